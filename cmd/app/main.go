@@ -38,31 +38,250 @@ func main() {
 	}
 	defer app.Close()
 
-	taskHandler := handlers.NewTaskHandler(app.TaskService)
-
-	setupRoutes(app.Router, taskHandler)
+	setupRoutes(app.Router, routeHandlers{
+		task:         handlers.NewTaskHandler(app.TaskService),
+		user:         handlers.NewUserHandler(app.UserService),
+		comment:      handlers.NewCommentHandler(app.CommentService),
+		attachment:   handlers.NewAttachmentHandler(app.AttachmentService),
+		link:         handlers.NewLinkHandler(app.LinkService),
+		label:        handlers.NewLabelHandler(app.LabelService),
+		project:      handlers.NewProjectHandler(app.ProjectService),
+		workspace:    handlers.NewWorkspaceHandler(app.WorkspaceService),
+		dependency:   handlers.NewDependencyHandler(app.DependencyService),
+		customField:  handlers.NewCustomFieldHandler(app.CustomFieldService),
+		template:     handlers.NewTemplateHandler(app.TemplateService),
+		timeEntry:    handlers.NewTimeEntryHandler(app.TimeEntryService),
+		report:       handlers.NewReportHandler(app.ReportService),
+		sprint:       handlers.NewSprintHandler(app.SprintService),
+		milestone:    handlers.NewMilestoneHandler(app.MilestoneService),
+		workflow:     handlers.NewWorkflowHandler(app.WorkflowService),
+		activity:     handlers.NewActivityHandler(app.ActivityService),
+		revision:     handlers.NewRevisionHandler(app.RevisionService),
+		notification: handlers.NewNotificationHandler(app.NotificationService),
+		email:        handlers.NewEmailHandler(app.EmailService),
+	})
 
 	app.Router.GET("/swagger/*any", gin.WrapH(httpSwagger.Handler()))
 
 	app.Run()
 }
 
-func setupRoutes(router *gin.Engine, taskHandler *handlers.TaskHandler) {
+// routeHandlers groups the HTTP handlers wired into the router
+type routeHandlers struct {
+	task         *handlers.TaskHandler
+	user         *handlers.UserHandler
+	comment      *handlers.CommentHandler
+	attachment   *handlers.AttachmentHandler
+	link         *handlers.LinkHandler
+	label        *handlers.LabelHandler
+	project      *handlers.ProjectHandler
+	workspace    *handlers.WorkspaceHandler
+	dependency   *handlers.DependencyHandler
+	customField  *handlers.CustomFieldHandler
+	template     *handlers.TemplateHandler
+	timeEntry    *handlers.TimeEntryHandler
+	report       *handlers.ReportHandler
+	sprint       *handlers.SprintHandler
+	milestone    *handlers.MilestoneHandler
+	workflow     *handlers.WorkflowHandler
+	activity     *handlers.ActivityHandler
+	revision     *handlers.RevisionHandler
+	notification *handlers.NotificationHandler
+	email        *handlers.EmailHandler
+}
+
+func setupRoutes(router *gin.Engine, h routeHandlers) {
 	v1 := router.Group("/api/v1")
 	{
-		tasks := v1.Group("/tasks")
+		workspaces := v1.Group("/workspaces")
+		{
+			workspaces.POST("", h.workspace.CreateWorkspace)
+			workspaces.GET("", h.workspace.GetWorkspaces)
+			workspaces.GET("/:id", h.workspace.GetWorkspace)
+			workspaces.PUT("/:id", h.workspace.UpdateWorkspace)
+			workspaces.DELETE("/:id", h.workspace.DeleteWorkspace)
+		}
+
+		// Task data is isolated per workspace, selected by the X-Workspace-ID header
+		requireWorkspace := h.workspace.RequireWorkspace()
+
+		// Changes to tasks are attributed to the user named by the optional X-User-ID header
+		identifyActor := h.user.IdentifyActor()
+
+		tasks := v1.Group("/tasks", requireWorkspace, identifyActor)
+		{
+			tasks.POST("", h.task.CreateTask)
+			tasks.GET("", h.task.GetTasks)
+			tasks.GET("/:id", h.task.GetTask)
+			tasks.PUT("/:id", h.task.UpdateTask)
+			tasks.DELETE("/:id", h.task.DeleteTask)
+			tasks.POST("/:id/restore", h.task.RestoreTask)
+			tasks.GET("/:id/activity", h.activity.GetTaskActivity)
+
+			tasks.GET("/:id/revisions", h.revision.GetRevisions)
+			tasks.GET("/:id/revisions/:rev", h.revision.GetRevision)
+			tasks.GET("/:id/revisions/:rev/diff", h.revision.DiffRevisions)
+			tasks.POST("/:id/revisions/:rev/restore", h.revision.RestoreRevision)
+
+			tasks.POST("/from-template/:template_id", h.template.CreateTaskFromTemplate)
+			tasks.POST("/:id/template", h.template.SaveTaskAsTemplate)
+
+			tasks.POST("/:id/users/:user_id", h.task.AssignUser)
+			tasks.DELETE("/:id/users/:user_id", h.task.UnassignUser)
+
+			tasks.GET("/:id/watchers", h.notification.GetWatchers)
+			tasks.POST("/:id/watchers/:user_id", h.notification.Watch)
+			tasks.DELETE("/:id/watchers/:user_id", h.notification.Unwatch)
+
+			tasks.POST("/:id/labels/:label_id", h.label.AssignLabel)
+			tasks.DELETE("/:id/labels/:label_id", h.label.UnassignLabel)
+
+			tasks.GET("/:id/dependencies", h.dependency.GetDependencies)
+			tasks.POST("/:id/dependencies/:blocker_id", h.dependency.AddDependency)
+			tasks.DELETE("/:id/dependencies/:blocker_id", h.dependency.RemoveDependency)
+
+			tasks.POST("/:id/subtasks", h.task.CreateSubTask)
+			tasks.GET("/:id/subtasks", h.task.GetSubTasksByTaskID)
+			tasks.POST("/:id/subtasks/:subtask_id/reorder", h.task.ReorderSubTask)
+			tasks.PUT("/:id/subtasks/:subtask_id", h.task.UpdateSubTask)
+			tasks.DELETE("/:id/subtasks/:subtask_id", h.task.DeleteSubTask)
+			tasks.POST("/:id/subtasks/:subtask_id/restore", h.task.RestoreSubTask)
+
+			tasks.POST("/:id/comments", h.comment.CreateComment)
+			tasks.GET("/:id/comments", h.comment.GetComments)
+			tasks.PUT("/:id/comments/:comment_id", h.comment.UpdateComment)
+			tasks.DELETE("/:id/comments/:comment_id", h.comment.DeleteComment)
+
+			tasks.POST("/:id/attachments", h.attachment.UploadAttachment)
+			tasks.GET("/:id/attachments", h.attachment.GetAttachments)
+			tasks.GET("/:id/attachments/:attachment_id", h.attachment.DownloadAttachment)
+			tasks.DELETE("/:id/attachments/:attachment_id", h.attachment.DeleteAttachment)
+
+			tasks.POST("/:id/links", h.link.CreateLink)
+			tasks.GET("/:id/links", h.link.GetLinks)
+			tasks.PUT("/:id/links/:link_id", h.link.UpdateLink)
+			tasks.DELETE("/:id/links/:link_id", h.link.DeleteLink)
+
+			tasks.POST("/:id/timer/start", h.timeEntry.StartTimer)
+			tasks.POST("/:id/timer/stop", h.timeEntry.StopTimer)
+			tasks.POST("/:id/time-entries", h.timeEntry.CreateTimeEntry)
+			tasks.GET("/:id/time-entries", h.timeEntry.GetTimeEntries)
+			tasks.GET("/:id/time-entries/totals", h.timeEntry.GetTaskTotals)
+			tasks.PUT("/:id/time-entries/:entry_id", h.timeEntry.UpdateTimeEntry)
+			tasks.DELETE("/:id/time-entries/:entry_id", h.timeEntry.DeleteTimeEntry)
+		}
+
+		trash := v1.Group("/trash", requireWorkspace)
+		{
+			trash.GET("", h.task.GetTrash)
+		}
+
+		activity := v1.Group("/activity", requireWorkspace)
+		{
+			activity.GET("", h.activity.GetActivities)
+		}
+
+		// The inbox belongs to the user named by the required X-User-ID header
+		notifications := v1.Group("/notifications", requireWorkspace, h.user.RequireUser())
+		{
+			notifications.GET("", h.notification.GetNotifications)
+			notifications.GET("/unread-count", h.notification.GetUnreadCount)
+			notifications.POST("/read-all", h.notification.MarkAllRead)
+			notifications.POST("/:id/read", h.notification.MarkRead)
+			notifications.GET("/settings", h.notification.GetSettings)
+			notifications.PUT("/settings", h.notification.UpdateSettings)
+		}
+
+		emails := v1.Group("/email-deliveries", requireWorkspace)
+		{
+			emails.GET("", h.email.GetDeliveries)
+			emails.GET("/:id", h.email.GetDelivery)
+			emails.POST("/:id/retry", h.email.RetryDelivery)
+		}
+
+		users := v1.Group("/users")
+		{
+			users.POST("", h.user.CreateUser)
+			users.GET("", h.user.GetUsers)
+			users.GET("/:id", h.user.GetUser)
+			users.PUT("/:id", h.user.UpdateUser)
+			users.DELETE("/:id", h.user.DeleteUser)
+			users.GET("/:id/email-settings", h.email.GetSettings)
+			users.PUT("/:id/email-settings", h.email.UpdateSettings)
+
+			// Time tracking is per workspace even though users are not
+			users.GET("/:id/timer", requireWorkspace, h.timeEntry.GetRunningTimer)
+			users.GET("/:id/time-entries/totals", requireWorkspace, h.timeEntry.GetUserTotals)
+		}
+
+		projects := v1.Group("/projects", requireWorkspace)
+		{
+			projects.POST("", h.project.CreateProject)
+			projects.GET("", h.project.GetProjects)
+			projects.GET("/:id", h.project.GetProject)
+			projects.PUT("/:id", h.project.UpdateProject)
+			projects.DELETE("/:id", h.project.DeleteProject)
+			projects.GET("/:id/tasks", h.project.GetProjectTasks)
+		}
+
+		sprints := v1.Group("/sprints", requireWorkspace)
+		{
+			sprints.POST("", h.sprint.CreateSprint)
+			sprints.GET("", h.sprint.GetSprints)
+			sprints.GET("/:id", h.sprint.GetSprint)
+			sprints.PUT("/:id", h.sprint.UpdateSprint)
+			sprints.DELETE("/:id", h.sprint.DeleteSprint)
+			sprints.POST("/:id/start", h.sprint.StartSprint)
+			sprints.POST("/:id/close", identifyActor, h.sprint.CloseSprint)
+			sprints.GET("/:id/tasks", h.sprint.GetSprintTasks)
+		}
+
+		milestones := v1.Group("/milestones", requireWorkspace)
+		{
+			milestones.POST("", h.milestone.CreateMilestone)
+			milestones.GET("", h.milestone.GetMilestones)
+			milestones.GET("/:id", h.milestone.GetMilestone)
+			milestones.PUT("/:id", h.milestone.UpdateMilestone)
+			milestones.DELETE("/:id", h.milestone.DeleteMilestone)
+			milestones.GET("/:id/tasks", h.milestone.GetMilestoneTasks)
+		}
+
+		labels := v1.Group("/labels", requireWorkspace)
+		{
+			labels.POST("", h.label.CreateLabel)
+			labels.GET("", h.label.GetLabels)
+			labels.GET("/:id", h.label.GetLabel)
+			labels.PUT("/:id", h.label.UpdateLabel)
+			labels.DELETE("/:id", h.label.DeleteLabel)
+		}
+
+		workflow := v1.Group("/workflow", requireWorkspace)
+		{
+			workflow.GET("", h.workflow.GetWorkflow)
+			workflow.PUT("", h.workflow.UpdateWorkflow)
+		}
+
+		customFields := v1.Group("/custom-fields", requireWorkspace)
+		{
+			customFields.POST("", h.customField.CreateCustomField)
+			customFields.GET("", h.customField.GetCustomFields)
+			customFields.GET("/:id", h.customField.GetCustomField)
+			customFields.PUT("/:id", h.customField.UpdateCustomField)
+			customFields.DELETE("/:id", h.customField.DeleteCustomField)
+		}
+
+		templates := v1.Group("/templates", requireWorkspace)
+		{
+			templates.POST("", h.template.CreateTemplate)
+			templates.GET("", h.template.GetTemplates)
+			templates.GET("/:id", h.template.GetTemplate)
+			templates.PUT("/:id", h.template.UpdateTemplate)
+			templates.DELETE("/:id", h.template.DeleteTemplate)
+		}
+
+		reports := v1.Group("/reports", requireWorkspace)
 		{
-			tasks.POST("", taskHandler.CreateTask)
-			tasks.GET("", taskHandler.GetTasks)
-			tasks.GET("/:id", taskHandler.GetTask)
-			tasks.PUT("/:id", taskHandler.UpdateTask)
-			tasks.DELETE("/:id", taskHandler.DeleteTask)
-
-			tasks.POST("/:id/subtasks", taskHandler.CreateSubTask)
-			tasks.GET("/:id/subtasks", taskHandler.GetSubTasksByTaskID)
-			tasks.POST("/:id/subtasks/:subtask_id/reorder", taskHandler.ReorderSubTask)
-			tasks.PUT("/:id/subtasks/:subtask_id", taskHandler.UpdateSubTask)
-			tasks.DELETE("/:id/subtasks/:subtask_id", taskHandler.DeleteSubTask)
+			reports.GET("/burndown", h.report.GetBurndown)
 		}
 	}
 }
@@ -72,37 +72,404 @@ func (c *DatabaseConfig) ConnectDB() (*sql.DB, error) {
 
 func CreateTables(db *sql.DB) error {
 	queries := []string{
+		`CREATE TABLE IF NOT EXISTS workspaces (
+			id VARCHAR(255) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`INSERT INTO workspaces (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING`,
+		`CREATE TABLE IF NOT EXISTS projects (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE projects ADD COLUMN IF NOT EXISTS workspace_id VARCHAR(255) NOT NULL DEFAULT 'default' REFERENCES workspaces(id) ON DELETE CASCADE`,
+		`ALTER TABLE projects ALTER COLUMN workspace_id DROP DEFAULT`,
 		`CREATE TABLE IF NOT EXISTS tasks (
 			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id),
+			project_id VARCHAR(255) REFERENCES projects(id) ON DELETE SET NULL,
 			title VARCHAR(255) NOT NULL,
 			icon_name VARCHAR(100) NOT NULL,
-			start_time VARCHAR(50),
-			end_time VARCHAR(50),
+			start_time TIMESTAMP,
+			end_time TIMESTAMP,
 			due_date TIMESTAMP NOT NULL,
 			progress INTEGER DEFAULT 0,
-			status VARCHAR(50) NOT NULL CHECK (status IN ('not-started', 'completed', 'in-progress')),
-			comments INTEGER DEFAULT 0,
-			attachments INTEGER DEFAULT 0,
-			links INTEGER DEFAULT 0,
+			status VARCHAR(50) NOT NULL,
+			priority VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id VARCHAR(255) REFERENCES projects(id) ON DELETE SET NULL`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS priority VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent'))`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id VARCHAR(255) NOT NULL DEFAULT 'default' REFERENCES workspaces(id)`,
+		`ALTER TABLE tasks ALTER COLUMN workspace_id DROP DEFAULT`,
+		`CREATE TABLE IF NOT EXISTS task_series (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			rrule TEXT NOT NULL,
+			start_date TIMESTAMP NOT NULL,
+			occurrences INTEGER NOT NULL DEFAULT 1,
+			current_task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE SET NULL,
+			active BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id VARCHAR(255) REFERENCES task_series(id) ON DELETE SET NULL`,
+		`CREATE TABLE IF NOT EXISTS custom_fields (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			key VARCHAR(50) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'date', 'single_select', 'multi_select', 'user')),
+			options JSONB NOT NULL DEFAULT '[]',
+			required BOOLEAN NOT NULL DEFAULT FALSE,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(workspace_id, key)
+		)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS custom_fields JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate_hours DOUBLE PRECISION CHECK (estimate_hours >= 0)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS story_points INTEGER CHECK (story_points >= 0)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS remaining_hours DOUBLE PRECISION CHECK (remaining_hours >= 0)`,
+		`CREATE TABLE IF NOT EXISTS sprints (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			goal TEXT,
+			start_date TIMESTAMP NOT NULL,
+			end_date TIMESTAMP NOT NULL,
+			state VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (state IN ('planned', 'active', 'closed')),
+			started_at TIMESTAMP,
+			closed_at TIMESTAMP,
+			carried_over INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			CHECK (end_date > start_date)
+		)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id VARCHAR(255) REFERENCES sprints(id) ON DELETE SET NULL`,
+		`CREATE TABLE IF NOT EXISTS milestones (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			description TEXT,
+			due_date TIMESTAMP NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_id VARCHAR(255) REFERENCES milestones(id) ON DELETE SET NULL`,
+		`CREATE TABLE IF NOT EXISTS task_templates (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			name VARCHAR(100) NOT NULL,
+			title VARCHAR(255) NOT NULL,
+			icon_name VARCHAR(100) NOT NULL,
+			project_id VARCHAR(255) REFERENCES projects(id) ON DELETE SET NULL,
+			priority VARCHAR(20) NOT NULL DEFAULT 'none' CHECK (priority IN ('none', 'low', 'medium', 'high', 'urgent')),
+			due_in_days INTEGER CHECK (due_in_days >= 0),
+			custom_fields JSONB NOT NULL DEFAULT '{}',
+			subtasks JSONB NOT NULL DEFAULT '[]',
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP DEFAULT NOW()
 		)`,
 		`CREATE TABLE IF NOT EXISTS sub_tasks (
 			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id),
 			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
 			title VARCHAR(255) NOT NULL,
 			description TEXT,
-			status VARCHAR(50) NOT NULL CHECK (status IN ('not-started', 'completed', 'in-progress')),
+			status VARCHAR(50) NOT NULL,
 			"order" INTEGER NOT NULL DEFAULT 0,
 			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		// Orders are unique per parent, not per task; writers keep them so by locking the task row
+		`ALTER TABLE sub_tasks DROP CONSTRAINT IF EXISTS sub_tasks_task_id_order_key`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS parent_subtask_id VARCHAR(255) REFERENCES sub_tasks(id) ON DELETE CASCADE`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS workspace_id VARCHAR(255) NOT NULL DEFAULT 'default' REFERENCES workspaces(id)`,
+		`ALTER TABLE sub_tasks ALTER COLUMN workspace_id DROP DEFAULT`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS estimate_hours DOUBLE PRECISION CHECK (estimate_hours >= 0)`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS story_points INTEGER CHECK (story_points >= 0)`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS remaining_hours DOUBLE PRECISION CHECK (remaining_hours >= 0)`,
+		`CREATE TABLE IF NOT EXISTS workflow_statuses (
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			key VARCHAR(50) NOT NULL,
+			name VARCHAR(100) NOT NULL,
+			category VARCHAR(20) NOT NULL CHECK (category IN ('todo', 'doing', 'done')),
+			position INTEGER NOT NULL DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			PRIMARY KEY (workspace_id, key)
+		)`,
+		`CREATE TABLE IF NOT EXISTS workflow_transitions (
+			workspace_id VARCHAR(255) NOT NULL,
+			from_status VARCHAR(50) NOT NULL,
+			to_status VARCHAR(50) NOT NULL,
+			PRIMARY KEY (workspace_id, from_status, to_status),
+			FOREIGN KEY (workspace_id, from_status) REFERENCES workflow_statuses(workspace_id, key) ON DELETE CASCADE,
+			FOREIGN KEY (workspace_id, to_status) REFERENCES workflow_statuses(workspace_id, key) ON DELETE CASCADE,
+			CHECK (from_status <> to_status)
+		)`,
+		// Workspaces from before configurable workflows get the three built-in statuses
+		`INSERT INTO workflow_statuses (workspace_id, key, name, category, position)
+		SELECT w.id, s.key, s.name, s.category, s.position
+		FROM workspaces w
+		CROSS JOIN (VALUES
+			('not-started', 'Not started', 'todo', 0),
+			('in-progress', 'In progress', 'doing', 1),
+			('completed', 'Completed', 'done', 2)
+		) AS s(key, name, category, position)
+		WHERE NOT EXISTS (SELECT 1 FROM workflow_statuses ws WHERE ws.workspace_id = w.id)`,
+		// Statuses are checked against the workspace's workflow instead of a fixed list
+		`ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check`,
+		`ALTER TABLE sub_tasks DROP CONSTRAINT IF EXISTS sub_tasks_status_check`,
+		`DO $$ BEGIN
+			ALTER TABLE tasks ADD CONSTRAINT tasks_workflow_status_fkey
+				FOREIGN KEY (workspace_id, status) REFERENCES workflow_statuses(workspace_id, key);
+		EXCEPTION WHEN duplicate_object THEN NULL;
+		END $$`,
+		`DO $$ BEGIN
+			ALTER TABLE sub_tasks ADD CONSTRAINT sub_tasks_workflow_status_fkey
+				FOREIGN KEY (workspace_id, status) REFERENCES workflow_statuses(workspace_id, key);
+		EXCEPTION WHEN duplicate_object THEN NULL;
+		END $$`,
+		`CREATE TABLE IF NOT EXISTS users (
+			id VARCHAR(255) PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			src TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE users ADD COLUMN IF NOT EXISTS email VARCHAR(255) NOT NULL DEFAULT ''`,
+		`CREATE TABLE IF NOT EXISTS task_user_assignments (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			assigned_at TIMESTAMP DEFAULT NOW(),
+			PRIMARY KEY (task_id, user_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS comments (
+			id VARCHAR(255) PRIMARY KEY,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			parent_id VARCHAR(255) REFERENCES comments(id) ON DELETE CASCADE,
+			author_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+			body TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			edited_at TIMESTAMP
+		)`,
+		`ALTER TABLE tasks DROP COLUMN IF EXISTS comments`,
+		`CREATE TABLE IF NOT EXISTS attachments (
+			id VARCHAR(255) PRIMARY KEY,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			filename VARCHAR(255) NOT NULL,
+			size BIGINT NOT NULL,
+			mime_type VARCHAR(255) NOT NULL,
+			checksum CHAR(64) NOT NULL,
+			storage_key VARCHAR(512) NOT NULL UNIQUE,
+			uploaded_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+			created_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE tasks DROP COLUMN IF EXISTS attachments`,
+		`CREATE TABLE IF NOT EXISTS task_links (
+			id VARCHAR(255) PRIMARY KEY,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			title VARCHAR(255) NOT NULL,
+			description TEXT,
+			kind VARCHAR(20) NOT NULL DEFAULT 'other' CHECK (kind IN ('doc', 'design', 'pr', 'issue', 'other')),
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(task_id, url)
+		)`,
+		`ALTER TABLE tasks DROP COLUMN IF EXISTS links`,
+		`CREATE TABLE IF NOT EXISTS labels (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			name VARCHAR(50) NOT NULL,
+			color CHAR(7) NOT NULL DEFAULT '#6b7280',
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		)`,
+		`ALTER TABLE labels ADD COLUMN IF NOT EXISTS workspace_id VARCHAR(255) NOT NULL DEFAULT 'default' REFERENCES workspaces(id) ON DELETE CASCADE`,
+		`ALTER TABLE labels ALTER COLUMN workspace_id DROP DEFAULT`,
+		`DROP INDEX IF EXISTS idx_labels_name_lower`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_workspace_name_lower ON labels(workspace_id, lower(name))`,
+		`CREATE TABLE IF NOT EXISTS task_labels (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			label_id VARCHAR(255) NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+			PRIMARY KEY (task_id, label_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_template_labels (
+			template_id VARCHAR(255) NOT NULL REFERENCES task_templates(id) ON DELETE CASCADE,
+			label_id VARCHAR(255) NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+			PRIMARY KEY (template_id, label_id)
+		)`,
+		`CREATE TABLE IF NOT EXISTS time_entries (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			subtask_id VARCHAR(255) REFERENCES sub_tasks(id) ON DELETE SET NULL,
+			started_at TIMESTAMPTZ NOT NULL,
+			ended_at TIMESTAMPTZ,
+			note TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP DEFAULT NOW(),
-			UNIQUE(task_id, "order")
+			CHECK (ended_at IS NULL OR ended_at >= started_at)
 		)`,
+		`CREATE TABLE IF NOT EXISTS task_effort_snapshots (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			remaining_hours DOUBLE PRECISION NOT NULL,
+			remaining_points INTEGER NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			blocked_by_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			created_at TIMESTAMP DEFAULT NOW(),
+			PRIMARY KEY (task_id, blocked_by_id),
+			CHECK (task_id <> blocked_by_id)
+		)`,
+		// The audit log outlives purged tasks, so its task and subtask ids are not foreign keys
+		`CREATE TABLE IF NOT EXISTS task_activity (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			task_id VARCHAR(255) NOT NULL,
+			subtask_id VARCHAR(255),
+			entity VARCHAR(20) NOT NULL CHECK (entity IN ('task', 'subtask')),
+			action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored', 'reordered')),
+			actor_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+			changes JSONB NOT NULL DEFAULT '[]',
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_revisions (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			revision INTEGER NOT NULL CHECK (revision > 0),
+			actor_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+			restored_from INTEGER,
+			snapshot JSONB NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (task_id, revision)
+		)`,
+		`CREATE TABLE IF NOT EXISTS task_watchers (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (task_id, user_id)
+		)`,
+		// Assignees watch their tasks; assignments made before watchers existed are carried over
+		`INSERT INTO task_watchers (task_id, user_id, workspace_id, created_at)
+			SELECT tua.task_id, tua.user_id, t.workspace_id, tua.assigned_at
+			FROM task_user_assignments tua JOIN tasks t ON t.id = tua.task_id
+			WHERE NOT EXISTS (SELECT 1 FROM task_watchers)
+			ON CONFLICT DO NOTHING`,
+		`CREATE TABLE IF NOT EXISTS notifications (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			subtask_id VARCHAR(255),
+			comment_id VARCHAR(255) REFERENCES comments(id) ON DELETE CASCADE,
+			type VARCHAR(30) NOT NULL,
+			action VARCHAR(20) NOT NULL DEFAULT '',
+			actor_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+			changes JSONB NOT NULL DEFAULT '[]',
+			read_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`,
+		`CREATE TABLE IF NOT EXISTS notification_mutes (
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			type VARCHAR(30) NOT NULL,
+			PRIMARY KEY (workspace_id, user_id, type)
+		)`,
+		`ALTER TABLE notifications ADD COLUMN IF NOT EXISTS due_date TIMESTAMP`,
+		// A row per reminder sent; the key makes each reminder of a due date go out once across replicas
+		`CREATE TABLE IF NOT EXISTS task_reminders (
+			task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			due_date TIMESTAMP NOT NULL,
+			offset_minutes INTEGER NOT NULL,
+			sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			PRIMARY KEY (task_id, due_date, offset_minutes)
+		)`,
+		`CREATE TABLE IF NOT EXISTS email_opt_outs (
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(20) NOT NULL,
+			PRIMARY KEY (user_id, kind)
+		)`,
+		// The delivery log; the unique keys make each notification and daily digest go out once across replicas
+		`CREATE TABLE IF NOT EXISTS email_deliveries (
+			id VARCHAR(255) PRIMARY KEY,
+			workspace_id VARCHAR(255) NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+			user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			kind VARCHAR(20) NOT NULL,
+			notification_id VARCHAR(255) UNIQUE REFERENCES notifications(id) ON DELETE CASCADE,
+			digest_date DATE,
+			to_address VARCHAR(255) NOT NULL DEFAULT '',
+			subject TEXT NOT NULL DEFAULT '',
+			status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'sent', 'failed', 'skipped')),
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT '',
+			next_attempt_at TIMESTAMPTZ,
+			sent_at TIMESTAMPTZ,
+			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+			UNIQUE (workspace_id, user_id, digest_date)
+		)`,
+		// Deleted tasks and subtasks stay in the trash until they are restored or purged
+		`ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
+		`ALTER TABLE sub_tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP`,
 		`CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date)`,
 		`CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_priority ON tasks(priority)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_project_status ON tasks(project_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_workspace_status ON tasks(workspace_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_projects_workspace_id ON projects(workspace_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_series_active ON task_series(current_task_id) WHERE active`,
 		`CREATE INDEX IF NOT EXISTS idx_sub_tasks_task_id ON sub_tasks(task_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_sub_tasks_order ON sub_tasks("order")`,
 		`CREATE INDEX IF NOT EXISTS idx_sub_tasks_task_order ON sub_tasks(task_id, "order")`,
+		`CREATE INDEX IF NOT EXISTS idx_sub_tasks_parent_id ON sub_tasks(parent_subtask_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_custom_fields ON tasks USING GIN (custom_fields)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_user_assignments_user_id ON task_user_assignments(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_comments_task_created ON comments(task_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments(parent_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels(label_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by_id ON task_dependencies(blocked_by_id)`,
+		// A user can only have one running timer; the index enforces it across replicas
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_task_started ON time_entries(task_id, started_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_effort_snapshots_task_recorded ON task_effort_snapshots(task_id, recorded_at)`,
+		// Only one sprint per project (or per workspace for sprints without a project) can be active
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_one_active ON sprints(workspace_id, COALESCE(project_id, '')) WHERE state = 'active'`,
+		`CREATE INDEX IF NOT EXISTS idx_sprints_workspace_start ON sprints(workspace_id, start_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_milestones_workspace_due ON milestones(workspace_id, due_date)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks(sprint_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_milestone_id ON tasks(milestone_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(workspace_id, deleted_at) WHERE deleted_at IS NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_sub_tasks_deleted_at ON sub_tasks(workspace_id, deleted_at) WHERE deleted_at IS NOT NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_task_activity_task_created ON task_activity(task_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_activity_workspace_created ON task_activity(workspace_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_activity_changes ON task_activity USING GIN (changes jsonb_path_ops)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_watchers_user_id ON task_watchers(user_id)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(workspace_id, user_id, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(workspace_id, user_id) WHERE read_at IS NULL`,
+		`CREATE INDEX IF NOT EXISTS idx_notifications_type_created ON notifications(type, created_at)`,
+		`CREATE INDEX IF NOT EXISTS idx_email_deliveries_pending ON email_deliveries(next_attempt_at) WHERE status = 'pending'`,
+		`CREATE INDEX IF NOT EXISTS idx_email_deliveries_workspace_created ON email_deliveries(workspace_id, created_at)`,
 	}
 
 	for _, query := range queries {
@@ -111,6 +478,10 @@ func CreateTables(db *sql.DB) error {
 		}
 	}
 
+	if err := migrateTaskSchedule(db); err != nil {
+		return fmt.Errorf("error migrating task schedule: %w", err)
+	}
+
 	log.Println("Successfully created tables")
 	return nil
 }
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/env"
+	"github.com/Sasha125588/event_app/internal/mail"
+)
+
+type EmailConfig struct {
+	// SMTP is the server email notifications are sent through; without a host no email is sent
+	SMTP mail.SMTPConfig
+	// AppURL is the address of the web app that links in emails point to
+	AppURL string
+	// DigestHour is the hour of the day, in server time, daily digests are sent at; negative disables them
+	DigestHour int
+	// DeliveryInterval is how often queued emails are sent
+	DeliveryInterval time.Duration
+	// MaxAttempts is how many times an email is tried before it is marked as failed
+	MaxAttempts int
+}
+
+func NewEmailConfig() *EmailConfig {
+	return &EmailConfig{
+		SMTP: mail.SMTPConfig{
+			Host:        env.GetEnvString("SMTP_HOST", ""),
+			Port:        env.GetEnvInt("SMTP_PORT", 587),
+			Username:    env.GetEnvString("SMTP_USERNAME", ""),
+			Password:    env.GetEnvString("SMTP_PASSWORD", ""),
+			From:        env.GetEnvString("SMTP_FROM", "Task Hub <noreply@localhost>"),
+			ImplicitTLS: env.GetEnvBool("SMTP_IMPLICIT_TLS", false),
+		},
+		AppURL:           env.GetEnvString("EMAIL_APP_URL", "http://localhost:3000"),
+		DigestHour:       env.GetEnvInt("EMAIL_DIGEST_HOUR", 8),
+		DeliveryInterval: time.Duration(env.GetEnvInt("EMAIL_DELIVERY_INTERVAL_SECONDS", 30)) * time.Second,
+		MaxAttempts:      env.GetEnvInt("EMAIL_MAX_ATTEMPTS", 5),
+	}
+}
+
+// OpenSender returns the sender for the configured SMTP server, or nil when no server is configured
+func (c *EmailConfig) OpenSender() (mail.Sender, error) {
+	if c.SMTP.Host == "" {
+		return nil, nil
+	}
+	sender, err := mail.NewSMTPSender(c.SMTP)
+	if err != nil {
+		return nil, err
+	}
+	return sender, nil
+}
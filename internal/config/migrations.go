@@ -0,0 +1,157 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// legacyTimestampLayouts are the full timestamps found in the old free-form start_time/end_time columns
+var legacyTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// legacyClockLayouts are the times of day found in the old columns, after lower-casing and removing spaces
+var legacyClockLayouts = []string{
+	"15:04",
+	"15:04:05",
+	"3pm",
+	"3:04pm",
+	"15.04",
+}
+
+// migrateTaskSchedule converts the legacy VARCHAR start_time/end_time columns of tasks into timestamps.
+// Times of day such as "9am" or "09:00" are placed on the date of the task's due date; values that
+// cannot be parsed are logged and dropped. The conversion runs once, under a table lock, so replicas
+// starting at the same time do not race
+func migrateTaskSchedule(db *sql.DB) error {
+	legacy, err := taskScheduleIsLegacy(db)
+	if err != nil || !legacy {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("LOCK TABLE tasks IN ACCESS EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+	// Another replica may have finished the migration while we waited for the lock
+	if legacy, err = taskScheduleIsLegacy(tx); err != nil || !legacy {
+		return err
+	}
+
+	type schedule struct {
+		id         string
+		start, end *time.Time
+	}
+
+	rows, err := tx.Query("SELECT id, start_time, end_time, due_date FROM tasks WHERE start_time IS NOT NULL OR end_time IS NOT NULL")
+	if err != nil {
+		return err
+	}
+	var schedules []schedule
+	for rows.Next() {
+		var id string
+		var start, end sql.NullString
+		var due time.Time
+		if err := rows.Scan(&id, &start, &end, &due); err != nil {
+			rows.Close()
+			return err
+		}
+		item := schedule{id: id}
+		if item.start, err = parseLegacyTime(start.String, due); err != nil {
+			log.Printf("Warning: dropping start_time %q of task %s: %v", start.String, id, err)
+		}
+		if item.end, err = parseLegacyTime(end.String, due); err != nil {
+			log.Printf("Warning: dropping end_time %q of task %s: %v", end.String, id, err)
+		}
+		schedules = append(schedules, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("ALTER TABLE tasks ADD COLUMN start_time_new TIMESTAMP, ADD COLUMN end_time_new TIMESTAMP"); err != nil {
+		return err
+	}
+	for _, item := range schedules {
+		if item.start == nil && item.end == nil {
+			continue
+		}
+		_, err := tx.Exec("UPDATE tasks SET start_time_new = $1, end_time_new = $2 WHERE id = $3", item.start, item.end, item.id)
+		if err != nil {
+			return fmt.Errorf("failed to migrate schedule of task %s: %w", item.id, err)
+		}
+	}
+
+	queries := []string{
+		`ALTER TABLE tasks DROP COLUMN start_time, DROP COLUMN end_time`,
+		`ALTER TABLE tasks RENAME COLUMN start_time_new TO start_time`,
+		`ALTER TABLE tasks RENAME COLUMN end_time_new TO end_time`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	log.Printf("Migrated start_time/end_time of %d tasks to timestamps", len(schedules))
+	return nil
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// taskScheduleIsLegacy reports whether tasks.start_time is still a free-form string column
+func taskScheduleIsLegacy(db queryRower) (bool, error) {
+	var dataType string
+	err := db.QueryRow(`
+		SELECT data_type FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = 'tasks' AND column_name = 'start_time'`).Scan(&dataType)
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect tasks.start_time: %w", err)
+	}
+	return dataType == "character varying", nil
+}
+
+// parseLegacyTime parses a legacy start_time/end_time value. An empty value is no time
+func parseLegacyTime(value string, due time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range legacyTimestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t, nil
+		}
+	}
+
+	clock := strings.ReplaceAll(strings.ToLower(value), " ", "")
+	clock = strings.ReplaceAll(clock, ".m.", "m")
+	for _, layout := range legacyClockLayouts {
+		if t, err := time.Parse(layout, clock); err == nil {
+			at := time.Date(due.Year(), due.Month(), due.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+			return &at, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unrecognized time format")
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLegacyTime(t *testing.T) {
+	due := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	at := func(day int, hour int, min int, sec int) *time.Time {
+		v := time.Date(2024, time.March, day, hour, min, sec, 0, time.UTC)
+		return &v
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"blank", "   ", nil, false},
+		{"rfc3339", "2024-03-10T08:30:00Z", at(10, 8, 30, 0), false},
+		{"rfc3339 with offset", "2024-03-10T10:30:00+02:00", at(10, 8, 30, 0), false},
+		{"iso without zone", "2024-03-10T08:30:00", at(10, 8, 30, 0), false},
+		{"iso without seconds", "2024-03-10T08:30", at(10, 8, 30, 0), false},
+		{"space separated", "2024-03-10 08:30:45", at(10, 8, 30, 45), false},
+		{"space separated without seconds", "2024-03-10 08:30", at(10, 8, 30, 0), false},
+		{"24-hour clock", "09:00", at(15, 9, 0, 0), false},
+		{"24-hour clock with seconds", "17:45:30", at(15, 17, 45, 30), false},
+		{"dotted clock", "14.15", at(15, 14, 15, 0), false},
+		{"am", "9am", at(15, 9, 0, 0), false},
+		{"pm with minutes", "3:30pm", at(15, 15, 30, 0), false},
+		{"upper case with spaces", " 9 AM ", at(15, 9, 0, 0), false},
+		{"dotted am", "9 a.m.", at(15, 9, 0, 0), false},
+		{"midnight", "12am", at(15, 0, 0, 0), false},
+		{"half past midnight", "12:30am", at(15, 0, 30, 0), false},
+		{"noon", "12pm", at(15, 12, 0, 0), false},
+		{"half past noon", "12:30pm", at(15, 12, 30, 0), false},
+		{"null literal", "null", nil, true},
+		{"words", "morning", nil, true},
+		{"out of range hour", "25:00", nil, true},
+		{"out of range am", "13am", nil, true},
+		{"date only", "2024-03-10", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLegacyTime(tt.value, due)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLegacyTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			switch {
+			case tt.want == nil && got != nil:
+				t.Errorf("parseLegacyTime(%q) = %v, want nil", tt.value, *got)
+			case tt.want != nil && (got == nil || !got.Equal(*tt.want)):
+				t.Errorf("parseLegacyTime(%q) = %v, want %v", tt.value, got, *tt.want)
+			}
+		})
+	}
+}
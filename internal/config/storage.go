@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/Sasha125588/event_app/internal/env"
+	"github.com/Sasha125588/event_app/internal/storage"
+)
+
+type StorageConfig struct {
+	Backend  string
+	LocalDir string
+	S3       storage.S3Config
+
+	MaxFileSize int64
+	MaxTaskSize int64
+}
+
+func NewStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Backend:  env.GetEnvString("STORAGE_BACKEND", "local"),
+		LocalDir: env.GetEnvString("STORAGE_LOCAL_DIR", "./uploads"),
+		S3: storage.S3Config{
+			Endpoint:     env.GetEnvString("S3_ENDPOINT", "http://localhost:9000"),
+			Region:       env.GetEnvString("S3_REGION", "us-east-1"),
+			Bucket:       env.GetEnvString("S3_BUCKET", "attachments"),
+			AccessKey:    env.GetEnvString("S3_ACCESS_KEY", ""),
+			SecretKey:    env.GetEnvString("S3_SECRET_KEY", ""),
+			UsePathStyle: env.GetEnvBool("S3_USE_PATH_STYLE", true),
+		},
+		MaxFileSize: int64(env.GetEnvInt("ATTACHMENT_MAX_FILE_SIZE", 25<<20)),
+		MaxTaskSize: int64(env.GetEnvInt("ATTACHMENT_MAX_TASK_SIZE", 100<<20)),
+	}
+}
+
+func (c *StorageConfig) OpenStorage() (storage.Storage, error) {
+	switch c.Backend {
+	case "local":
+		return storage.NewLocalStorage(c.LocalDir)
+	case "s3":
+		return storage.NewS3Storage(c.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", c.Backend)
+	}
+}
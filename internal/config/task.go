@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/env"
+)
+
+type TaskConfig struct {
+	// RejectBlockedCompletion forbids moving a task to a done status while any of its blockers is open
+	RejectBlockedCompletion bool
+	// RecurrenceInterval is how often overdue occurrences of recurring tasks are rolled over
+	RecurrenceInterval time.Duration
+	// TrashRetention is how long deleted tasks and subtasks can be restored before they are purged;
+	// zero keeps them forever
+	TrashRetention time.Duration
+	// PurgeInterval is how often the trash is checked for items past their retention
+	PurgeInterval time.Duration
+	// ReminderOffsets are how long before the due date of an open task its watchers are reminded
+	ReminderOffsets []time.Duration
+	// OverdueReminders reminds watchers once when an open task passes its due date
+	OverdueReminders bool
+	// ReminderInterval is how often due-date reminders are checked
+	ReminderInterval time.Duration
+}
+
+func NewTaskConfig() *TaskConfig {
+	return &TaskConfig{
+		RejectBlockedCompletion: env.GetEnvBool("TASK_REJECT_BLOCKED_COMPLETION", true),
+		RecurrenceInterval:      time.Duration(env.GetEnvInt("TASK_RECURRENCE_INTERVAL_SECONDS", 60)) * time.Second,
+		TrashRetention:          time.Duration(env.GetEnvInt("TASK_TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		PurgeInterval:           time.Duration(env.GetEnvInt("TASK_PURGE_INTERVAL_SECONDS", 3600)) * time.Second,
+		ReminderOffsets:         parseMinutes(env.GetEnvString("TASK_REMINDER_OFFSETS_MINUTES", "1440,60")),
+		OverdueReminders:        env.GetEnvBool("TASK_OVERDUE_REMINDERS", true),
+		ReminderInterval:        time.Duration(env.GetEnvInt("TASK_REMINDER_INTERVAL_SECONDS", 60)) * time.Second,
+	}
+}
+
+// parseMinutes parses a comma-separated list of minutes, skipping entries that are not positive numbers
+func parseMinutes(value string) []time.Duration {
+	var durations []time.Duration
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(item)
+		if err != nil || minutes <= 0 {
+			log.Printf("Warning: ignoring invalid reminder offset %q", item)
+			continue
+		}
+		durations = append(durations, time.Duration(minutes)*time.Minute)
+	}
+	return durations
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ActivityHandler struct {
+	activityService *service.ActivityService
+}
+
+func NewActivityHandler(activityService *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// GetTaskActivity handles GET /api/v1/tasks/:id/activity
+// @Summary Get task's activity
+// @Description Get the history of changes to a task and its subtasks, newest first.
+// @Description The history is kept after the task is deleted
+// @Tags activity
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param actor_id query string false "Filter by the user who made the change"
+// @Param entity query string false "Filter by entity: task, subtask"
+// @Param action query string false "Filter by action: created, updated, deleted, restored, reordered"
+// @Param field query string false "Only changes touching this field, e.g. status or cf.<key>"
+// @Param from query string false "Changes at or after this time (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Changes before this time; a date includes that whole day"
+// @Param limit query int false "Limit number of entries returned (default: 50, max: 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} models.ActivitiesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/activity [get]
+func (h *ActivityHandler) GetTaskActivity(c *gin.Context) {
+	var filters models.ActivityFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	activities, err := h.activityService.GetTaskActivity(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"activities": activities})
+}
+
+// GetActivities handles GET /api/v1/activity
+// @Summary Get the audit log
+// @Description Get changes to tasks and subtasks across the workspace, newest first
+// @Tags activity
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param task_id query string false "Filter by task ID"
+// @Param actor_id query string false "Filter by the user who made the change"
+// @Param entity query string false "Filter by entity: task, subtask"
+// @Param action query string false "Filter by action: created, updated, deleted, restored, reordered"
+// @Param field query string false "Only changes touching this field, e.g. status or cf.<key>"
+// @Param from query string false "Changes at or after this time (YYYY-MM-DD or RFC 3339)"
+// @Param to query string false "Changes before this time; a date includes that whole day"
+// @Param limit query int false "Limit number of entries returned (default: 50, max: 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} models.ActivitiesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /activity [get]
+func (h *ActivityHandler) GetActivities(c *gin.Context) {
+	var filters models.ActivityFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	activities, err := h.activityService.GetActivities(workspaceID(c), filters)
+	if err != nil {
+		writeServiceError(c, err, "Activity not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"activities": activities})
+}
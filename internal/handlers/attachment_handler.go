@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the allowance for multipart headers and boundaries on top of the file itself
+const multipartOverhead = 1 << 20
+
+type AttachmentHandler struct {
+	attachmentService *service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentService *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService}
+}
+
+// UploadAttachment handles POST /api/v1/tasks/:id/attachments
+// @Summary Upload an attachment
+// @Description Upload a file to a task as multipart/form-data. The MIME type is detected from the content
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param file formData file true "File to upload"
+// @Param uploaded_by formData string false "ID of the uploading user"
+// @Success 201 {object} models.Attachment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	taskID := c.Param("id")
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxFileSize()+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var uploadedBy *string
+	if value := c.PostForm("uploaded_by"); value != "" {
+		uploadedBy = &value
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(c.Request.Context(), workspaceID(c), taskID, uploadedBy, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+// GetAttachments handles GET /api/v1/tasks/:id/attachments
+// @Summary Get task's attachments
+// @Description Get metadata of all files attached to a task
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.AttachmentsResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachments(c *gin.Context) {
+	taskID := c.Param("id")
+
+	attachments, err := h.attachmentService.GetAttachments(workspaceID(c), taskID)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+}
+
+// DownloadAttachment handles GET /api/v1/tasks/:id/attachments/:attachment_id
+// @Summary Download an attachment
+// @Description Download the content of a file attached to a task
+// @Tags attachments
+// @Produce octet-stream
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	taskID := c.Param("id")
+	attachmentID := c.Param("attachment_id")
+
+	attachment, content, err := h.attachmentService.OpenAttachment(c.Request.Context(), workspaceID(c), taskID, attachmentID)
+	if err != nil {
+		writeServiceError(c, err, "Attachment not found in the specified task")
+		return
+	}
+	defer content.Close()
+
+	headers := map[string]string{
+		"Content-Disposition": mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}),
+		"ETag":                strconv.Quote(attachment.Checksum),
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.MimeType, content, headers)
+}
+
+// DeleteAttachment handles DELETE /api/v1/tasks/:id/attachments/:attachment_id
+// @Summary Delete an attachment
+// @Description Delete a file attached to a task
+// @Tags attachments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param attachment_id path string true "Attachment ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	taskID := c.Param("id")
+	attachmentID := c.Param("attachment_id")
+
+	if err := h.attachmentService.DeleteAttachment(c.Request.Context(), workspaceID(c), taskID, attachmentID); err != nil {
+		writeServiceError(c, err, "Attachment not found in the specified task")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService *service.CommentService
+}
+
+func NewCommentHandler(commentService *service.CommentService) *CommentHandler {
+	return &CommentHandler{commentService: commentService}
+}
+
+// CreateComment handles POST /api/v1/tasks/:id/comments
+// @Summary Add a comment to a task
+// @Description Add a comment to a task. Set parent_id to reply to another comment on the same task.
+// @Description Users mentioned in the body as <@user_id> are notified. With X-User-ID, author_id must be that user
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param comment body models.CreateCommentRequest true "Comment details"
+// @Success 201 {object} models.Comment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	taskID := c.Param("id")
+
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(workspaceID(c), actorID(c), taskID, req)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// GetComments handles GET /api/v1/tasks/:id/comments
+// @Summary Get task's comments
+// @Description Get a page of top-level comments on a task, oldest first, with replies nested
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param limit query int false "Limit number of threads returned (default: 50)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} models.CommentsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments [get]
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	taskID := c.Param("id")
+
+	var filters models.CommentFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = 50
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	comments, total, err := h.commentService.GetComments(workspaceID(c), taskID, filters)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CommentsResponse{Comments: comments, Total: total})
+}
+
+// UpdateComment handles PUT /api/v1/tasks/:id/comments/:comment_id
+// @Summary Edit a comment
+// @Description Replace the text of an existing comment. Users the new text mentions as <@user_id> are notified
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Param comment body models.UpdateCommentRequest true "Updated comment text"
+// @Success 200 {object} models.Comment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	taskID := c.Param("id")
+	commentID := c.Param("comment_id")
+
+	var req models.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(workspaceID(c), actorID(c), taskID, commentID, req)
+	if err != nil {
+		writeServiceError(c, err, "Comment not found in the specified task")
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /api/v1/tasks/:id/comments/:comment_id
+// @Summary Delete a comment
+// @Description Delete a comment together with all of its replies
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	taskID := c.Param("id")
+	commentID := c.Param("comment_id")
+
+	if err := h.commentService.DeleteComment(workspaceID(c), taskID, commentID); err != nil {
+		writeServiceError(c, err, "Comment not found in the specified task")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
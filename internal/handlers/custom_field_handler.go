@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CustomFieldHandler struct {
+	customFieldService *service.CustomFieldService
+}
+
+func NewCustomFieldHandler(customFieldService *service.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{customFieldService: customFieldService}
+}
+
+// CreateCustomField handles POST /api/v1/custom-fields
+// @Summary Define a custom field
+// @Description Define a task attribute of type text, number, date, single_select, multi_select or user. Keys are unique within the workspace
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param field body models.CreateCustomFieldRequest true "Custom field definition"
+// @Success 201 {object} models.CustomField
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Custom field key already exists"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /custom-fields [post]
+func (h *CustomFieldHandler) CreateCustomField(c *gin.Context) {
+	var req models.CreateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	field, err := h.customFieldService.CreateCustomField(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Custom field not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, field)
+}
+
+// GetCustomFields handles GET /api/v1/custom-fields
+// @Summary Get all custom fields
+// @Description Get every custom field definition of the workspace ordered by name
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.CustomFieldsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /custom-fields [get]
+func (h *CustomFieldHandler) GetCustomFields(c *gin.Context) {
+	fields, err := h.customFieldService.GetCustomFields(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"custom_fields": fields})
+}
+
+// GetCustomField handles GET /api/v1/custom-fields/:id
+// @Summary Get a custom field by ID
+// @Description Get a specific custom field definition
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Custom field ID"
+// @Success 200 {object} models.CustomField
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /custom-fields/{id} [get]
+func (h *CustomFieldHandler) GetCustomField(c *gin.Context) {
+	field, err := h.customFieldService.GetCustomField(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Custom field not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// UpdateCustomField handles PUT /api/v1/custom-fields/:id
+// @Summary Update a custom field
+// @Description Rename a custom field, change its options or whether it is required. The key and type cannot change
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Custom field ID"
+// @Param field body models.UpdateCustomFieldRequest true "Updated custom field details"
+// @Success 200 {object} models.CustomField
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /custom-fields/{id} [put]
+func (h *CustomFieldHandler) UpdateCustomField(c *gin.Context) {
+	var req models.UpdateCustomFieldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	field, err := h.customFieldService.UpdateCustomField(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Custom field not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, field)
+}
+
+// DeleteCustomField handles DELETE /api/v1/custom-fields/:id
+// @Summary Delete a custom field
+// @Description Delete a custom field definition and its values on every task
+// @Tags custom-fields
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Custom field ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /custom-fields/{id} [delete]
+func (h *CustomFieldHandler) DeleteCustomField(c *gin.Context) {
+	if err := h.customFieldService.DeleteCustomField(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Custom field not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Custom field deleted successfully"})
+}
+
+// customFieldFilters collects the cf.<key>=value query parameters of a task listing
+func customFieldFilters(c *gin.Context) map[string]string {
+	filters := make(map[string]string)
+	for name, values := range c.Request.URL.Query() {
+		if key, ok := strings.CutPrefix(name, models.CustomFieldPrefix); ok && key != "" && len(values) > 0 {
+			filters[key] = values[0]
+		}
+	}
+	return filters
+}
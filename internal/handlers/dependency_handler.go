@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type DependencyHandler struct {
+	dependencyService *service.DependencyService
+}
+
+func NewDependencyHandler(dependencyService *service.DependencyService) *DependencyHandler {
+	return &DependencyHandler{dependencyService: dependencyService}
+}
+
+// GetDependencies handles GET /api/v1/tasks/:id/dependencies
+// @Summary Get task dependencies
+// @Description Get the tasks this task is blocked by and the tasks it blocks
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TaskDependencies
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/dependencies [get]
+func (h *DependencyHandler) GetDependencies(c *gin.Context) {
+	dependencies, err := h.dependencyService.GetDependencies(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// AddDependency handles POST /api/v1/tasks/:id/dependencies/:blocker_id
+// @Summary Mark a task as blocked by another task
+// @Description Make the task wait on the blocking task. Adding an existing dependency is a no-op; dependencies that would create a cycle are rejected
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param blocker_id path string true "Blocking task ID"
+// @Success 200 {object} models.TaskDependencies
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/dependencies/{blocker_id} [post]
+func (h *DependencyHandler) AddDependency(c *gin.Context) {
+	dependencies, err := h.dependencyService.AddDependency(workspaceID(c), c.Param("id"), c.Param("blocker_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
+
+// RemoveDependency handles DELETE /api/v1/tasks/:id/dependencies/:blocker_id
+// @Summary Remove a task dependency
+// @Description Stop the task from waiting on the blocking task
+// @Tags dependencies
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param blocker_id path string true "Blocking task ID"
+// @Success 200 {object} models.TaskDependencies
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/dependencies/{blocker_id} [delete]
+func (h *DependencyHandler) RemoveDependency(c *gin.Context) {
+	dependencies, err := h.dependencyService.RemoveDependency(workspaceID(c), c.Param("id"), c.Param("blocker_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found or it is not blocked by the given task")
+		return
+	}
+
+	c.JSON(http.StatusOK, dependencies)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailHandler struct {
+	emailService *service.EmailService
+}
+
+func NewEmailHandler(emailService *service.EmailService) *EmailHandler {
+	return &EmailHandler{emailService: emailService}
+}
+
+// GetSettings handles GET /api/v1/users/:id/email-settings
+// @Summary Get user's email settings
+// @Description Get the kinds of email the user opted out of: assignment, mention, reminder, digest
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.EmailSettings
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/email-settings [get]
+func (h *EmailHandler) GetSettings(c *gin.Context) {
+	settings, err := h.emailService.GetSettings(c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/users/:id/email-settings
+// @Summary Update user's email settings
+// @Description Replace the kinds of email the user opted out of. Queued emails of those kinds are not sent
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param settings body models.EmailSettings true "Email kinds to opt out of"
+// @Success 200 {object} models.EmailSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/email-settings [put]
+func (h *EmailHandler) UpdateSettings(c *gin.Context) {
+	var req models.EmailSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.emailService.UpdateSettings(c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// GetDeliveries handles GET /api/v1/email-deliveries
+// @Summary Get the email delivery log
+// @Description Get the emails sent or waiting to be sent in the workspace, newest first, with the outcome of their attempts
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param user_id query string false "Filter by recipient"
+// @Param kind query string false "Filter by kind: assignment, mention, reminder, digest"
+// @Param status query string false "Filter by status: pending, sent, failed, skipped"
+// @Param limit query int false "Limit number of entries returned (default: 50, max: 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} models.EmailDeliveriesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /email-deliveries [get]
+func (h *EmailHandler) GetDeliveries(c *gin.Context) {
+	var filters models.EmailDeliveryFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	deliveries, err := h.emailService.GetDeliveries(workspaceID(c), filters)
+	if err != nil {
+		writeServiceError(c, err, "Emails not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// GetDelivery handles GET /api/v1/email-deliveries/:id
+// @Summary Get an email of the delivery log
+// @Description Get an email sent or waiting to be sent, with the outcome of its attempts
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Email ID"
+// @Success 200 {object} models.EmailDelivery
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /email-deliveries/{id} [get]
+func (h *EmailHandler) GetDelivery(c *gin.Context) {
+	delivery, err := h.emailService.GetDelivery(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Email not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RetryDelivery handles POST /api/v1/email-deliveries/:id/retry
+// @Summary Retry a failed email
+// @Description Queue an email that ran out of attempts again, with a fresh set of attempts
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Email ID"
+// @Success 200 {object} models.EmailDelivery
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /email-deliveries/{id}/retry [post]
+func (h *EmailHandler) RetryDelivery(c *gin.Context) {
+	delivery, err := h.emailService.RetryDelivery(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Email not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LabelHandler struct {
+	labelService *service.LabelService
+}
+
+func NewLabelHandler(labelService *service.LabelService) *LabelHandler {
+	return &LabelHandler{labelService: labelService}
+}
+
+// CreateLabel handles POST /api/v1/labels
+// @Summary Create a label
+// @Description Create a new label. Names are unique regardless of case
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param label body models.CreateLabelRequest true "Label details"
+// @Success 201 {object} models.Label
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Label name already exists"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /labels [post]
+func (h *LabelHandler) CreateLabel(c *gin.Context) {
+	var req models.CreateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := h.labelService.CreateLabel(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Label not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, label)
+}
+
+// GetLabels handles GET /api/v1/labels
+// @Summary Get all labels
+// @Description Get all labels ordered by name
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.LabelsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /labels [get]
+func (h *LabelHandler) GetLabels(c *gin.Context) {
+	labels, err := h.labelService.GetLabels(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"labels": labels})
+}
+
+// GetLabel handles GET /api/v1/labels/:id
+// @Summary Get a label by ID
+// @Description Get a specific label
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Label ID"
+// @Success 200 {object} models.Label
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /labels/{id} [get]
+func (h *LabelHandler) GetLabel(c *gin.Context) {
+	label, err := h.labelService.GetLabel(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Label not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, label)
+}
+
+// UpdateLabel handles PUT /api/v1/labels/:id
+// @Summary Update a label
+// @Description Rename or recolor a label
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Label ID"
+// @Param label body models.UpdateLabelRequest true "Updated label details"
+// @Success 200 {object} models.Label
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Label name already exists"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /labels/{id} [put]
+func (h *LabelHandler) UpdateLabel(c *gin.Context) {
+	var req models.UpdateLabelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := h.labelService.UpdateLabel(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Label not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, label)
+}
+
+// DeleteLabel handles DELETE /api/v1/labels/:id
+// @Summary Delete a label
+// @Description Delete a label and detach it from every task
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Label ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /labels/{id} [delete]
+func (h *LabelHandler) DeleteLabel(c *gin.Context) {
+	if err := h.labelService.DeleteLabel(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Label not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Label deleted successfully"})
+}
+
+// AssignLabel handles POST /api/v1/tasks/:id/labels/:label_id
+// @Summary Attach a label to a task
+// @Description Attach an existing label to a task. Attaching an already attached label is a no-op
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/labels/{label_id} [post]
+func (h *LabelHandler) AssignLabel(c *gin.Context) {
+	task, err := h.labelService.AssignLabel(workspaceID(c), actorID(c), c.Param("id"), c.Param("label_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task or label not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// UnassignLabel handles DELETE /api/v1/tasks/:id/labels/:label_id
+// @Summary Detach a label from a task
+// @Description Detach a label from a task
+// @Tags labels
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param label_id path string true "Label ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/labels/{label_id} [delete]
+func (h *LabelHandler) UnassignLabel(c *gin.Context) {
+	task, err := h.labelService.UnassignLabel(workspaceID(c), actorID(c), c.Param("id"), c.Param("label_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found or label is not attached to it")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
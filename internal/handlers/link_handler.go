@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LinkHandler struct {
+	linkService *service.LinkService
+}
+
+func NewLinkHandler(linkService *service.LinkService) *LinkHandler {
+	return &LinkHandler{linkService: linkService}
+}
+
+// CreateLink handles POST /api/v1/tasks/:id/links
+// @Summary Add a link to a task
+// @Description Link a document, design, pull request or other resource to a task
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param link body models.CreateTaskLinkRequest true "Link details"
+// @Success 201 {object} models.TaskLink
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "URL is already linked to the task"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/links [post]
+func (h *LinkHandler) CreateLink(c *gin.Context) {
+	taskID := c.Param("id")
+
+	var req models.CreateTaskLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.linkService.CreateLink(workspaceID(c), taskID, req)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// GetLinks handles GET /api/v1/tasks/:id/links
+// @Summary Get task's links
+// @Description Get all links of a task in the order they were added
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TaskLinksResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/links [get]
+func (h *LinkHandler) GetLinks(c *gin.Context) {
+	taskID := c.Param("id")
+
+	links, err := h.linkService.GetLinks(workspaceID(c), taskID)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"links": links})
+}
+
+// UpdateLink handles PUT /api/v1/tasks/:id/links/:link_id
+// @Summary Update a link
+// @Description Update an existing task link
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param link_id path string true "Link ID"
+// @Param link body models.UpdateTaskLinkRequest true "Updated link details"
+// @Success 200 {object} models.TaskLink
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "URL is already linked to the task"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/links/{link_id} [put]
+func (h *LinkHandler) UpdateLink(c *gin.Context) {
+	taskID := c.Param("id")
+	linkID := c.Param("link_id")
+
+	var req models.UpdateTaskLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.linkService.UpdateLink(workspaceID(c), taskID, linkID, req)
+	if err != nil {
+		writeServiceError(c, err, "Link not found in the specified task")
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// DeleteLink handles DELETE /api/v1/tasks/:id/links/:link_id
+// @Summary Delete a link
+// @Description Remove a link from a task
+// @Tags links
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param link_id path string true "Link ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/links/{link_id} [delete]
+func (h *LinkHandler) DeleteLink(c *gin.Context) {
+	taskID := c.Param("id")
+	linkID := c.Param("link_id")
+
+	if err := h.linkService.DeleteLink(workspaceID(c), taskID, linkID); err != nil {
+		writeServiceError(c, err, "Link not found in the specified task")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Link deleted successfully"})
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type MilestoneHandler struct {
+	milestoneService *service.MilestoneService
+}
+
+func NewMilestoneHandler(milestoneService *service.MilestoneService) *MilestoneHandler {
+	return &MilestoneHandler{milestoneService: milestoneService}
+}
+
+// CreateMilestone handles POST /api/v1/milestones
+// @Summary Create a milestone
+// @Description Create a new milestone, optionally within a project
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param milestone body models.CreateMilestoneRequest true "Milestone details"
+// @Success 201 {object} models.Milestone
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones [post]
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	var req models.CreateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.CreateMilestone(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Milestone not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, milestone)
+}
+
+// GetMilestones handles GET /api/v1/milestones
+// @Summary Get all milestones
+// @Description Get milestones with their task counts and progress, by due date
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param project_id query string false "Filter by project ID"
+// @Success 200 {object} models.MilestonesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones [get]
+func (h *MilestoneHandler) GetMilestones(c *gin.Context) {
+	milestones, err := h.milestoneService.GetMilestones(workspaceID(c), c.Query("project_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"milestones": milestones})
+}
+
+// GetMilestone handles GET /api/v1/milestones/:id
+// @Summary Get a milestone by ID
+// @Description Get a milestone with its task counts per status and progress
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Milestone ID"
+// @Success 200 {object} models.Milestone
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones/{id} [get]
+func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
+	milestone, err := h.milestoneService.GetMilestone(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Milestone not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// UpdateMilestone handles PUT /api/v1/milestones/:id
+// @Summary Update a milestone
+// @Description Update an existing milestone's details
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Milestone ID"
+// @Param milestone body models.UpdateMilestoneRequest true "Updated milestone details"
+// @Success 200 {object} models.Milestone
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones/{id} [put]
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+	var req models.UpdateMilestoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	milestone, err := h.milestoneService.UpdateMilestone(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Milestone not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+// DeleteMilestone handles DELETE /api/v1/milestones/:id
+// @Summary Delete a milestone
+// @Description Delete a milestone. Its tasks are kept and no longer belong to any milestone
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Milestone ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones/{id} [delete]
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	if err := h.milestoneService.DeleteMilestone(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Milestone not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Milestone deleted successfully"})
+}
+
+// GetMilestoneTasks handles GET /api/v1/milestones/:id/tasks
+// @Summary Get milestone's tasks
+// @Description Get the tasks of a milestone. Accepts the same filters as GET /tasks
+// @Tags milestones
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Milestone ID"
+// @Param status query string false "Filter by status key; not-started, in-progress and completed match their whole category"
+// @Param category query string false "Filter by status category: todo, doing, done"
+// @Param priority query string false "Filter by priority"
+// @Param user_id query string false "Filter by assigned user ID"
+// @Param label query []string false "Filter by label name" collectionFormat(multi)
+// @Param label_match query string false "any (default) or all"
+// @Param sort_by query string false "Sort by due_date, created_at, status, priority or cf.<key> for a custom field"
+// @Param sort_type query string false "Sort direction: asc or desc"
+// @Param limit query int false "Limit number of tasks returned (default: 50)"
+// @Param offset query int false "Offset for pagination"
+// @Param cf.key query string false "Filter by a custom field value; replace key with the field key"
+// @Success 200 {object} models.TasksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /milestones/{id}/tasks [get]
+func (h *MilestoneHandler) GetMilestoneTasks(c *gin.Context) {
+	var filters models.TaskFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filters.CustomFields = customFieldFilters(c)
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	tasks, err := h.milestoneService.GetMilestoneTasks(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Milestone not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService}
+}
+
+// GetWatchers handles GET /api/v1/tasks/:id/watchers
+// @Summary Get task's watchers
+// @Description Get the users notified about changes to a task
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.WatchersResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/watchers [get]
+func (h *NotificationHandler) GetWatchers(c *gin.Context) {
+	watchers, err := h.notificationService.GetWatchers(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watchers": watchers})
+}
+
+// Watch handles POST /api/v1/tasks/:id/watchers/:user_id
+// @Summary Watch a task
+// @Description Make a user watch a task. Watching an already watched task is a no-op
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.WatchersResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/watchers/{user_id} [post]
+func (h *NotificationHandler) Watch(c *gin.Context) {
+	watchers, err := h.notificationService.Watch(workspaceID(c), c.Param("id"), c.Param("user_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task or user not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watchers": watchers})
+}
+
+// Unwatch handles DELETE /api/v1/tasks/:id/watchers/:user_id
+// @Summary Stop watching a task
+// @Description Stop a user watching a task. Assignees and commenters watch a task automatically but can stop too
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.WatchersResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/watchers/{user_id} [delete]
+func (h *NotificationHandler) Unwatch(c *gin.Context) {
+	watchers, err := h.notificationService.Unwatch(workspaceID(c), c.Param("id"), c.Param("user_id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found or user does not watch it")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"watchers": watchers})
+}
+
+// GetNotifications handles GET /api/v1/notifications
+// @Summary Get the notification inbox
+// @Description Get the notifications of the user named by X-User-ID, newest first
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Param unread query bool false "Only unread notifications"
+// @Param type query string false "Filter by type: task_assigned, task_updated, task_deleted, task_restored, subtask_changed, comment_added, mentioned, task_due_soon, task_overdue"
+// @Param limit query int false "Limit number of notifications returned (default: 50, max: 200)"
+// @Param offset query int false "Offset for pagination"
+// @Success 200 {object} models.NotificationsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	var filters models.NotificationFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	notifications, err := h.notificationService.GetNotifications(workspaceID(c), actorID(c), filters)
+	if err != nil {
+		writeServiceError(c, err, "Notifications not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notifications": notifications})
+}
+
+// GetUnreadCount handles GET /api/v1/notifications/unread-count
+// @Summary Count unread notifications
+// @Description Count the unread notifications of the user named by X-User-ID, in total and per type
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.UnreadCount
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	unread, err := h.notificationService.GetUnreadCount(workspaceID(c), actorID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, unread)
+}
+
+// MarkRead handles POST /api/v1/notifications/:id/read
+// @Summary Mark a notification as read
+// @Description Mark a notification of the user named by X-User-ID as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Param id path string true "Notification ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	if err := h.notificationService.MarkRead(workspaceID(c), actorID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Notification not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllRead handles POST /api/v1/notifications/read-all
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the user named by X-User-ID as read
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.MarkedReadResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	marked, err := h.notificationService.MarkAllRead(workspaceID(c), actorID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// GetSettings handles GET /api/v1/notifications/settings
+// @Summary Get notification settings
+// @Description Get the notification types the user named by X-User-ID muted in the workspace
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Success 200 {object} models.NotificationSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/settings [get]
+func (h *NotificationHandler) GetSettings(c *gin.Context) {
+	settings, err := h.notificationService.GetSettings(workspaceID(c), actorID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/v1/notifications/settings
+// @Summary Update notification settings
+// @Description Replace the notification types the user named by X-User-ID muted in the workspace.
+// @Description Muted types are not delivered; notifications already in the inbox stay
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string true "User ID"
+// @Param settings body models.NotificationSettings true "Muted notification types"
+// @Success 200 {object} models.NotificationSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications/settings [put]
+func (h *NotificationHandler) UpdateSettings(c *gin.Context) {
+	var req models.NotificationSettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.notificationService.UpdateSettings(workspaceID(c), actorID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Notification settings not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
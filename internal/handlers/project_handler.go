@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ProjectHandler struct {
+	projectService *service.ProjectService
+}
+
+func NewProjectHandler(projectService *service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService}
+}
+
+// CreateProject handles POST /api/v1/projects
+// @Summary Create a project
+// @Description Create a new project to group tasks
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param project body models.CreateProjectRequest true "Project details"
+// @Success 201 {object} models.Project
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	var req models.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.CreateProject(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Project not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// GetProjects handles GET /api/v1/projects
+// @Summary Get all projects
+// @Description Get all projects with their task counts per status
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.ProjectsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects [get]
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	projects, err := h.projectService.GetProjects(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+// GetProject handles GET /api/v1/projects/:id
+// @Summary Get a project by ID
+// @Description Get a project with its task counts per status
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	project, err := h.projectService.GetProject(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Project not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// UpdateProject handles PUT /api/v1/projects/:id
+// @Summary Update a project
+// @Description Update an existing project's details
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Project ID"
+// @Param project body models.UpdateProjectRequest true "Updated project details"
+// @Success 200 {object} models.Project
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	var req models.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.UpdateProject(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Project not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// DeleteProject handles DELETE /api/v1/projects/:id
+// @Summary Delete a project
+// @Description Delete a project. Its tasks are kept and no longer belong to any project
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	if err := h.projectService.DeleteProject(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Project not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+}
+
+// GetProjectTasks handles GET /api/v1/projects/:id/tasks
+// @Summary Get project's tasks
+// @Description Get the tasks of a project. Accepts the same filters as GET /tasks
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Project ID"
+// @Param status query string false "Filter by status key; not-started, in-progress and completed match their whole category"
+// @Param category query string false "Filter by status category: todo, doing, done"
+// @Param priority query string false "Filter by priority"
+// @Param user_id query string false "Filter by assigned user ID"
+// @Param series_id query string false "Filter by recurring series ID"
+// @Param label query []string false "Filter by label name" collectionFormat(multi)
+// @Param label_match query string false "any (default) or all"
+// @Param sort_by query string false "Sort by due_date, created_at, status, priority or cf.<key> for a custom field"
+// @Param sort_type query string false "Sort direction: asc or desc"
+// @Param limit query int false "Limit number of tasks returned (default: 50)"
+// @Param offset query int false "Offset for pagination"
+// @Param cf.key query string false "Filter by a custom field value; replace key with the field key"
+// @Success 200 {object} models.TasksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /projects/{id}/tasks [get]
+func (h *ProjectHandler) GetProjectTasks(c *gin.Context) {
+	var filters models.TaskFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filters.CustomFields = customFieldFilters(c)
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	tasks, err := h.projectService.GetProjectTasks(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Project not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService: reportService}
+}
+
+// GetBurndown handles GET /api/v1/reports/burndown
+// @Summary Get burndown data
+// @Description Get the remaining effort in hours and story points at the end of each day for the tasks matching the usual task filters.
+// @Description Completed tasks and subtasks count as zero; an unset remaining_hours counts the whole estimate
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param from query string false "First day, YYYY-MM-DD (default: 13 days before to)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: today)"
+// @Param project_id query string false "Filter by project ID"
+// @Param sprint_id query string false "Filter by sprint ID"
+// @Param milestone_id query string false "Filter by milestone ID"
+// @Param status query string false "Filter by status key; not-started, in-progress and completed match their whole category"
+// @Param category query string false "Filter by status category: todo, doing, done"
+// @Param user_id query string false "Filter by assigned user ID"
+// @Param priority query string false "Filter by priority: none, low, medium, high, urgent"
+// @Param label query []string false "Filter by label names"
+// @Param cf.key query string false "Filter by a custom field value; replace key with the field key"
+// @Success 200 {object} models.BurndownReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /reports/burndown [get]
+func (h *ReportHandler) GetBurndown(c *gin.Context) {
+	var filters models.TaskFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filters.CustomFields = customFieldFilters(c)
+
+	var period models.BurndownFilters
+	if err := c.ShouldBindQuery(&period); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.reportService.GetBurndown(workspaceID(c), filters, period)
+	if err != nil {
+		writeServiceError(c, err, "Report not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RevisionHandler struct {
+	revisionService *service.RevisionService
+}
+
+func NewRevisionHandler(revisionService *service.RevisionService) *RevisionHandler {
+	return &RevisionHandler{revisionService: revisionService}
+}
+
+// GetRevisions handles GET /api/v1/tasks/:id/revisions
+// @Summary Get task's revisions
+// @Description Get the revisions of a task without their snapshots, newest first.
+// @Description A revision is stored after every change to the task or its subtasks
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.RevisionsResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/revisions [get]
+func (h *RevisionHandler) GetRevisions(c *gin.Context) {
+	revisions, err := h.revisionService.GetRevisions(workspaceID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetRevision handles GET /api/v1/tasks/:id/revisions/:rev
+// @Summary Get a revision of a task
+// @Description Get a revision with its snapshot: the task and its subtasks as they were, parents before their children
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.TaskRevision
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/revisions/{rev} [get]
+func (h *RevisionHandler) GetRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	revision, err := h.revisionService.GetRevision(workspaceID(c), c.Param("id"), number)
+	if err != nil {
+		writeServiceError(c, err, "Revision not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, revision)
+}
+
+// DiffRevisions handles GET /api/v1/tasks/:id/revisions/:rev/diff
+// @Summary Compare revisions of a task
+// @Description Get the field changes of the task and its subtasks from revision from to revision rev
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param rev path int true "Revision number"
+// @Param from query int false "Revision to compare with (default: the previous one; 0 compares with nothing)"
+// @Success 200 {object} models.RevisionDiff
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/revisions/{rev}/diff [get]
+func (h *RevisionHandler) DiffRevisions(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	from := number - 1
+	if value := c.Query("from"); value != "" {
+		var err error
+		if from, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a revision number"})
+			return
+		}
+	}
+
+	diff, err := h.revisionService.DiffRevisions(workspaceID(c), c.Param("id"), from, number)
+	if err != nil {
+		writeServiceError(c, err, "Revision not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision handles POST /api/v1/tasks/:id/revisions/:rev/restore
+// @Summary Restore a revision of a task
+// @Description Bring the task and its subtasks back to a revision, validated like any other edit, and store the result
+// @Description as a new revision, assignees and labels included. Subtasks the revision did not have go to the trash. Recurrence is kept.
+// @Description Nothing is changed if any step fails validation
+// @Tags revisions
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/revisions/{rev}/restore [post]
+func (h *RevisionHandler) RestoreRevision(c *gin.Context) {
+	number, ok := revisionNumber(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	task, err := h.revisionService.RestoreRevision(workspaceID(c), actorID(c), c.Param("id"), number)
+	if err != nil {
+		writeServiceError(c, err, "Task or revision not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// revisionNumber parses a revision number from the path, answering 400 when it is not a positive integer
+func revisionNumber(c *gin.Context, value string) (int, bool) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Revision must be a positive number"})
+		return 0, false
+	}
+	return number, true
+}
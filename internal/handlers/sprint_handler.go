@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type SprintHandler struct {
+	sprintService *service.SprintService
+}
+
+func NewSprintHandler(sprintService *service.SprintService) *SprintHandler {
+	return &SprintHandler{sprintService: sprintService}
+}
+
+// CreateSprint handles POST /api/v1/sprints
+// @Summary Create a sprint
+// @Description Plan a new sprint, optionally within a project
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param sprint body models.CreateSprintRequest true "Sprint details"
+// @Success 201 {object} models.Sprint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints [post]
+func (h *SprintHandler) CreateSprint(c *gin.Context) {
+	var req models.CreateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, err := h.sprintService.CreateSprint(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, sprint)
+}
+
+// GetSprints handles GET /api/v1/sprints
+// @Summary Get all sprints
+// @Description Get sprints with their task counts and progress, latest first
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param state query string false "Filter by state: planned, active, closed"
+// @Param project_id query string false "Filter by project ID"
+// @Success 200 {object} models.SprintsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints [get]
+func (h *SprintHandler) GetSprints(c *gin.Context) {
+	var filters models.SprintFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprints, err := h.sprintService.GetSprints(workspaceID(c), filters)
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sprints": sprints})
+}
+
+// GetSprint handles GET /api/v1/sprints/:id
+// @Summary Get a sprint by ID
+// @Description Get a sprint with its task counts per status and progress
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Sprint ID"
+// @Success 200 {object} models.Sprint
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id} [get]
+func (h *SprintHandler) GetSprint(c *gin.Context) {
+	sprint, err := h.sprintService.GetSprint(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, sprint)
+}
+
+// UpdateSprint handles PUT /api/v1/sprints/:id
+// @Summary Update a sprint
+// @Description Update a sprint's name, goal or dates. The dates of a closed sprint cannot be changed
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Sprint ID"
+// @Param sprint body models.UpdateSprintRequest true "Updated sprint details"
+// @Success 200 {object} models.Sprint
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id} [put]
+func (h *SprintHandler) UpdateSprint(c *gin.Context) {
+	var req models.UpdateSprintRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, err := h.sprintService.UpdateSprint(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, sprint)
+}
+
+// DeleteSprint handles DELETE /api/v1/sprints/:id
+// @Summary Delete a sprint
+// @Description Delete a sprint. Its tasks are kept and go back to the backlog
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Sprint ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id} [delete]
+func (h *SprintHandler) DeleteSprint(c *gin.Context) {
+	if err := h.sprintService.DeleteSprint(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sprint deleted successfully"})
+}
+
+// StartSprint handles POST /api/v1/sprints/:id/start
+// @Summary Start a sprint
+// @Description Make a planned sprint active. A project can only have one active sprint
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Sprint ID"
+// @Success 200 {object} models.Sprint
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id}/start [post]
+func (h *SprintHandler) StartSprint(c *gin.Context) {
+	sprint, err := h.sprintService.StartSprint(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, sprint)
+}
+
+// CloseSprint handles POST /api/v1/sprints/:id/close
+// @Summary Close a sprint
+// @Description Close the active sprint. Unfinished tasks move to carry_over_to, by default to the
+// @Description project's next planned sprint; an empty carry_over_to moves them to the backlog
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Sprint ID"
+// @Param close body models.CloseSprintRequest false "Where to carry unfinished tasks over"
+// @Success 200 {object} models.CloseSprintResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id}/close [post]
+func (h *SprintHandler) CloseSprint(c *gin.Context) {
+	var req models.CloseSprintRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.sprintService.CloseSprint(workspaceID(c), actorID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetSprintTasks handles GET /api/v1/sprints/:id/tasks
+// @Summary Get sprint's tasks
+// @Description Get the tasks of a sprint. Accepts the same filters as GET /tasks
+// @Tags sprints
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Sprint ID"
+// @Param status query string false "Filter by status key; not-started, in-progress and completed match their whole category"
+// @Param category query string false "Filter by status category: todo, doing, done"
+// @Param priority query string false "Filter by priority"
+// @Param user_id query string false "Filter by assigned user ID"
+// @Param label query []string false "Filter by label name" collectionFormat(multi)
+// @Param label_match query string false "any (default) or all"
+// @Param sort_by query string false "Sort by due_date, created_at, status, priority or cf.<key> for a custom field"
+// @Param sort_type query string false "Sort direction: asc or desc"
+// @Param limit query int false "Limit number of tasks returned (default: 50)"
+// @Param offset query int false "Offset for pagination"
+// @Param cf.key query string false "Filter by a custom field value; replace key with the field key"
+// @Success 200 {object} models.TasksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /sprints/{id}/tasks [get]
+func (h *SprintHandler) GetSprintTasks(c *gin.Context) {
+	var filters models.TaskFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filters.CustomFields = customFieldFilters(c)
+
+	if filters.Limit == 0 {
+		filters.Limit = 50
+	}
+
+	tasks, err := h.sprintService.GetSprintTasks(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Sprint not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
@@ -29,6 +29,8 @@ func NewTaskHandler(taskService *service.TaskService) *TaskHandler {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param task body models.CreateTaskRequest true "Task details"
 // @Success 201 {object} models.Task
 // @Failure 400 {object} models.ErrorResponse
@@ -41,9 +43,9 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.CreateTask(req)
+	task, err := h.taskService.CreateTask(workspaceID(c), actorID(c), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err, "Task not found")
 		return
 	}
 
@@ -56,10 +58,19 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param status query string false "Filter by status"
-// @Param priority query string false "Filter by priority"
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param status query string false "Filter by status key; not-started, in-progress and completed match their whole category"
+// @Param category query string false "Filter by status category: todo, doing, done"
+// @Param user_id query string false "Filter by assigned user ID"
+// @Param series_id query string false "Filter by recurring series ID"
+// @Param sprint_id query string false "Filter by sprint ID"
+// @Param milestone_id query string false "Filter by milestone ID"
+// @Param priority query string false "Filter by priority: none, low, medium, high, urgent"
+// @Param sort_by query string false "Sort by due_date, created_at, status, priority or cf.<key> for a custom field"
+// @Param sort_type query string false "Sort direction: asc or desc"
 // @Param limit query int false "Limit number of tasks returned (default: 50)"
 // @Param offset query int false "Offset for pagination"
+// @Param cf.key query string false "Filter by a custom field value; replace key with the field key"
 // @Success 200 {object} models.TasksResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
@@ -70,15 +81,16 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	filters.CustomFields = customFieldFilters(c)
 
 	// Set default values if not provided
 	if filters.Limit == 0 {
 		filters.Limit = 50 // default limit
 	}
 
-	tasks, err := h.taskService.GetTasks(filters)
+	tasks, err := h.taskService.GetTasks(workspaceID(c), filters)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err, "Task not found")
 		return
 	}
 
@@ -91,6 +103,7 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
 // @Param id path string true "Task ID"
 // @Success 200 {object} models.Task
 // @Failure 404 {object} models.ErrorResponse
@@ -99,7 +112,7 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 func (h *TaskHandler) GetTask(c *gin.Context) {
 	id := c.Param("id")
 
-	task, err := h.taskService.GetTask(id)
+	task, err := h.taskService.GetTask(workspaceID(c), id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
@@ -114,15 +127,19 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 
 // UpdateTask handles PUT /api/v1/tasks/:id
 // @Summary Update a task
-// @Description Update an existing task's details
+// @Description Update an existing task's details. Status changes must be allowed by the workflow, and moving a task
+// @Description with open blockers into a done status is rejected when TASK_REJECT_BLOCKED_COMPLETION is enabled
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Param task body models.UpdateTaskRequest true "Updated task details"
 // @Success 200 {object} models.Task
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
@@ -134,13 +151,9 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskService.UpdateTask(id, req)
+	task, err := h.taskService.UpdateTask(workspaceID(c), actorID(c), id, req)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err, "Task not found")
 		return
 	}
 
@@ -149,10 +162,12 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 
 // DeleteTask handles DELETE /api/v1/tasks/:id
 // @Summary Delete a task
-// @Description Delete an existing task
+// @Description Move a task and its subtasks to the trash. It can be restored until the trash retention period ends
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Success 200 {object} models.MessageResponse
 // @Failure 404 {object} models.ErrorResponse
@@ -161,29 +176,126 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
 
-	err := h.taskService.DeleteTask(id)
+	err := h.taskService.DeleteTask(workspaceID(c), actorID(c), id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err, "Task not found")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
 
+// RestoreTask handles POST /api/v1/tasks/:id/restore
+// @Summary Restore a task
+// @Description Take a task out of the trash together with the subtasks deleted with it
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} models.ErrorResponse "Task not in the trash"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	task, err := h.taskService.RestoreTask(workspaceID(c), actorID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Task not found in the trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// GetTrash handles GET /api/v1/trash
+// @Summary Get the trash
+// @Description List the deleted tasks, with their subtasks, and the deleted subtasks of other tasks, most recently deleted first
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.TrashResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /trash [get]
+func (h *TaskHandler) GetTrash(c *gin.Context) {
+	trash, err := h.taskService.GetTrash(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+// AssignUser handles POST /api/v1/tasks/:id/users/:user_id
+// @Summary Assign a user to a task
+// @Description Assign an existing user to a task. The user starts watching the task and is notified about the assignment.
+// @Description Assigning an already assigned user is a no-op
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/users/{user_id} [post]
+func (h *TaskHandler) AssignUser(c *gin.Context) {
+	taskID := c.Param("id")
+	userID := c.Param("user_id")
+
+	task, err := h.taskService.AssignUser(workspaceID(c), actorID(c), taskID, userID)
+	if err != nil {
+		writeServiceError(c, err, "Task or user not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// UnassignUser handles DELETE /api/v1/tasks/:id/users/:user_id
+// @Summary Remove a user from a task
+// @Description Remove a user's assignment from a task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.Task
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/users/{user_id} [delete]
+func (h *TaskHandler) UnassignUser(c *gin.Context) {
+	taskID := c.Param("id")
+	userID := c.Param("user_id")
+
+	task, err := h.taskService.UnassignUser(workspaceID(c), actorID(c), taskID, userID)
+	if err != nil {
+		writeServiceError(c, err, "Task not found or user is not assigned to it")
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
 // CreateSubTask handles POST /api/v1/tasks/:id/subtasks
 // @Summary Create a subtask
 // @Description Create a new subtask for a specific task
 // @Tags subtasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Param subtask body models.CreateSubTaskRequest true "Subtask details"
 // @Success 201 {object} models.SubTask
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /tasks/{id}/subtasks [post]
 func (h *TaskHandler) CreateSubTask(c *gin.Context) {
@@ -195,9 +307,9 @@ func (h *TaskHandler) CreateSubTask(c *gin.Context) {
 		return
 	}
 
-	subTask, err := h.taskService.CreateSubTask(taskID, req)
+	subTask, err := h.taskService.CreateSubTask(workspaceID(c), actorID(c), taskID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeServiceError(c, err, "Task not found")
 		return
 	}
 
@@ -206,18 +318,26 @@ func (h *TaskHandler) CreateSubTask(c *gin.Context) {
 
 // GetSubTasksByTaskID handles GET /api/v1/tasks/:id/subtasks
 // @Summary Get task's subtasks
-// @Description Get all subtasks for a specific task
+// @Description Get all subtasks for a specific task as a flat list, or nested under their parents with tree=true
 // @Tags subtasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
 // @Param id path string true "Task ID"
+// @Param tree query bool false "Return subtasks nested in children"
 // @Success 200 {object} models.SubTasksResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /tasks/{id}/subtasks [get]
 func (h *TaskHandler) GetSubTasksByTaskID(c *gin.Context) {
 	taskID := c.Param("id")
 
-	subTasks, err := h.taskService.GetSubTasksByTaskID(taskID)
+	var subTasks []models.SubTask
+	var err error
+	if c.Query("tree") == "true" {
+		subTasks, err = h.taskService.GetSubTaskTree(workspaceID(c), taskID)
+	} else {
+		subTasks, err = h.taskService.GetSubTasksByTaskID(workspaceID(c), taskID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -228,16 +348,19 @@ func (h *TaskHandler) GetSubTasksByTaskID(c *gin.Context) {
 
 // UpdateSubTask handles PUT /api/v1/tasks/:id/subtasks/:subtask_id
 // @Summary Update a subtask
-// @Description Update an existing subtask's details
+// @Description Update an existing subtask's details. Status changes must be allowed by the workflow
 // @Tags subtasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Param subtask_id path string true "Subtask ID"
 // @Param subtask body models.UpdateSubTaskRequest true "Updated subtask details"
 // @Success 200 {object} models.SubTask
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /tasks/{id}/subtasks/{subtask_id} [put]
 func (h *TaskHandler) UpdateSubTask(c *gin.Context) {
@@ -251,7 +374,7 @@ func (h *TaskHandler) UpdateSubTask(c *gin.Context) {
 	}
 
 	// Verify that the subtask belongs to the task
-	subTask, err := h.taskService.GetSubTasksByTaskID(taskID)
+	subTask, err := h.taskService.GetSubTasksByTaskID(workspaceID(c), taskID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -269,7 +392,7 @@ func (h *TaskHandler) UpdateSubTask(c *gin.Context) {
 		return
 	}
 
-	updatedSubTask, err := h.taskService.UpdateSubTask(subtaskID, req)
+	updatedSubTask, err := h.taskService.UpdateSubTask(workspaceID(c), actorID(c), subtaskID, req)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "SubTask not found"})
@@ -284,10 +407,12 @@ func (h *TaskHandler) UpdateSubTask(c *gin.Context) {
 
 // DeleteSubTask handles DELETE /api/v1/tasks/:id/subtasks/:subtask_id
 // @Summary Delete a subtask
-// @Description Delete an existing subtask
+// @Description Move an existing subtask together with its nested subtasks to the trash
 // @Tags subtasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Param subtask_id path string true "Subtask ID"
 // @Success 200 {object} models.MessageResponse
@@ -299,7 +424,7 @@ func (h *TaskHandler) DeleteSubTask(c *gin.Context) {
 	subtaskID := c.Param("subtask_id")
 
 	// Verify that the subtask belongs to the task
-	subTasks, err := h.taskService.GetSubTasksByTaskID(taskID)
+	subTasks, err := h.taskService.GetSubTasksByTaskID(workspaceID(c), taskID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -317,7 +442,7 @@ func (h *TaskHandler) DeleteSubTask(c *gin.Context) {
 		return
 	}
 
-	err = h.taskService.DeleteSubTask(subtaskID)
+	err = h.taskService.DeleteSubTask(workspaceID(c), actorID(c), subtaskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			c.JSON(http.StatusNotFound, gin.H{"error": "SubTask not found"})
@@ -330,6 +455,32 @@ func (h *TaskHandler) DeleteSubTask(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "SubTask deleted successfully"})
 }
 
+// RestoreSubTask handles POST /api/v1/tasks/:id/subtasks/:subtask_id/restore
+// @Summary Restore a subtask
+// @Description Take a subtask out of the trash together with the nested subtasks deleted with it, back at its original order.
+// @Description Its task and parent subtask must have been restored first
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param id path string true "Task ID"
+// @Param subtask_id path string true "Subtask ID"
+// @Success 200 {object} models.SubTask
+// @Failure 404 {object} models.ErrorResponse "Subtask not in the trash of the task"
+// @Failure 409 {object} models.ErrorResponse "The task or parent subtask is in the trash"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/subtasks/{subtask_id}/restore [post]
+func (h *TaskHandler) RestoreSubTask(c *gin.Context) {
+	subTask, err := h.taskService.RestoreSubTask(workspaceID(c), actorID(c), c.Param("id"), c.Param("subtask_id"))
+	if err != nil {
+		writeServiceError(c, err, "SubTask not found in the trash")
+		return
+	}
+
+	c.JSON(http.StatusOK, subTask)
+}
+
 // ReorderSubTaskRequest represents the request body for reordering a subtask
 // @Description Request body for reordering a subtask within its parent task
 type ReorderSubTaskRequest struct {
@@ -338,10 +489,12 @@ type ReorderSubTaskRequest struct {
 
 // ReorderSubTask handles POST /api/v1/tasks/:id/subtasks/:subtask_id/reorder
 // @Summary Reorder a subtask
-// @Description Reorder a subtask within its parent task by changing its order position
+// @Description Reorder a subtask among its siblings under the same parent by changing its order position
 // @Tags subtasks
 // @Accept json
 // @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
 // @Param id path string true "Task ID"
 // @Param subtask_id path string true "Subtask ID"
 // @Param request body ReorderSubTaskRequest true "Reorder request"
@@ -376,7 +529,7 @@ func (h *TaskHandler) ReorderSubTask(c *gin.Context) {
 
 	fmt.Printf("Parsed request: %+v\n", req)
 
-	err = h.taskService.ReorderSubTask(taskID, subTaskID, req.NewOrder)
+	err = h.taskService.ReorderSubTask(workspaceID(c), actorID(c), taskID, subTaskID, req.NewOrder)
 	if err != nil {
 		fmt.Printf("Error in ReorderSubTask service: %v\n", err)
 		switch err.Error() {
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TemplateHandler struct {
+	templateService *service.TemplateService
+}
+
+func NewTemplateHandler(templateService *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{templateService: templateService}
+}
+
+// CreateTemplate handles POST /api/v1/templates
+// @Summary Create a task template
+// @Description Create a reusable task blueprint with nested subtasks, labels, custom field values and a relative due date
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param template body models.CreateTaskTemplateRequest true "Template details"
+// @Success 201 {object} models.TaskTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	var req models.CreateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Template not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// GetTemplates handles GET /api/v1/templates
+// @Summary Get all task templates
+// @Description Get every task template of the workspace ordered by name
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.TaskTemplatesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /templates [get]
+func (h *TemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.templateService.GetTemplates(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetTemplate handles GET /api/v1/templates/:id
+// @Summary Get a task template by ID
+// @Description Get a specific task template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.TaskTemplate
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Template not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate handles PUT /api/v1/templates/:id
+// @Summary Update a task template
+// @Description Update a task template. label_ids, custom_fields and sub_tasks replace the stored values when given
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Template ID"
+// @Param template body models.UpdateTaskTemplateRequest true "Updated template details"
+// @Success 200 {object} models.TaskTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	var req models.UpdateTaskTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.UpdateTemplate(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Template not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate handles DELETE /api/v1/templates/:id
+// @Summary Delete a task template
+// @Description Delete a task template. Tasks created from it are kept
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	if err := h.templateService.DeleteTemplate(workspaceID(c), c.Param("id")); err != nil {
+		writeServiceError(c, err, "Template not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
+}
+
+// CreateTaskFromTemplate handles POST /api/v1/tasks/from-template/:template_id
+// @Summary Create a task from a template
+// @Description Create a task with the template's subtasks, labels and custom fields in one transaction. The task is due due_in_days from now unless due_date is given
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param X-User-ID header string false "ID of the user making the change"
+// @Param template_id path string true "Template ID"
+// @Param overrides body models.CreateTaskFromTemplateRequest false "Values overriding the template"
+// @Success 201 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/from-template/{template_id} [post]
+func (h *TemplateHandler) CreateTaskFromTemplate(c *gin.Context) {
+	var req models.CreateTaskFromTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	task, err := h.templateService.CreateTaskFromTemplate(workspaceID(c), actorID(c), c.Param("template_id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Template not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, task)
+}
+
+// SaveTaskAsTemplate handles POST /api/v1/tasks/:id/template
+// @Summary Save a task as a template
+// @Description Create a template from a task, its subtask tree, labels and custom field values
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param template body models.SaveTaskAsTemplateRequest true "Template name"
+// @Success 201 {object} models.TaskTemplate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/template [post]
+func (h *TemplateHandler) SaveTaskAsTemplate(c *gin.Context) {
+	var req models.SaveTaskAsTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.templateService.SaveTaskAsTemplate(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
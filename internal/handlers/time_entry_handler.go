@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type TimeEntryHandler struct {
+	timeEntryService *service.TimeEntryService
+}
+
+func NewTimeEntryHandler(timeEntryService *service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{timeEntryService: timeEntryService}
+}
+
+// StartTimer handles POST /api/v1/tasks/:id/timer/start
+// @Summary Start a timer
+// @Description Start tracking time on a task, optionally on one of its subtasks. A user can only have one running timer
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param timer body models.StartTimerRequest true "Timer details"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "User already has a running timer"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	var req models.StartTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.timeEntryService.StartTimer(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// StopTimer handles POST /api/v1/tasks/:id/timer/stop
+// @Summary Stop a timer
+// @Description Stop the user's running timer on a task
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param timer body models.StopTimerRequest true "Whose timer to stop"
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No running timer of the user on the task"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	var req models.StopTimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.timeEntryService.StopTimer(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "No running timer of the user on this task")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// GetRunningTimer handles GET /api/v1/users/:id/timer
+// @Summary Get a user's running timer
+// @Description Get the timer the user is currently running in the workspace
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "User ID"
+// @Success 200 {object} models.TimeEntry
+// @Failure 404 {object} models.ErrorResponse "No running timer"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/timer [get]
+func (h *TimeEntryHandler) GetRunningTimer(c *gin.Context) {
+	entry, err := h.timeEntryService.GetRunningTimer(workspaceID(c), c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "No running timer")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// CreateTimeEntry handles POST /api/v1/tasks/:id/time-entries
+// @Summary Log time manually
+// @Description Add a finished time entry to a task
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param entry body models.CreateTimeEntryRequest true "Time entry details"
+// @Success 201 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/time-entries [post]
+func (h *TimeEntryHandler) CreateTimeEntry(c *gin.Context) {
+	var req models.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.timeEntryService.CreateTimeEntry(workspaceID(c), c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetTimeEntries handles GET /api/v1/tasks/:id/time-entries
+// @Summary Get a task's time entries
+// @Description Get the time entries of a task overlapping a date range, newest first
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param user_id query string false "Only entries of this user"
+// @Param from query string false "Range start: YYYY-MM-DD or RFC 3339"
+// @Param to query string false "Range end: YYYY-MM-DD (inclusive) or RFC 3339"
+// @Success 200 {object} models.TimeEntriesResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/time-entries [get]
+func (h *TimeEntryHandler) GetTimeEntries(c *gin.Context) {
+	var filters models.TimeRangeFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries, err := h.timeEntryService.GetTimeEntries(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"time_entries": entries})
+}
+
+// UpdateTimeEntry handles PUT /api/v1/tasks/:id/time-entries/:entry_id
+// @Summary Edit a time entry
+// @Description Edit a time entry. Setting ended_at on a running entry stops it
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param entry_id path string true "Time entry ID"
+// @Param entry body models.UpdateTimeEntryRequest true "Updated time entry details"
+// @Success 200 {object} models.TimeEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/time-entries/{entry_id} [put]
+func (h *TimeEntryHandler) UpdateTimeEntry(c *gin.Context) {
+	var req models.UpdateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, err := h.timeEntryService.UpdateTimeEntry(workspaceID(c), c.Param("id"), c.Param("entry_id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Time entry not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// DeleteTimeEntry handles DELETE /api/v1/tasks/:id/time-entries/:entry_id
+// @Summary Delete a time entry
+// @Description Delete a time entry, running or not
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param entry_id path string true "Time entry ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/time-entries/{entry_id} [delete]
+func (h *TimeEntryHandler) DeleteTimeEntry(c *gin.Context) {
+	if err := h.timeEntryService.DeleteTimeEntry(workspaceID(c), c.Param("id"), c.Param("entry_id")); err != nil {
+		writeServiceError(c, err, "Time entry not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Time entry deleted successfully"})
+}
+
+// GetTaskTotals handles GET /api/v1/tasks/:id/time-entries/totals
+// @Summary Get time spent on a task
+// @Description Sum the time spent on a task within a date range, in total and per user. Running timers count up to now
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "Task ID"
+// @Param from query string false "Range start: YYYY-MM-DD or RFC 3339"
+// @Param to query string false "Range end: YYYY-MM-DD (inclusive) or RFC 3339"
+// @Success 200 {object} models.TaskTimeTotals
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/time-entries/totals [get]
+func (h *TimeEntryHandler) GetTaskTotals(c *gin.Context) {
+	var filters models.TimeRangeFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	totals, err := h.timeEntryService.GetTaskTotals(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "Task not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
+
+// GetUserTotals handles GET /api/v1/users/:id/time-entries/totals
+// @Summary Get time spent by a user
+// @Description Sum the time a user spent in the workspace within a date range, in total and per task. Running timers count up to now
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param id path string true "User ID"
+// @Param from query string false "Range start: YYYY-MM-DD or RFC 3339"
+// @Param to query string false "Range end: YYYY-MM-DD (inclusive) or RFC 3339"
+// @Success 200 {object} models.UserTimeTotals
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id}/time-entries/totals [get]
+func (h *TimeEntryHandler) GetUserTotals(c *gin.Context) {
+	var filters models.TimeRangeFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	totals, err := h.timeEntryService.GetUserTotals(workspaceID(c), c.Param("id"), filters)
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, totals)
+}
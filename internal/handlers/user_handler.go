@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHeader is the optional request header that names the user making a change
+const UserHeader = "X-User-ID"
+
+const actorContextKey = "actor_id"
+
+type UserHandler struct {
+	userService *service.UserService
+}
+
+func NewUserHandler(userService *service.UserService) *UserHandler {
+	return &UserHandler{userService: userService}
+}
+
+// IdentifyActor resolves the user named by the optional X-User-ID header and stores it in the
+// request context, so changes can be attributed to them. Requests with an unknown user are rejected
+func (h *UserHandler) IdentifyActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(UserHeader)
+		if id == "" {
+			c.Next()
+			return
+		}
+
+		if _, err := h.userService.GetUser(id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "User not found"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(actorContextKey, id)
+		c.Next()
+	}
+}
+
+// RequireUser is IdentifyActor for endpoints that act on behalf of a user, such as the notification inbox:
+// requests without the X-User-ID header are rejected
+func (h *UserHandler) RequireUser() gin.HandlerFunc {
+	identify := h.IdentifyActor()
+	return func(c *gin.Context) {
+		if c.GetHeader(UserHeader) == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": UserHeader + " header is required"})
+			return
+		}
+		identify(c)
+	}
+}
+
+// actorID returns the user resolved by IdentifyActor or RequireUser, or an empty string for anonymous requests
+func actorID(c *gin.Context) string {
+	return c.GetString(actorContextKey)
+}
+
+// CreateUser handles POST /api/v1/users
+// @Summary Create a new user
+// @Description Create a new user with a name and an optional avatar URL
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body models.CreateUserRequest true "User details"
+// @Success 201 {object} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users [post]
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req models.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.CreateUser(req)
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// GetUsers handles GET /api/v1/users
+// @Summary Get all users
+// @Description Get all users ordered by name
+// @Tags users
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.UsersResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users [get]
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	users, err := h.userService.GetUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// GetUser handles GET /api/v1/users/:id
+// @Summary Get a user by ID
+// @Description Get a specific user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id} [get]
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id := c.Param("id")
+
+	user, err := h.userService.GetUser(id)
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser handles PUT /api/v1/users/:id
+// @Summary Update a user
+// @Description Update an existing user's name or avatar URL
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param user body models.UpdateUserRequest true "Updated user details"
+// @Success 200 {object} models.User
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id} [put]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id := c.Param("id")
+
+	var req models.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.userService.UpdateUser(id, req)
+	if err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser handles DELETE /api/v1/users/:id
+// @Summary Delete a user
+// @Description Delete an existing user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.userService.DeleteUser(id); err != nil {
+		writeServiceError(c, err, "User not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WorkflowHandler struct {
+	workflowService *service.WorkflowService
+}
+
+func NewWorkflowHandler(workflowService *service.WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{workflowService: workflowService}
+}
+
+// GetWorkflow handles GET /api/v1/workflow
+// @Summary Get the workflow
+// @Description Get the task statuses of the workspace in board order and the allowed transitions between them
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Success 200 {object} models.Workflow
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workflow [get]
+func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
+	workflow, err := h.workflowService.GetWorkflow(workspaceID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, workflow)
+}
+
+// UpdateWorkflow handles PUT /api/v1/workflow
+// @Summary Replace the workflow
+// @Description Replace the statuses and transitions of the workspace. Each of the categories todo, doing and done
+// @Description needs a status; without transitions any status change is allowed
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Param X-Workspace-ID header string true "Workspace ID"
+// @Param workflow body models.UpdateWorkflowRequest true "Statuses and transitions"
+// @Success 200 {object} models.Workflow
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "A removed status is still in use"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workflow [put]
+func (h *WorkflowHandler) UpdateWorkflow(c *gin.Context) {
+	var req models.UpdateWorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workflow, err := h.workflowService.UpdateWorkflow(workspaceID(c), req)
+	if err != nil {
+		writeServiceError(c, err, "Workspace not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, workflow)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHeader is the request header that selects the workspace of a tenant-scoped request
+const WorkspaceHeader = "X-Workspace-ID"
+
+const workspaceContextKey = "workspace_id"
+
+type WorkspaceHandler struct {
+	workspaceService *service.WorkspaceService
+}
+
+func NewWorkspaceHandler(workspaceService *service.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceService: workspaceService}
+}
+
+// RequireWorkspace resolves the workspace named by the X-Workspace-ID header and stores it
+// in the request context. Requests without the header or with an unknown workspace are rejected
+func (h *WorkspaceHandler) RequireWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(WorkspaceHeader)
+		if id == "" {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": WorkspaceHeader + " header is required"})
+			return
+		}
+
+		if _, err := h.workspaceService.GetWorkspace(id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Set(workspaceContextKey, id)
+		c.Next()
+	}
+}
+
+// workspaceID returns the workspace resolved by RequireWorkspace
+func workspaceID(c *gin.Context) string {
+	return c.GetString(workspaceContextKey)
+}
+
+// CreateWorkspace handles POST /api/v1/workspaces
+// @Summary Create a new workspace
+// @Description Create a new workspace. Its ID is passed in the X-Workspace-ID header of tenant-scoped requests
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param workspace body models.CreateWorkspaceRequest true "Workspace details"
+// @Success 201 {object} models.Workspace
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	var req models.CreateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.CreateWorkspace(req)
+	if err != nil {
+		writeServiceError(c, err, "Workspace not found")
+		return
+	}
+
+	c.JSON(http.StatusCreated, workspace)
+}
+
+// GetWorkspaces handles GET /api/v1/workspaces
+// @Summary Get all workspaces
+// @Description Get all workspaces ordered by name
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.WorkspacesResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) GetWorkspaces(c *gin.Context) {
+	workspaces, err := h.workspaceService.GetWorkspaces()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+}
+
+// GetWorkspace handles GET /api/v1/workspaces/:id
+// @Summary Get a workspace by ID
+// @Description Get a specific workspace
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} models.Workspace
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workspaces/{id} [get]
+func (h *WorkspaceHandler) GetWorkspace(c *gin.Context) {
+	workspace, err := h.workspaceService.GetWorkspace(c.Param("id"))
+	if err != nil {
+		writeServiceError(c, err, "Workspace not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// UpdateWorkspace handles PUT /api/v1/workspaces/:id
+// @Summary Update a workspace
+// @Description Rename an existing workspace
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Param workspace body models.UpdateWorkspaceRequest true "Updated workspace details"
+// @Success 200 {object} models.Workspace
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workspaces/{id} [put]
+func (h *WorkspaceHandler) UpdateWorkspace(c *gin.Context) {
+	var req models.UpdateWorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace, err := h.workspaceService.UpdateWorkspace(c.Param("id"), req)
+	if err != nil {
+		writeServiceError(c, err, "Workspace not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, workspace)
+}
+
+// DeleteWorkspace handles DELETE /api/v1/workspaces/:id
+// @Summary Delete a workspace
+// @Description Delete a workspace that has no tasks left. Its projects and labels are deleted with it. The default workspace cannot be deleted
+// @Tags workspaces
+// @Accept json
+// @Produce json
+// @Param id path string true "Workspace ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /workspaces/{id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
+	if err := h.workspaceService.DeleteWorkspace(c.Param("id")); err != nil {
+		writeServiceError(c, err, "Workspace not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Workspace deleted successfully"})
+}
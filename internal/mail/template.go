@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Templates renders email messages. Every email has <name>.txt.tmpl and <name>.html.tmpl under
+// templates/, each defining "body" inside the matching layout; the text one also defines "subject"
+type Templates struct {
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+}
+
+// LoadTemplates parses the embedded email templates
+func LoadTemplates() (*Templates, error) {
+	files, err := templateFS.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := &Templates{
+		text: make(map[string]*texttemplate.Template),
+		html: make(map[string]*htmltemplate.Template),
+	}
+	for _, file := range files {
+		name, ok := strings.CutSuffix(file.Name(), ".txt.tmpl")
+		if !ok || name == "layout" {
+			continue
+		}
+
+		text, err := texttemplate.ParseFS(templateFS, "templates/layout.txt.tmpl", "templates/"+name+".txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("mail: failed to parse %s text template: %w", name, err)
+		}
+		html, err := htmltemplate.ParseFS(templateFS, "templates/layout.html.tmpl", "templates/"+name+".html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("mail: failed to parse %s HTML template: %w", name, err)
+		}
+		templates.text[name] = text
+		templates.html[name] = html
+	}
+
+	return templates, nil
+}
+
+// Render renders the email called name for data, addressed to to
+func (t *Templates) Render(name string, to string, data any) (Message, error) {
+	text, ok := t.text[name]
+	if !ok {
+		return Message{}, fmt.Errorf("mail: unknown template %q", name)
+	}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("mail: failed to render %s subject: %w", name, err)
+	}
+	if err := text.ExecuteTemplate(&textBody, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("mail: failed to render %s text: %w", name, err)
+	}
+	if err := t.html[name].ExecuteTemplate(&htmlBody, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("mail: failed to render %s HTML: %w", name, err)
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Attachment describes a file uploaded to a task
+// @Description Metadata of a file attached to a task
+type Attachment struct {
+	ID         string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TaskID     string    `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Filename   string    `json:"filename" db:"filename" example:"design.pdf"`
+	Size       int64     `json:"size" db:"size" example:"52133"`
+	MimeType   string    `json:"mime_type" db:"mime_type" example:"application/pdf"`
+	Checksum   string    `json:"checksum" db:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	StorageKey string    `json:"-" db:"storage_key"`
+	UploadedBy *string   `json:"uploaded_by,omitempty" db:"uploaded_by" example:"123e4567-e89b-12d3-a456-426614174003"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+}
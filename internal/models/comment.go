@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Comment represents a comment left on a task
+// @Description A comment on a task, optionally replying to another comment
+type Comment struct {
+	ID        string     `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TaskID    string     `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ParentID  *string    `json:"parent_id,omitempty" db:"parent_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	AuthorID  *string    `json:"author_id,omitempty" db:"author_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	Body      string     `json:"body" db:"body" example:"Looks good to me"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+	EditedAt  *time.Time `json:"edited_at,omitempty" db:"edited_at" example:"2024-01-01T00:00:00Z"`
+
+	Author  *User     `json:"author,omitempty"`
+	Replies []Comment `json:"replies,omitempty"`
+}
+
+// CreateCommentRequest represents the request body for creating a comment
+// @Description Request body for creating a comment or a reply to another comment
+type CreateCommentRequest struct {
+	AuthorID string  `json:"author_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174003"`
+	ParentID *string `json:"parent_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Body     string  `json:"body" binding:"required" example:"Looks good to me"`
+}
+
+// UpdateCommentRequest represents the request body for editing a comment
+// @Description Request body for editing the text of a comment
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required" example:"Looks good to me, merging"`
+}
+
+type CommentFilters struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+func NewComment(taskID string, req CreateCommentRequest) *Comment {
+	now := time.Now()
+	authorID := req.AuthorID
+	return &Comment{
+		ID:        uuid.New().String(),
+		TaskID:    taskID,
+		ParentID:  req.ParentID,
+		AuthorID:  &authorID,
+		Body:      req.Body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CustomFieldType string
+
+const (
+	CustomFieldText         CustomFieldType = "text"
+	CustomFieldNumber       CustomFieldType = "number"
+	CustomFieldDate         CustomFieldType = "date"
+	CustomFieldSingleSelect CustomFieldType = "single_select"
+	CustomFieldMultiSelect  CustomFieldType = "multi_select"
+	CustomFieldUser         CustomFieldType = "user"
+)
+
+// IsValid reports whether t is one of the supported field types
+func (t CustomFieldType) IsValid() bool {
+	switch t {
+	case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSingleSelect, CustomFieldMultiSelect, CustomFieldUser:
+		return true
+	}
+	return false
+}
+
+// IsSelect reports whether values of the type must be picked from the field's options
+func (t CustomFieldType) IsSelect() bool {
+	return t == CustomFieldSingleSelect || t == CustomFieldMultiSelect
+}
+
+// CustomFieldValues maps a custom field key to its value on a task: a string for text,
+// date (YYYY-MM-DD), single_select and user (user ID) fields, a number for number fields
+// and a list of strings for multi_select fields
+type CustomFieldValues map[string]any
+
+// CustomField is a workspace-defined task attribute
+// @Description A custom field definition. Its key is used in task JSON and in cf.<key> filters
+type CustomField struct {
+	ID          string          `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string          `json:"-" db:"workspace_id"`
+	Key         string          `json:"key" db:"key" example:"customer"`
+	Name        string          `json:"name" db:"name" example:"Customer"`
+	Type        CustomFieldType `json:"type" db:"type" example:"single_select"`
+	Options     []string        `json:"options,omitempty" db:"options" example:"Acme,Globex"`
+	Required    bool            `json:"required" db:"required" example:"false"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// CreateCustomFieldRequest represents the request body for defining a custom field
+// @Description Request body for defining a custom field. Options are required for select types
+type CreateCustomFieldRequest struct {
+	Key      string          `json:"key" binding:"required" example:"customer"`
+	Name     string          `json:"name" binding:"required" example:"Customer"`
+	Type     CustomFieldType `json:"type" binding:"required" example:"single_select"`
+	Options  []string        `json:"options,omitempty" example:"Acme,Globex"`
+	Required bool            `json:"required" example:"false"`
+}
+
+// UpdateCustomFieldRequest represents the request body for updating a custom field.
+// The key and type of a field cannot change
+// @Description Request body for updating an existing custom field
+type UpdateCustomFieldRequest struct {
+	Name     *string   `json:"name,omitempty" example:"Customer"`
+	Options  *[]string `json:"options,omitempty" example:"Acme,Globex,Initech"`
+	Required *bool     `json:"required,omitempty" example:"true"`
+}
+
+func NewCustomField(workspaceID string, req CreateCustomFieldRequest) *CustomField {
+	now := time.Now()
+	return &CustomField{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Key:         req.Key,
+		Name:        req.Name,
+		Type:        req.Type,
+		Options:     req.Options,
+		Required:    req.Required,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
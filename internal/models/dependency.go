@@ -0,0 +1,16 @@
+package models
+
+// TaskRef is a compact reference to another task
+// @Description A short reference to a task
+type TaskRef struct {
+	ID     string     `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Title  string     `json:"title" db:"title" example:"Design database schema"`
+	Status TaskStatus `json:"status" db:"status" example:"in-progress"`
+}
+
+// TaskDependencies lists the tasks a task waits on and the tasks waiting on it
+// @Description Dependency edges of a task in both directions
+type TaskDependencies struct {
+	BlockedBy []TaskRef `json:"blocked_by"`
+	Blocks    []TaskRef `json:"blocks"`
+}
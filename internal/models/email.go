@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// EmailKind is a kind of email a user can opt out of
+type EmailKind string
+
+const (
+	EmailAssignment EmailKind = "assignment"
+	EmailMention    EmailKind = "mention"
+	EmailReminder   EmailKind = "reminder"
+	EmailDigest     EmailKind = "digest"
+)
+
+// EmailKinds lists every email kind, in the order settings show them
+var EmailKinds = []EmailKind{EmailAssignment, EmailMention, EmailReminder, EmailDigest}
+
+// IsValid reports whether k is one of the known email kinds
+func (k EmailKind) IsValid() bool {
+	for _, known := range EmailKinds {
+		if k == known {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationTypes returns the notification types emailed as k; the digest covers all of them
+func (k EmailKind) NotificationTypes() []NotificationType {
+	switch k {
+	case EmailAssignment:
+		return []NotificationType{NotifyTaskAssigned}
+	case EmailMention:
+		return []NotificationType{NotifyMentioned}
+	case EmailReminder:
+		return []NotificationType{NotifyTaskDueSoon, NotifyTaskOverdue}
+	default:
+		return nil
+	}
+}
+
+type EmailStatus string
+
+const (
+	// EmailPending is waiting to be sent, for the first time or again after a failed attempt
+	EmailPending EmailStatus = "pending"
+	EmailSent    EmailStatus = "sent"
+	// EmailFailed ran out of attempts
+	EmailFailed EmailStatus = "failed"
+	// EmailSkipped was not sent because there was nothing to send or nobody to send it to
+	EmailSkipped EmailStatus = "skipped"
+)
+
+// IsValid reports whether s is one of the known delivery statuses
+func (s EmailStatus) IsValid() bool {
+	switch s {
+	case EmailPending, EmailSent, EmailFailed, EmailSkipped:
+		return true
+	}
+	return false
+}
+
+// EmailDelivery is an entry of the email delivery log: an email to a user about a notification,
+// or their daily digest, with the outcome of the attempts to send it
+// @Description An email sent or waiting to be sent to a user
+type EmailDelivery struct {
+	ID             string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID    string    `json:"-" db:"workspace_id"`
+	UserID         string    `json:"user_id" db:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	Kind           EmailKind `json:"kind" db:"kind" example:"assignment"`
+	NotificationID *string   `json:"notification_id,omitempty" db:"notification_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// DigestDate is the day a digest was sent for
+	DigestDate *time.Time  `json:"digest_date,omitempty" db:"digest_date" example:"2024-01-01T00:00:00Z"`
+	To         string      `json:"to" db:"to_address" example:"john@example.com"`
+	Subject    string      `json:"subject" db:"subject" example:"You were assigned to Complete project documentation"`
+	Status     EmailStatus `json:"status" db:"status" example:"sent"`
+	Attempts   int         `json:"attempts" db:"attempts" example:"1"`
+	LastError  string      `json:"last_error,omitempty" db:"last_error" example:"dial tcp: connection refused"`
+	// NextAttemptAt is when a pending email is tried next
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" db:"next_attempt_at" example:"2024-01-01T00:05:00Z"`
+	SentAt        *time.Time `json:"sent_at,omitempty" db:"sent_at" example:"2024-01-01T00:00:05Z"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// EmailDeliveryFilters selects entries of the delivery log
+type EmailDeliveryFilters struct {
+	UserID string      `form:"user_id"`
+	Kind   EmailKind   `form:"kind"`
+	Status EmailStatus `form:"status"`
+	Limit  int         `form:"limit"`
+	Offset int         `form:"offset"`
+}
+
+// EmailSettings are the kinds of email a user does not want to receive
+// @Description Email kinds the user opted out of
+type EmailSettings struct {
+	OptedOut []EmailKind `json:"opted_out" example:"digest"`
+}
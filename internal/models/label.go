@@ -0,0 +1,38 @@
+package models
+
+import "github.com/google/uuid"
+
+// DefaultLabelColor is used when a label is created without a color
+const DefaultLabelColor = "#6b7280"
+
+// Label represents a tag used to group tasks by area
+// @Description A label that can be attached to any number of tasks
+type Label struct {
+	ID          string `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string `json:"-" db:"workspace_id"`
+	Name        string `json:"name" db:"name" example:"backend"`
+	Color       string `json:"color" db:"color" example:"#2563eb"`
+}
+
+// CreateLabelRequest represents the request body for creating a label
+// @Description Request body for creating a label. Color is a #rrggbb hex value
+type CreateLabelRequest struct {
+	Name  string `json:"name" binding:"required" example:"backend"`
+	Color string `json:"color" example:"#2563eb"`
+}
+
+// UpdateLabelRequest represents the request body for updating a label
+// @Description Request body for updating an existing label
+type UpdateLabelRequest struct {
+	Name  *string `json:"name,omitempty" example:"backend"`
+	Color *string `json:"color,omitempty" example:"#2563eb"`
+}
+
+func NewLabel(workspaceID string, req CreateLabelRequest) *Label {
+	return &Label{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Name:        req.Name,
+		Color:       req.Color,
+	}
+}
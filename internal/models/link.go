@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkKind string
+
+const (
+	LinkKindDoc    LinkKind = "doc"
+	LinkKindDesign LinkKind = "design"
+	LinkKindPR     LinkKind = "pr"
+	LinkKindIssue  LinkKind = "issue"
+	LinkKindOther  LinkKind = "other"
+)
+
+// IsValid reports whether k is one of the known link kinds
+func (k LinkKind) IsValid() bool {
+	switch k {
+	case LinkKindDoc, LinkKindDesign, LinkKindPR, LinkKindIssue, LinkKindOther:
+		return true
+	}
+	return false
+}
+
+// TaskLink represents an external resource linked to a task
+// @Description A link from a task to a document, design, pull request or other resource
+type TaskLink struct {
+	ID          string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	TaskID      string    `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	URL         string    `json:"url" db:"url" example:"https://github.com/org/repo/pull/42"`
+	Title       string    `json:"title" db:"title" example:"Add login form"`
+	Description *string   `json:"description,omitempty" db:"description" example:"Frontend part of the auth flow"`
+	Kind        LinkKind  `json:"kind" db:"kind" example:"pr"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// CreateTaskLinkRequest represents the request body for linking a resource to a task
+// @Description Request body for adding a link to a task. Title defaults to the URL host and kind to "other"
+type CreateTaskLinkRequest struct {
+	URL         string   `json:"url" binding:"required" example:"https://github.com/org/repo/pull/42"`
+	Title       string   `json:"title" example:"Add login form"`
+	Description *string  `json:"description,omitempty" example:"Frontend part of the auth flow"`
+	Kind        LinkKind `json:"kind" example:"pr"`
+}
+
+// UpdateTaskLinkRequest represents the request body for updating a task link
+// @Description Request body for updating an existing task link
+type UpdateTaskLinkRequest struct {
+	URL         *string   `json:"url,omitempty" example:"https://github.com/org/repo/pull/42"`
+	Title       *string   `json:"title,omitempty" example:"Add login form"`
+	Description *string   `json:"description,omitempty" example:"Frontend part of the auth flow"`
+	Kind        *LinkKind `json:"kind,omitempty" example:"pr"`
+}
+
+func NewTaskLink(taskID string, req CreateTaskLinkRequest) *TaskLink {
+	now := time.Now()
+	return &TaskLink{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		URL:         req.URL,
+		Title:       req.Title,
+		Description: req.Description,
+		Kind:        req.Kind,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
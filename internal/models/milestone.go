@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Milestone marks a target date that tasks work towards, optionally within a project
+// @Description A milestone with its task counts per status and completion percentage
+type Milestone struct {
+	ID          string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string    `json:"workspace_id" db:"workspace_id" example:"default"`
+	ProjectID   *string   `json:"project_id,omitempty" db:"project_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name        string    `json:"name" db:"name" example:"Public beta"`
+	Description *string   `json:"description,omitempty" db:"description" example:"Feature complete and open to beta testers"`
+	DueDate     time.Time `json:"due_date" db:"due_date" example:"2024-03-01T00:00:00Z"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	// Progress is the percentage of the milestone's tasks that are in a done status
+	TaskCounts map[TaskStatus]int `json:"task_counts"`
+	TotalTasks int                `json:"total_tasks"`
+	Progress   int                `json:"progress"`
+}
+
+// CreateMilestoneRequest represents the request body for creating a milestone
+// @Description Request body for creating a milestone
+type CreateMilestoneRequest struct {
+	ProjectID   *string   `json:"project_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name        string    `json:"name" binding:"required" example:"Public beta"`
+	Description *string   `json:"description,omitempty" example:"Feature complete and open to beta testers"`
+	DueDate     time.Time `json:"due_date" binding:"required" example:"2024-03-01T00:00:00Z"`
+}
+
+// UpdateMilestoneRequest represents the request body for updating a milestone
+// @Description Request body for updating an existing milestone
+type UpdateMilestoneRequest struct {
+	Name        *string    `json:"name,omitempty" example:"Public beta"`
+	Description *string    `json:"description,omitempty" example:"Feature complete and open to beta testers"`
+	DueDate     *time.Time `json:"due_date,omitempty" example:"2024-03-01T00:00:00Z"`
+}
+
+func NewMilestone(workspaceID string, req CreateMilestoneRequest) *Milestone {
+	now := time.Now()
+	return &Milestone{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		Description: req.Description,
+		DueDate:     req.DueDate,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotifyTaskAssigned   NotificationType = "task_assigned"
+	NotifyTaskUpdated    NotificationType = "task_updated"
+	NotifyTaskDeleted    NotificationType = "task_deleted"
+	NotifyTaskRestored   NotificationType = "task_restored"
+	NotifySubTaskChanged NotificationType = "subtask_changed"
+	NotifyCommentAdded   NotificationType = "comment_added"
+	NotifyMentioned      NotificationType = "mentioned"
+	NotifyTaskDueSoon    NotificationType = "task_due_soon"
+	NotifyTaskOverdue    NotificationType = "task_overdue"
+)
+
+// NotificationTypes lists every notification type, in the order settings show them
+var NotificationTypes = []NotificationType{
+	NotifyTaskAssigned, NotifyTaskUpdated, NotifyTaskDeleted, NotifyTaskRestored, NotifySubTaskChanged, NotifyCommentAdded, NotifyMentioned,
+	NotifyTaskDueSoon, NotifyTaskOverdue,
+}
+
+// IsValid reports whether t is one of the known notification types
+func (t NotificationType) IsValid() bool {
+	for _, known := range NotificationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification represents an entry in a user's inbox about a task they watch
+// @Description A notification about a change to a watched task
+type Notification struct {
+	ID          string           `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string           `json:"-" db:"workspace_id"`
+	UserID      string           `json:"user_id" db:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	TaskID      string           `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	SubTaskID   *string          `json:"subtask_id,omitempty" db:"subtask_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	CommentID   *string          `json:"comment_id,omitempty" db:"comment_id" example:"123e4567-e89b-12d3-a456-426614174004"`
+	Type        NotificationType `json:"type" db:"type" example:"task_updated"`
+	// Action is what happened to the task or subtask, as in the audit log
+	Action  ActivityAction `json:"action,omitempty" db:"action" example:"updated"`
+	ActorID *string        `json:"actor_id,omitempty" db:"actor_id" example:"123e4567-e89b-12d3-a456-426614174005"`
+	Changes []FieldChange  `json:"changes" db:"changes"`
+	// DueDate is the due date a reminder was sent for
+	DueDate   *time.Time `json:"due_date,omitempty" db:"due_date" example:"2024-01-02T00:00:00Z"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at" example:"2024-01-01T00:00:00Z"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+
+	TaskTitle string `json:"task_title" example:"Complete project documentation"`
+	Actor     *User  `json:"actor,omitempty"`
+}
+
+// NotificationFilters selects notifications of the inbox
+type NotificationFilters struct {
+	Unread bool             `form:"unread"`
+	Type   NotificationType `form:"type"`
+	Limit  int              `form:"limit"`
+	Offset int              `form:"offset"`
+}
+
+// UnreadCount is the number of unread notifications of a user, in total and per type
+// @Description Unread notifications of the user, in total and per type
+type UnreadCount struct {
+	Total  int                      `json:"total" example:"3"`
+	ByType map[NotificationType]int `json:"by_type"`
+}
+
+// NotificationSettings are the notification types a user does not want to receive
+// @Description Notification types the user has muted in the workspace
+type NotificationSettings struct {
+	MutedTypes []NotificationType `json:"muted_types" example:"subtask_changed"`
+}
+
+// NewNotification builds a notification about a task; each recipient gets a copy with its own ID when it is stored
+func NewNotification(workspaceID string, taskID string, notificationType NotificationType, actorID string) *Notification {
+	var actor *string
+	if actorID != "" {
+		actor = &actorID
+	}
+	return &Notification{
+		WorkspaceID: workspaceID,
+		TaskID:      taskID,
+		Type:        notificationType,
+		ActorID:     actor,
+		Changes:     []FieldChange{},
+		CreatedAt:   time.Now(),
+	}
+}
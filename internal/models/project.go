@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project groups tasks that belong to one team or board
+// @Description A project that groups tasks, with task counts per status
+type Project struct {
+	ID          string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string    `json:"workspace_id" db:"workspace_id" example:"default"`
+	Name        string    `json:"name" db:"name" example:"Mobile app"`
+	Description *string   `json:"description,omitempty" db:"description" example:"iOS and Android clients"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	TaskCounts map[TaskStatus]int `json:"task_counts"`
+	TotalTasks int                `json:"total_tasks"`
+}
+
+// CreateProjectRequest represents the request body for creating a project
+// @Description Request body for creating a project
+type CreateProjectRequest struct {
+	Name        string  `json:"name" binding:"required" example:"Mobile app"`
+	Description *string `json:"description,omitempty" example:"iOS and Android clients"`
+}
+
+// UpdateProjectRequest represents the request body for updating a project
+// @Description Request body for updating an existing project
+type UpdateProjectRequest struct {
+	Name        *string `json:"name,omitempty" example:"Mobile app"`
+	Description *string `json:"description,omitempty" example:"iOS and Android clients"`
+}
+
+func NewProject(workspaceID string, req CreateProjectRequest) *Project {
+	now := time.Now()
+	return &Project{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// TaskReminder records a due-date reminder sent for a task, so it is sent once per due date.
+// Offset is how long before the due date it fired; zero is the overdue reminder
+type TaskReminder struct {
+	WorkspaceID string        `json:"-" db:"workspace_id"`
+	TaskID      string        `json:"task_id" db:"task_id"`
+	DueDate     time.Time     `json:"due_date" db:"due_date"`
+	Offset      time.Duration `json:"-" db:"offset_minutes"`
+	SentAt      time.Time     `json:"sent_at" db:"sent_at"`
+}
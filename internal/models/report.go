@@ -0,0 +1,26 @@
+package models
+
+// BurndownFilters selects the days of a burndown report. Both dates are YYYY-MM-DD and inclusive;
+// by default the report covers the last 14 days up to today
+type BurndownFilters struct {
+	From string `form:"from"`
+	To   string `form:"to"`
+}
+
+// BurndownDay is the effort left at the end of a day, next to the ideal straight-line burndown
+type BurndownDay struct {
+	Date            string  `json:"date" example:"2024-01-01"`
+	RemainingHours  float64 `json:"remaining_hours" example:"42.5"`
+	RemainingPoints int     `json:"remaining_points" example:"21"`
+	IdealHours      float64 `json:"ideal_hours" example:"40"`
+	IdealPoints     float64 `json:"ideal_points" example:"20"`
+}
+
+// BurndownReport is the daily remaining effort of the tasks matched by the task filters
+// @Description Daily remaining effort in hours and story points
+type BurndownReport struct {
+	From  string        `json:"from" example:"2024-01-01"`
+	To    string        `json:"to" example:"2024-01-14"`
+	Tasks int           `json:"tasks" example:"12"`
+	Days  []BurndownDay `json:"days"`
+}
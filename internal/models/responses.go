@@ -17,3 +17,119 @@ type TasksResponse struct {
 type SubTasksResponse struct {
 	SubTasks []SubTask `json:"subtasks"`
 }
+
+// TrashResponse represents the tasks and subtasks in the trash of a workspace
+// @Description Response body containing the deleted tasks and subtasks that can still be restored
+type TrashResponse struct {
+	Tasks    []Task    `json:"tasks"`
+	SubTasks []SubTask `json:"subtasks"`
+}
+
+// UsersResponse represents the response body for getting users
+// @Description Response body containing a list of users
+type UsersResponse struct {
+	Users []User `json:"users"`
+}
+
+// CommentsResponse represents a page of top-level comments with their replies
+// @Description Response body containing a page of comment threads
+type CommentsResponse struct {
+	Comments []Comment `json:"comments"`
+	Total    int       `json:"total"`
+}
+
+// AttachmentsResponse represents the response body for listing a task's attachments
+// @Description Response body containing a list of attachments
+type AttachmentsResponse struct {
+	Attachments []Attachment `json:"attachments"`
+}
+
+// TaskLinksResponse represents the response body for listing a task's links
+// @Description Response body containing a list of task links
+type TaskLinksResponse struct {
+	Links []TaskLink `json:"links"`
+}
+
+// LabelsResponse represents the response body for listing labels
+// @Description Response body containing a list of labels
+type LabelsResponse struct {
+	Labels []Label `json:"labels"`
+}
+
+// CustomFieldsResponse represents the response body for listing custom field definitions
+// @Description Response body containing a list of custom fields
+type CustomFieldsResponse struct {
+	CustomFields []CustomField `json:"custom_fields"`
+}
+
+// TaskTemplatesResponse represents the response body for listing task templates
+// @Description Response body containing a list of task templates
+type TaskTemplatesResponse struct {
+	Templates []TaskTemplate `json:"templates"`
+}
+
+// TimeEntriesResponse represents the response body for listing time entries
+// @Description Response body containing a list of time entries
+type TimeEntriesResponse struct {
+	TimeEntries []TimeEntry `json:"time_entries"`
+}
+
+// ProjectsResponse represents the response body for listing projects
+// @Description Response body containing a list of projects
+type ProjectsResponse struct {
+	Projects []Project `json:"projects"`
+}
+
+// SprintsResponse represents the response body for listing sprints
+// @Description Response body containing a list of sprints
+type SprintsResponse struct {
+	Sprints []Sprint `json:"sprints"`
+}
+
+// MilestonesResponse represents the response body for listing milestones
+// @Description Response body containing a list of milestones
+type MilestonesResponse struct {
+	Milestones []Milestone `json:"milestones"`
+}
+
+// WorkspacesResponse represents the response body for getting workspaces
+// @Description Response body containing a list of workspaces
+type WorkspacesResponse struct {
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// ActivitiesResponse represents a page of audit log entries
+// @Description Response body containing audit log entries, newest first
+type ActivitiesResponse struct {
+	Activities []Activity `json:"activities"`
+}
+
+// RevisionsResponse represents a list of revisions of a task
+// @Description Response body containing the revisions of a task without their snapshots
+type RevisionsResponse struct {
+	Revisions []TaskRevision `json:"revisions"`
+}
+
+// WatchersResponse represents the users watching a task
+// @Description Response body containing the watchers of a task
+type WatchersResponse struct {
+	Watchers []User `json:"watchers"`
+}
+
+// NotificationsResponse represents a page of a user's notification inbox
+// @Description Response body containing notifications, newest first
+type NotificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// MarkedReadResponse represents how many notifications were marked as read
+// @Description Response body containing the number of notifications marked as read
+type MarkedReadResponse struct {
+	Marked int `json:"marked" example:"5"`
+}
+
+// EmailDeliveriesResponse represents a page of the email delivery log
+// @Description Response body containing emails sent or waiting to be sent, newest first
+type EmailDeliveriesResponse struct {
+	Deliveries []EmailDelivery `json:"deliveries"`
+}
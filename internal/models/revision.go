@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskRevision is a numbered snapshot of a task taken after one of its writes
+// @Description A revision of a task: the task with its subtasks as they were after a change
+type TaskRevision struct {
+	ID          string  `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string  `json:"-" db:"workspace_id"`
+	TaskID      string  `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Revision    int     `json:"revision" db:"revision" example:"3"`
+	ActorID     *string `json:"actor_id,omitempty" db:"actor_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	// RestoredFrom is the revision a restore brought the task back to
+	RestoredFrom *int      `json:"restored_from,omitempty" db:"restored_from" example:"1"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+
+	Actor *User `json:"actor,omitempty"`
+
+	// Snapshot is the task with its subtasks in tree order; it is left out of revision lists
+	Snapshot *Task `json:"snapshot,omitempty" db:"snapshot"`
+}
+
+// SubTaskDiff describes how one subtask differs between two revisions
+type SubTaskDiff struct {
+	SubTaskID string `json:"subtask_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Title     string `json:"title" example:"Implement user authentication"`
+	// Change is added, removed or updated
+	Change  string        `json:"change" example:"updated"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// RevisionDiff lists the changes that lead from one revision of a task to another
+// @Description The field changes of a task and its subtasks between two revisions
+type RevisionDiff struct {
+	TaskID string `json:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	// From is 0 when the diff starts from nothing, as for the first revision
+	From     int           `json:"from" example:"2"`
+	To       int           `json:"to" example:"3"`
+	Changes  []FieldChange `json:"changes"`
+	SubTasks []SubTaskDiff `json:"sub_tasks"`
+}
+
+func NewTaskRevision(workspaceID string, taskID string, actorID string, snapshot *Task) *TaskRevision {
+	var actor *string
+	if actorID != "" {
+		actor = &actorID
+	}
+	return &TaskRevision{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		TaskID:      taskID,
+		ActorID:     actor,
+		CreatedAt:   time.Now(),
+		Snapshot:    snapshot,
+	}
+}
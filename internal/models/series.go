@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// TaskSeries links the occurrences of a recurring task.
+// CurrentTaskID is the latest occurrence; the next one is spawned from it
+type TaskSeries struct {
+	ID            string    `json:"id" db:"id"`
+	WorkspaceID   string    `json:"workspace_id" db:"workspace_id"`
+	RRule         string    `json:"rrule" db:"rrule"`
+	StartDate     time.Time `json:"start_date" db:"start_date"`
+	Occurrences   int       `json:"occurrences" db:"occurrences"`
+	CurrentTaskID *string   `json:"current_task_id,omitempty" db:"current_task_id"`
+	Active        bool      `json:"active" db:"active"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SprintState string
+
+const (
+	SprintPlanned SprintState = "planned"
+	SprintActive  SprintState = "active"
+	SprintClosed  SprintState = "closed"
+)
+
+// IsValid reports whether s is one of the known sprint states
+func (s SprintState) IsValid() bool {
+	switch s {
+	case SprintPlanned, SprintActive, SprintClosed:
+		return true
+	}
+	return false
+}
+
+// Sprint is a time box of work, optionally scoped to a project. A sprint is planned,
+// then started and finally closed; only one sprint per project can be active at a time
+// @Description A sprint with its task counts per status and completion percentage
+type Sprint struct {
+	ID          string      `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string      `json:"workspace_id" db:"workspace_id" example:"default"`
+	ProjectID   *string     `json:"project_id,omitempty" db:"project_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name        string      `json:"name" db:"name" example:"Sprint 14"`
+	Goal        *string     `json:"goal,omitempty" db:"goal" example:"Ship the new onboarding"`
+	StartDate   time.Time   `json:"start_date" db:"start_date" example:"2024-01-01T00:00:00Z"`
+	EndDate     time.Time   `json:"end_date" db:"end_date" example:"2024-01-14T00:00:00Z"`
+	State       SprintState `json:"state" db:"state" example:"planned"`
+	StartedAt   *time.Time  `json:"started_at,omitempty" db:"started_at" example:"2024-01-01T09:00:00Z"`
+	ClosedAt    *time.Time  `json:"closed_at,omitempty" db:"closed_at" example:"2024-01-14T17:00:00Z"`
+	// CarriedOver is the number of unfinished tasks moved out of the sprint when it was closed
+	CarriedOver int       `json:"carried_over" db:"carried_over" example:"3"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	// Progress is the percentage of the sprint's tasks that are in a done status
+	TaskCounts map[TaskStatus]int `json:"task_counts"`
+	TotalTasks int                `json:"total_tasks"`
+	Progress   int                `json:"progress"`
+}
+
+// CreateSprintRequest represents the request body for planning a sprint
+// @Description Request body for creating a sprint in the planned state
+type CreateSprintRequest struct {
+	ProjectID *string   `json:"project_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Name      string    `json:"name" binding:"required" example:"Sprint 14"`
+	Goal      *string   `json:"goal,omitempty" example:"Ship the new onboarding"`
+	StartDate time.Time `json:"start_date" binding:"required" example:"2024-01-01T00:00:00Z"`
+	EndDate   time.Time `json:"end_date" binding:"required" example:"2024-01-14T00:00:00Z"`
+}
+
+// UpdateSprintRequest represents the request body for updating a sprint.
+// The dates of a closed sprint cannot be changed
+// @Description Request body for updating an existing sprint
+type UpdateSprintRequest struct {
+	Name      *string    `json:"name,omitempty" example:"Sprint 14"`
+	Goal      *string    `json:"goal,omitempty" example:"Ship the new onboarding"`
+	StartDate *time.Time `json:"start_date,omitempty" example:"2024-01-01T00:00:00Z"`
+	EndDate   *time.Time `json:"end_date,omitempty" example:"2024-01-14T00:00:00Z"`
+}
+
+// CloseSprintRequest represents the request body for closing a sprint.
+// Unfinished tasks move to carry_over_to; without it they move to the project's next planned
+// sprint, and an empty carry_over_to sends them back to the backlog
+// @Description Request body for closing the active sprint
+type CloseSprintRequest struct {
+	CarryOverTo *string `json:"carry_over_to,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// CloseSprintResponse is the closed sprint and where its unfinished tasks went
+// @Description A closed sprint and the carry-over of its unfinished tasks
+type CloseSprintResponse struct {
+	Sprint        *Sprint `json:"sprint"`
+	CarriedOver   int     `json:"carried_over" example:"3"`
+	CarriedOverTo *string `json:"carried_over_to,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+}
+
+// SprintFilters narrows the list of sprints
+type SprintFilters struct {
+	State     SprintState `form:"state"`
+	ProjectID string      `form:"project_id"`
+}
+
+func NewSprint(workspaceID string, req CreateSprintRequest) *Sprint {
+	now := time.Now()
+	return &Sprint{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		ProjectID:   req.ProjectID,
+		Name:        req.Name,
+		Goal:        req.Goal,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		State:       SprintPlanned,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
@@ -14,95 +14,208 @@ const (
 	StatusInProgress TaskStatus = "in-progress"
 )
 
+type TaskPriority string
+
+const (
+	PriorityNone   TaskPriority = "none"
+	PriorityLow    TaskPriority = "low"
+	PriorityMedium TaskPriority = "medium"
+	PriorityHigh   TaskPriority = "high"
+	PriorityUrgent TaskPriority = "urgent"
+)
+
+// IsValid reports whether p is one of the known priority levels
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
 type User struct {
 	ID   string `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
 	Name string `json:"name" db:"name" example:"John Doe"`
 	Src  string `json:"src" db:"src" example:"https://avatars.githubusercontent.com/u/124599?v=4"`
+	// Email is where email notifications are sent; it is only loaded with the user itself
+	Email string `json:"email,omitempty" db:"email" example:"john@example.com"`
 }
 
 // SubTask represents a subtask within a task
-// @Description A subtask that belongs to a parent task
+// @Description A subtask that belongs to a parent task and optionally to a parent subtask
 type SubTask struct {
-	ID          string     `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
-	TaskID      string     `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
-	Title       string     `json:"title" db:"title" example:"Implement user authentication"`
-	Description *string    `json:"description,omitempty" db:"description" example:"Add JWT token authentication"`
-	Status      TaskStatus `json:"status" db:"status" example:"not-started"`
-	Order       int        `json:"order" db:"order" example:"1"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+	ID              string     `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID     string     `json:"-" db:"workspace_id"`
+	TaskID          string     `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	ParentSubTaskID *string    `json:"parent_subtask_id,omitempty" db:"parent_subtask_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Title           string     `json:"title" db:"title" example:"Implement user authentication"`
+	Description     *string    `json:"description,omitempty" db:"description" example:"Add JWT token authentication"`
+	Status          TaskStatus `json:"status" db:"status" example:"not-started"`
+	Order           int        `json:"order" db:"order" example:"1"`
+	EstimateHours   *float64   `json:"estimate_hours,omitempty" db:"estimate_hours" example:"4"`
+	StoryPoints     *int       `json:"story_points,omitempty" db:"story_points" example:"3"`
+	RemainingHours  *float64   `json:"remaining_hours,omitempty" db:"remaining_hours" example:"2.5"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	// StatusCategory is the workflow category of the status: todo, doing or done
+	StatusCategory StatusCategory `json:"status_category" db:"status_category" example:"todo"`
+
+	// DeletedAt is only set on subtasks in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at" example:"2024-01-02T00:00:00Z"`
+
+	// Children is only filled in when subtasks are returned as a tree
+	Children []SubTask `json:"children,omitempty"`
 }
 
 type Task struct {
-	ID          string     `json:"id" db:"id"`
-	Title       string     `json:"title" db:"title"`
-	IconName    string     `json:"icon_name" db:"icon_name"`
-	StartTime   *string    `json:"start_time,omitempty" db:"start_time"`
-	EndTime     *string    `json:"end_time,omitempty" db:"end_time"`
-	DueDate     time.Time  `json:"due_date" db:"due_date"`
-	Progress    int        `json:"progress" db:"progress"`
-	Status      TaskStatus `json:"status" db:"status"`
-	Comments    int        `json:"comments" db:"comments"`
-	Attachments int        `json:"attachments" db:"attachments"`
-	Links       int        `json:"links" db:"links"`
-	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+	ID          string       `json:"id" db:"id"`
+	WorkspaceID string       `json:"workspace_id" db:"workspace_id"`
+	ProjectID   *string      `json:"project_id,omitempty" db:"project_id"`
+	SprintID    *string      `json:"sprint_id,omitempty" db:"sprint_id"`
+	MilestoneID *string      `json:"milestone_id,omitempty" db:"milestone_id"`
+	Title       string       `json:"title" db:"title"`
+	IconName    string       `json:"icon_name" db:"icon_name"`
+	StartTime   *time.Time   `json:"start_time,omitempty" db:"start_time"`
+	EndTime     *time.Time   `json:"end_time,omitempty" db:"end_time"`
+	DueDate     time.Time    `json:"due_date" db:"due_date"`
+	Progress    int          `json:"progress" db:"progress"`
+	Status      TaskStatus   `json:"status" db:"status"`
+	Priority    TaskPriority `json:"priority" db:"priority"`
+	SeriesID    *string      `json:"series_id,omitempty" db:"series_id"`
+	Recurrence  *string      `json:"recurrence,omitempty" db:"recurrence"`
+	Comments    int          `json:"comments" db:"comments"`
+	Attachments int          `json:"attachments" db:"attachments"`
+	Links       int          `json:"links" db:"links"`
+	Blocked     bool         `json:"blocked" db:"blocked"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+
+	// DurationSeconds is derived from start_time and end_time when both are set
+	DurationSeconds *int64 `json:"duration_seconds,omitempty"`
+
+	// StatusCategory is the workflow category of the status: todo, doing or done
+	StatusCategory StatusCategory `json:"status_category" db:"status_category"`
+
+	// DeletedAt is only set on tasks in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// RemainingHours is the effort left; while it is unset the whole estimate counts as remaining
+	EstimateHours  *float64 `json:"estimate_hours,omitempty" db:"estimate_hours"`
+	StoryPoints    *int     `json:"story_points,omitempty" db:"story_points"`
+	RemainingHours *float64 `json:"remaining_hours,omitempty" db:"remaining_hours"`
+
+	CustomFields CustomFieldValues `json:"custom_fields" db:"custom_fields"`
 
 	Users    []User    `json:"users,omitempty"`
+	Labels   []Label   `json:"labels,omitempty"`
 	SubTasks []SubTask `json:"sub_tasks,omitempty"`
 }
 
 type CreateTaskRequest struct {
-	Title     string     `json:"title" binding:"required"`
-	IconName  string     `json:"icon_name" binding:"required"`
-	StartTime *string    `json:"start_time,omitempty"`
-	EndTime   *string    `json:"end_time,omitempty"`
-	DueDate   time.Time  `json:"due_date" binding:"required"`
-	Status    TaskStatus `json:"status" binding:"required"`
-	UserIDs   []string   `json:"user_ids,omitempty"`
+	ProjectID   *string `json:"project_id,omitempty"`
+	SprintID    *string `json:"sprint_id,omitempty"`
+	MilestoneID *string `json:"milestone_id,omitempty"`
+	Title       string  `json:"title" binding:"required"`
+	IconName    string  `json:"icon_name" binding:"required"`
+	// StartTime and EndTime are RFC 3339 timestamps; start_time < end_time <= due_date
+	StartTime *time.Time   `json:"start_time,omitempty"`
+	EndTime   *time.Time   `json:"end_time,omitempty"`
+	DueDate   time.Time    `json:"due_date" binding:"required"`
+	Status    TaskStatus   `json:"status" binding:"required"`
+	Priority  TaskPriority `json:"priority,omitempty"`
+	// Estimates must not be negative; remaining_hours defaults to the estimate
+	EstimateHours  *float64 `json:"estimate_hours,omitempty"`
+	StoryPoints    *int     `json:"story_points,omitempty"`
+	RemainingHours *float64 `json:"remaining_hours,omitempty"`
+	// Recurrence is an RRULE such as "FREQ=WEEKLY;BYDAY=MO". The due date is the first occurrence
+	Recurrence   *string           `json:"recurrence,omitempty"`
+	UserIDs      []string          `json:"user_ids,omitempty"`
+	LabelIDs     []string          `json:"label_ids,omitempty"`
+	CustomFields CustomFieldValues `json:"custom_fields,omitempty"`
 }
 
 type UpdateTaskRequest struct {
-	Title       *string     `json:"title,omitempty"`
-	IconName    *string     `json:"icon_name,omitempty"`
-	StartTime   *string     `json:"start_time,omitempty"`
-	EndTime     *string     `json:"end_time,omitempty"`
-	DueDate     *time.Time  `json:"due_date,omitempty"`
-	Progress    *int        `json:"progress,omitempty"`
-	Status      *TaskStatus `json:"status,omitempty"`
-	Comments    *int        `json:"comments,omitempty"`
-	Attachments *int        `json:"attachments,omitempty"`
-	Links       *int        `json:"links,omitempty"`
+	ProjectID *string `json:"project_id,omitempty"`
+	// SprintID and MilestoneID move the task; an empty string removes it from its sprint or milestone
+	SprintID       *string       `json:"sprint_id,omitempty"`
+	MilestoneID    *string       `json:"milestone_id,omitempty"`
+	Title          *string       `json:"title,omitempty"`
+	IconName       *string       `json:"icon_name,omitempty"`
+	StartTime      *time.Time    `json:"start_time,omitempty"`
+	EndTime        *time.Time    `json:"end_time,omitempty"`
+	DueDate        *time.Time    `json:"due_date,omitempty"`
+	Progress       *int          `json:"progress,omitempty"`
+	Status         *TaskStatus   `json:"status,omitempty"`
+	Priority       *TaskPriority `json:"priority,omitempty"`
+	EstimateHours  *float64      `json:"estimate_hours,omitempty"`
+	StoryPoints    *int          `json:"story_points,omitempty"`
+	RemainingHours *float64      `json:"remaining_hours,omitempty"`
+	// Recurrence sets or replaces the task's RRULE; an empty string stops the series
+	Recurrence *string `json:"recurrence,omitempty"`
+	// CustomFields sets the given custom field values; a null value clears the field
+	CustomFields *CustomFieldValues `json:"custom_fields,omitempty"`
+	// ClearSchedule removes start_time and end_time; it cannot be combined with either of them
+	ClearSchedule bool `json:"clear_schedule,omitempty"`
 }
 
 // CreateSubTaskRequest represents the request body for creating a new subtask
 // @Description Request body for creating a new subtask
 type CreateSubTaskRequest struct {
-	Title       string     `json:"title" binding:"required" example:"Implement user authentication"`
-	Description *string    `json:"description,omitempty" example:"Add JWT token authentication"`
-	Status      TaskStatus `json:"status" binding:"required" example:"not-started"`
+	ParentSubTaskID *string    `json:"parent_subtask_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Title           string     `json:"title" binding:"required" example:"Implement user authentication"`
+	Description     *string    `json:"description,omitempty" example:"Add JWT token authentication"`
+	Status          TaskStatus `json:"status" binding:"required" example:"not-started"`
+	EstimateHours   *float64   `json:"estimate_hours,omitempty" example:"4"`
+	StoryPoints     *int       `json:"story_points,omitempty" example:"3"`
+	RemainingHours  *float64   `json:"remaining_hours,omitempty" example:"2.5"`
 }
 
 // UpdateSubTaskRequest represents the request body for updating an existing subtask
 // @Description Request body for updating an existing subtask
 type UpdateSubTaskRequest struct {
-	Title       *string     `json:"title,omitempty" example:"Implement user authentication"`
-	Description *string     `json:"description,omitempty" example:"Add JWT token authentication"`
-	Status      *TaskStatus `json:"status,omitempty" example:"in-progress"`
+	Title          *string     `json:"title,omitempty" example:"Implement user authentication"`
+	Description    *string     `json:"description,omitempty" example:"Add JWT token authentication"`
+	Status         *TaskStatus `json:"status,omitempty" example:"in-progress"`
+	EstimateHours  *float64    `json:"estimate_hours,omitempty" example:"4"`
+	StoryPoints    *int        `json:"story_points,omitempty" example:"3"`
+	RemainingHours *float64    `json:"remaining_hours,omitempty" example:"2.5"`
 }
 
 type TaskFilters struct {
-	Status   TaskStatus `form:"status"`
-	SortBy   string     `form:"sort_by"`
-	SortType string     `form:"sort_type"`
-	Limit    int        `form:"limit"`
-	Offset   int        `form:"offset"`
+	ProjectID   string         `form:"project_id"`
+	SprintID    string         `form:"sprint_id"`
+	MilestoneID string         `form:"milestone_id"`
+	Status      TaskStatus     `form:"status"`
+	Category    StatusCategory `form:"category"`
+	Priority    TaskPriority   `form:"priority"`
+	UserID      string         `form:"user_id"`
+	SeriesID    string         `form:"series_id"`
+	Labels      []string       `form:"label"`
+	LabelMatch  string         `form:"label_match"`
+	SortBy      string         `form:"sort_by"`
+	SortType    string         `form:"sort_type"`
+	Limit       int            `form:"limit"`
+	Offset      int            `form:"offset"`
+
+	// CustomFields holds the cf.<key>=value filters of the query string, keyed by field key
+	CustomFields map[string]string `form:"-"`
+	// CustomFieldMatch and SortNumeric are resolved from the field definitions by the service
+	CustomFieldMatch string `form:"-"`
+	SortNumeric      bool   `form:"-"`
 }
 
-func NewTask(req CreateTaskRequest) *Task {
+// CustomFieldPrefix marks a query parameter or sort_by value that refers to a custom field key
+const CustomFieldPrefix = "cf."
+
+func NewTask(workspaceID string, req CreateTaskRequest) *Task {
 	now := time.Now()
 	return &Task{
 		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		ProjectID:   req.ProjectID,
+		SprintID:    req.SprintID,
+		MilestoneID: req.MilestoneID,
 		Title:       req.Title,
 		IconName:    req.IconName,
 		StartTime:   req.StartTime,
@@ -110,23 +223,35 @@ func NewTask(req CreateTaskRequest) *Task {
 		DueDate:     req.DueDate,
 		Progress:    0,
 		Status:      req.Status,
+		Priority:    req.Priority,
 		Comments:    0,
 		Attachments: 0,
 		Links:       0,
 		CreatedAt:   now,
 		UpdatedAt:   now,
+
+		EstimateHours:  req.EstimateHours,
+		StoryPoints:    req.StoryPoints,
+		RemainingHours: req.RemainingHours,
+
+		CustomFields: req.CustomFields,
 	}
 }
 
-func NewSubTask(taskID string, req CreateSubTaskRequest) *SubTask {
+func NewSubTask(workspaceID string, taskID string, req CreateSubTaskRequest) *SubTask {
 	now := time.Now()
 	return &SubTask{
-		ID:          uuid.New().String(),
-		TaskID:      taskID,
-		Title:       req.Title,
-		Description: req.Description,
-		Status:      req.Status,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:              uuid.New().String(),
+		WorkspaceID:     workspaceID,
+		TaskID:          taskID,
+		ParentSubTaskID: req.ParentSubTaskID,
+		Title:           req.Title,
+		Description:     req.Description,
+		Status:          req.Status,
+		EstimateHours:   req.EstimateHours,
+		StoryPoints:     req.StoryPoints,
+		RemainingHours:  req.RemainingHours,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 }
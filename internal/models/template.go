@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TemplateSubTask is a subtask blueprint inside a task template. Its position in the
+// list becomes the order of the created subtask among its siblings
+type TemplateSubTask struct {
+	Title       string            `json:"title" example:"Tag the release"`
+	Description *string           `json:"description,omitempty" example:"Create an annotated git tag"`
+	Children    []TemplateSubTask `json:"children,omitempty"`
+}
+
+// TaskTemplate captures a task that is created over and over again
+// @Description A reusable task blueprint with subtasks, labels and a relative due date
+type TaskTemplate struct {
+	ID           string            `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID  string            `json:"-" db:"workspace_id"`
+	Name         string            `json:"name" db:"name" example:"Release checklist"`
+	Title        string            `json:"title" db:"title" example:"Release v1.2"`
+	IconName     string            `json:"icon_name" db:"icon_name" example:"rocket"`
+	ProjectID    *string           `json:"project_id,omitempty" db:"project_id"`
+	Priority     TaskPriority      `json:"priority" db:"priority" example:"high"`
+	DueInDays    *int              `json:"due_in_days,omitempty" db:"due_in_days" example:"14"`
+	CustomFields CustomFieldValues `json:"custom_fields" db:"custom_fields"`
+	SubTasks     []TemplateSubTask `json:"sub_tasks" db:"subtasks"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt    time.Time         `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	Labels []Label `json:"labels,omitempty"`
+}
+
+// CreateTaskTemplateRequest represents the request body for creating a task template
+// @Description Request body for creating a task template. due_in_days is counted from the moment a task is created from it
+type CreateTaskTemplateRequest struct {
+	Name         string            `json:"name" binding:"required" example:"Release checklist"`
+	Title        string            `json:"title" binding:"required" example:"Release v1.2"`
+	IconName     string            `json:"icon_name" binding:"required" example:"rocket"`
+	ProjectID    *string           `json:"project_id,omitempty"`
+	Priority     TaskPriority      `json:"priority,omitempty" example:"high"`
+	DueInDays    *int              `json:"due_in_days,omitempty" example:"14"`
+	LabelIDs     []string          `json:"label_ids,omitempty"`
+	CustomFields CustomFieldValues `json:"custom_fields,omitempty"`
+	SubTasks     []TemplateSubTask `json:"sub_tasks,omitempty"`
+}
+
+// UpdateTaskTemplateRequest represents the request body for updating a task template.
+// label_ids, custom_fields and sub_tasks replace the stored values when given
+// @Description Request body for updating an existing task template
+type UpdateTaskTemplateRequest struct {
+	Name         *string            `json:"name,omitempty" example:"Release checklist"`
+	Title        *string            `json:"title,omitempty" example:"Release v1.3"`
+	IconName     *string            `json:"icon_name,omitempty" example:"rocket"`
+	ProjectID    *string            `json:"project_id,omitempty"`
+	Priority     *TaskPriority      `json:"priority,omitempty" example:"urgent"`
+	DueInDays    *int               `json:"due_in_days,omitempty" example:"7"`
+	LabelIDs     *[]string          `json:"label_ids,omitempty"`
+	CustomFields *CustomFieldValues `json:"custom_fields,omitempty"`
+	SubTasks     *[]TemplateSubTask `json:"sub_tasks,omitempty"`
+}
+
+// CreateTaskFromTemplateRequest represents the request body for instantiating a template.
+// Every field is optional and overrides the template's value
+// @Description Request body for creating a task from a template
+type CreateTaskFromTemplateRequest struct {
+	Title     *string    `json:"title,omitempty" example:"Release v1.3"`
+	DueDate   *time.Time `json:"due_date,omitempty" example:"2024-02-01T00:00:00Z"`
+	ProjectID *string    `json:"project_id,omitempty"`
+	UserIDs   []string   `json:"user_ids,omitempty"`
+}
+
+// SaveTaskAsTemplateRequest represents the request body for saving a task as a template
+// @Description Request body for saving an existing task as a template
+type SaveTaskAsTemplateRequest struct {
+	Name string `json:"name" binding:"required" example:"Release checklist"`
+	// DueInDays defaults to the number of days between the task's creation and its due date
+	DueInDays *int `json:"due_in_days,omitempty" example:"14"`
+}
+
+func NewTaskTemplate(workspaceID string, req CreateTaskTemplateRequest) *TaskTemplate {
+	now := time.Now()
+	return &TaskTemplate{
+		ID:           uuid.New().String(),
+		WorkspaceID:  workspaceID,
+		Name:         req.Name,
+		Title:        req.Title,
+		IconName:     req.IconName,
+		ProjectID:    req.ProjectID,
+		Priority:     req.Priority,
+		DueInDays:    req.DueInDays,
+		CustomFields: req.CustomFields,
+		SubTasks:     req.SubTasks,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
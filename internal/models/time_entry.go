@@ -0,0 +1,120 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is a span of time a user spent on a task, optionally on one of its subtasks.
+// An entry without ended_at is a running timer; a user has at most one at a time
+// @Description A tracked span of work on a task
+type TimeEntry struct {
+	ID          string     `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	WorkspaceID string     `json:"-" db:"workspace_id"`
+	UserID      string     `json:"user_id" db:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	TaskID      string     `json:"task_id" db:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	SubTaskID   *string    `json:"subtask_id,omitempty" db:"subtask_id" example:"123e4567-e89b-12d3-a456-426614174002"`
+	StartedAt   time.Time  `json:"started_at" db:"started_at" example:"2024-01-01T09:00:00Z"`
+	EndedAt     *time.Time `json:"ended_at,omitempty" db:"ended_at" example:"2024-01-01T10:30:00Z"`
+	Note        *string    `json:"note,omitempty" db:"note" example:"Code review"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+
+	// Running and DurationSeconds are derived; a running entry counts up to now
+	Running         bool  `json:"running" example:"false"`
+	DurationSeconds int64 `json:"duration_seconds" example:"5400"`
+}
+
+// StartTimerRequest represents the request body for starting a timer on a task
+// @Description Request body for starting a timer. Fails with 409 if the user already has a running timer
+type StartTimerRequest struct {
+	UserID    string  `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174003"`
+	SubTaskID *string `json:"subtask_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	Note      *string `json:"note,omitempty" example:"Code review"`
+}
+
+// StopTimerRequest represents the request body for stopping a timer on a task
+// @Description Request body for stopping the user's running timer on a task
+type StopTimerRequest struct {
+	UserID string `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174003"`
+}
+
+// CreateTimeEntryRequest represents the request body for logging time manually
+// @Description Request body for adding a finished time entry
+type CreateTimeEntryRequest struct {
+	UserID    string    `json:"user_id" binding:"required" example:"123e4567-e89b-12d3-a456-426614174003"`
+	SubTaskID *string   `json:"subtask_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	StartedAt time.Time `json:"started_at" binding:"required" example:"2024-01-01T09:00:00Z"`
+	EndedAt   time.Time `json:"ended_at" binding:"required" example:"2024-01-01T10:30:00Z"`
+	Note      *string   `json:"note,omitempty" example:"Code review"`
+}
+
+// UpdateTimeEntryRequest represents the request body for editing a time entry.
+// Setting ended_at on a running entry stops it; an empty subtask_id detaches the subtask
+// @Description Request body for editing an existing time entry
+type UpdateTimeEntryRequest struct {
+	SubTaskID *string    `json:"subtask_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174002"`
+	StartedAt *time.Time `json:"started_at,omitempty" example:"2024-01-01T09:00:00Z"`
+	EndedAt   *time.Time `json:"ended_at,omitempty" example:"2024-01-01T10:30:00Z"`
+	Note      *string    `json:"note,omitempty" example:"Code review"`
+}
+
+// TimeRangeFilters selects time entries overlapping a range. from and to accept
+// YYYY-MM-DD or RFC 3339 timestamps; a date-only to includes that whole day
+type TimeRangeFilters struct {
+	UserID string `form:"user_id"`
+	From   string `form:"from"`
+	To     string `form:"to"`
+}
+
+// UserTime is the time one user spent within a range
+type UserTime struct {
+	UserID  string `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	Name    string `json:"name" example:"John Doe"`
+	Seconds int64  `json:"seconds" example:"5400"`
+}
+
+// TaskTime is the time spent on one task within a range
+type TaskTime struct {
+	TaskID  string `json:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	Title   string `json:"title" example:"Release v1.2"`
+	Seconds int64  `json:"seconds" example:"5400"`
+}
+
+// TaskTimeTotals sums the time spent on a task within a range, per user.
+// Entries crossing the range bounds only count their part inside the range
+// @Description Time spent on a task, in total and per user
+type TaskTimeTotals struct {
+	TaskID       string     `json:"task_id" example:"123e4567-e89b-12d3-a456-426614174001"`
+	From         *time.Time `json:"from,omitempty" example:"2024-01-01T00:00:00Z"`
+	To           *time.Time `json:"to,omitempty" example:"2024-02-01T00:00:00Z"`
+	TotalSeconds int64      `json:"total_seconds" example:"5400"`
+	Users        []UserTime `json:"users"`
+}
+
+// UserTimeTotals sums the time a user spent within a range, per task
+// @Description Time spent by a user, in total and per task
+type UserTimeTotals struct {
+	UserID       string     `json:"user_id" example:"123e4567-e89b-12d3-a456-426614174003"`
+	From         *time.Time `json:"from,omitempty" example:"2024-01-01T00:00:00Z"`
+	To           *time.Time `json:"to,omitempty" example:"2024-02-01T00:00:00Z"`
+	TotalSeconds int64      `json:"total_seconds" example:"5400"`
+	Tasks        []TaskTime `json:"tasks"`
+}
+
+func NewTimeEntry(workspaceID string, taskID string, userID string, subTaskID *string, note *string, startedAt time.Time, endedAt *time.Time) *TimeEntry {
+	now := time.Now()
+	return &TimeEntry{
+		ID:          uuid.New().String(),
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		TaskID:      taskID,
+		SubTaskID:   subTaskID,
+		StartedAt:   startedAt,
+		EndedAt:     endedAt,
+		Note:        note,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
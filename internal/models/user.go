@@ -0,0 +1,30 @@
+package models
+
+import "github.com/google/uuid"
+
+// CreateUserRequest represents the request body for creating a new user
+// @Description Request body for creating a new user
+type CreateUserRequest struct {
+	Name string `json:"name" binding:"required" example:"John Doe"`
+	Src  string `json:"src" example:"https://avatars.githubusercontent.com/u/124599?v=4"`
+	// Email is where email notifications are sent; without it the user gets none
+	Email string `json:"email" example:"john@example.com"`
+}
+
+// UpdateUserRequest represents the request body for updating an existing user
+// @Description Request body for updating an existing user
+type UpdateUserRequest struct {
+	Name *string `json:"name,omitempty" example:"John Doe"`
+	Src  *string `json:"src,omitempty" example:"https://avatars.githubusercontent.com/u/124599?v=4"`
+	// Email set to an empty string stops email notifications
+	Email *string `json:"email,omitempty" example:"john@example.com"`
+}
+
+func NewUser(req CreateUserRequest) *User {
+	return &User{
+		ID:    uuid.New().String(),
+		Name:  req.Name,
+		Src:   req.Src,
+		Email: req.Email,
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import "time"
+
+// StatusCategory groups workflow statuses by how far along a task is.
+// Reports, rollups and dependency checks only look at the category
+type StatusCategory string
+
+const (
+	CategoryTodo  StatusCategory = "todo"
+	CategoryDoing StatusCategory = "doing"
+	CategoryDone  StatusCategory = "done"
+)
+
+// IsValid reports whether c is one of the known status categories
+func (c StatusCategory) IsValid() bool {
+	switch c {
+	case CategoryTodo, CategoryDoing, CategoryDone:
+		return true
+	}
+	return false
+}
+
+// LegacyCategory returns the category that one of the three built-in statuses stands for.
+// Filters treat these statuses as aliases of their category so that they match custom statuses too
+func (s TaskStatus) LegacyCategory() (StatusCategory, bool) {
+	switch s {
+	case StatusNotStarted:
+		return CategoryTodo, true
+	case StatusInProgress:
+		return CategoryDoing, true
+	case StatusCompleted:
+		return CategoryDone, true
+	}
+	return "", false
+}
+
+// WorkflowStatus is one status of a workspace's workflow
+// @Description A task status and the category it belongs to
+type WorkflowStatus struct {
+	Key       TaskStatus     `json:"key" db:"key" example:"in-review"`
+	Name      string         `json:"name" db:"name" example:"In review"`
+	Category  StatusCategory `json:"category" db:"category" example:"doing"`
+	Position  int            `json:"position" db:"position" example:"2"`
+	CreatedAt time.Time      `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// WorkflowTransition allows tasks and subtasks to move from one status to another
+// @Description An allowed status change
+type WorkflowTransition struct {
+	From TaskStatus `json:"from" db:"from_status" example:"in-progress"`
+	To   TaskStatus `json:"to" db:"to_status" example:"in-review"`
+}
+
+// Workflow is the set of statuses tasks and subtasks of a workspace can have, in board order,
+// and the status changes allowed between them. Without transitions any change is allowed
+// @Description The statuses of a workspace and the allowed transitions between them
+type Workflow struct {
+	Statuses    []WorkflowStatus     `json:"statuses"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// Status looks up a status of the workflow by key
+func (w *Workflow) Status(key TaskStatus) (WorkflowStatus, bool) {
+	for _, status := range w.Statuses {
+		if status.Key == key {
+			return status, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// InitialStatus is the first todo status; new occurrences and tasks created from templates start there
+func (w *Workflow) InitialStatus() TaskStatus {
+	for _, status := range w.Statuses {
+		if status.Category == CategoryTodo {
+			return status.Key
+		}
+	}
+	return StatusNotStarted
+}
+
+// CanTransition reports whether a task may move from one status to another
+func (w *Workflow) CanTransition(from TaskStatus, to TaskStatus) bool {
+	if from == to || len(w.Transitions) == 0 {
+		return true
+	}
+	for _, transition := range w.Transitions {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdateWorkflowRequest replaces the workflow of a workspace. Statuses are listed in board order
+// and every category needs at least one status. Statuses still used by tasks cannot be removed
+// @Description Request body for replacing the workflow of a workspace
+type UpdateWorkflowRequest struct {
+	Statuses    []WorkflowStatusRequest `json:"statuses" binding:"required"`
+	Transitions []WorkflowTransition    `json:"transitions"`
+}
+
+// WorkflowStatusRequest describes one status in UpdateWorkflowRequest
+type WorkflowStatusRequest struct {
+	Key      TaskStatus     `json:"key" binding:"required" example:"in-review"`
+	Name     string         `json:"name" binding:"required" example:"In review"`
+	Category StatusCategory `json:"category" binding:"required" example:"doing"`
+}
+
+// DefaultWorkflow is the workflow every workspace starts with: the three built-in statuses
+// and no transition rules
+func DefaultWorkflow() []WorkflowStatusRequest {
+	return []WorkflowStatusRequest{
+		{Key: StatusNotStarted, Name: "Not started", Category: CategoryTodo},
+		{Key: StatusInProgress, Name: "In progress", Category: CategoryDoing},
+		{Key: StatusCompleted, Name: "Completed", Category: CategoryDone},
+	}
+}
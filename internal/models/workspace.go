@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultWorkspaceID identifies the workspace that holds data created before workspaces existed
+const DefaultWorkspaceID = "default"
+
+// Workspace is a tenant that owns tasks, projects and labels
+// @Description A workspace isolating one team's tasks, projects and labels
+type Workspace struct {
+	ID        string    `json:"id" db:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string    `json:"name" db:"name" example:"Acme Inc."`
+	CreatedAt time.Time `json:"created_at" db:"created_at" example:"2024-01-01T00:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// CreateWorkspaceRequest represents the request body for creating a workspace
+// @Description Request body for creating a workspace
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required" example:"Acme Inc."`
+}
+
+// UpdateWorkspaceRequest represents the request body for updating a workspace
+// @Description Request body for updating an existing workspace
+type UpdateWorkspaceRequest struct {
+	Name *string `json:"name,omitempty" example:"Acme Inc."`
+}
+
+func NewWorkspace(req CreateWorkspaceRequest) *Workspace {
+	now := time.Now()
+	return &Workspace{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,236 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int, hour int, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+}
+
+// occurrences lists the occurrences of rule from start the way series do: COUNT caps how many are
+// produced, and at most limit are returned for rules that never end
+func occurrences(rule *Rule, start time.Time, limit int) []time.Time {
+	var result []time.Time
+	after := start.Add(-time.Nanosecond)
+	for len(result) < limit {
+		if rule.Count > 0 && len(result) >= rule.Count {
+			break
+		}
+		next, ok := rule.Next(start, after)
+		if !ok {
+			break
+		}
+		result = append(result, next)
+		after = next
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"daily", "FREQ=DAILY", "FREQ=DAILY"},
+		{"prefix and lower case", "RRULE:freq=weekly;byday=mo,we", "FREQ=WEEKLY;BYDAY=MO,WE"},
+		{"interval", "FREQ=DAILY;INTERVAL=3", "FREQ=DAILY;INTERVAL=3"},
+		{"interval of one is dropped", "FREQ=WEEKLY;INTERVAL=1", "FREQ=WEEKLY"},
+		{"monthly ordinals", "FREQ=MONTHLY;BYDAY=-1FR,2MO", "FREQ=MONTHLY;BYDAY=-1FR,2MO"},
+		{"count", "FREQ=DAILY;COUNT=5", "FREQ=DAILY;COUNT=5"},
+		{"until timestamp", "FREQ=DAILY;UNTIL=20240110T120000Z", "FREQ=DAILY;UNTIL=20240110T120000Z"},
+		{"until date covers the whole day", "FREQ=DAILY;UNTIL=20240110", "FREQ=DAILY;UNTIL=20240110T235959Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.value, err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"missing freq", "INTERVAL=2"},
+		{"unsupported freq", "FREQ=YEARLY"},
+		{"unsupported bymonthday", "FREQ=MONTHLY;BYMONTHDAY=31"},
+		{"unsupported bysetpos", "FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1"},
+		{"malformed part", "FREQ=DAILY;INTERVAL"},
+		{"duplicate part", "FREQ=DAILY;FREQ=WEEKLY"},
+		{"zero interval", "FREQ=DAILY;INTERVAL=0"},
+		{"zero count", "FREQ=DAILY;COUNT=0"},
+		{"count with until", "FREQ=DAILY;COUNT=3;UNTIL=20240110"},
+		{"bad until", "FREQ=DAILY;UNTIL=tomorrow"},
+		{"unknown weekday", "FREQ=WEEKLY;BYDAY=XX"},
+		{"ordinal outside monthly", "FREQ=WEEKLY;BYDAY=2MO"},
+		{"ordinal out of range", "FREQ=MONTHLY;BYDAY=6MO"},
+		{"zero ordinal", "FREQ=MONTHLY;BYDAY=0MO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("Parse(%q) = %v, %v, want ErrInvalidRule", tt.value, rule, err)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		limit int
+		want  []time.Time
+	}{
+		{
+			name:  "daily",
+			rule:  "FREQ=DAILY",
+			start: date(2024, time.January, 30, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 30, 9, 0), date(2024, time.January, 31, 9, 0), date(2024, time.February, 1, 9, 0)},
+		},
+		{
+			name:  "daily with interval",
+			rule:  "FREQ=DAILY;INTERVAL=2",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 1, 9, 0), date(2024, time.January, 3, 9, 0), date(2024, time.January, 5, 9, 0)},
+		},
+		{
+			name:  "daily on weekdays",
+			rule:  "FREQ=DAILY;BYDAY=MO,FR",
+			start: date(2024, time.January, 3, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 5, 9, 0), date(2024, time.January, 8, 9, 0), date(2024, time.January, 12, 9, 0)},
+		},
+		{
+			name:  "weekly keeps the weekday of start",
+			rule:  "FREQ=WEEKLY",
+			start: date(2024, time.January, 3, 14, 30),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 3, 14, 30), date(2024, time.January, 10, 14, 30), date(2024, time.January, 17, 14, 30)},
+		},
+		{
+			name:  "biweekly on several days",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 4,
+			want: []time.Time{
+				date(2024, time.January, 1, 9, 0), date(2024, time.January, 3, 9, 0),
+				date(2024, time.January, 15, 9, 0), date(2024, time.January, 17, 9, 0),
+			},
+		},
+		{
+			name:  "monthly on the day of start",
+			rule:  "FREQ=MONTHLY",
+			start: date(2024, time.January, 15, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 15, 9, 0), date(2024, time.February, 15, 9, 0), date(2024, time.March, 15, 9, 0)},
+		},
+		{
+			name:  "monthly on the 31st skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: date(2024, time.January, 31, 9, 0),
+			limit: 4,
+			want: []time.Time{
+				date(2024, time.January, 31, 9, 0), date(2024, time.March, 31, 9, 0),
+				date(2024, time.May, 31, 9, 0), date(2024, time.July, 31, 9, 0),
+			},
+		},
+		{
+			name:  "monthly on the last friday",
+			rule:  "FREQ=MONTHLY;BYDAY=-1FR",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 26, 9, 0), date(2024, time.February, 23, 9, 0), date(2024, time.March, 29, 9, 0)},
+		},
+		{
+			name:  "monthly on the second monday",
+			rule:  "FREQ=MONTHLY;BYDAY=2MO",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 8, 9, 0), date(2024, time.February, 12, 9, 0), date(2024, time.March, 11, 9, 0)},
+		},
+		{
+			name:  "every other month",
+			rule:  "FREQ=MONTHLY;INTERVAL=2",
+			start: date(2024, time.January, 10, 9, 0),
+			limit: 3,
+			want:  []time.Time{date(2024, time.January, 10, 9, 0), date(2024, time.March, 10, 9, 0), date(2024, time.May, 10, 9, 0)},
+		},
+		{
+			name:  "count is exhausted",
+			rule:  "FREQ=DAILY;COUNT=2",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 10,
+			want:  []time.Time{date(2024, time.January, 1, 9, 0), date(2024, time.January, 2, 9, 0)},
+		},
+		{
+			name:  "until is exhausted",
+			rule:  "FREQ=WEEKLY;UNTIL=20240115T090000Z",
+			start: date(2024, time.January, 1, 9, 0),
+			limit: 10,
+			want:  []time.Time{date(2024, time.January, 1, 9, 0), date(2024, time.January, 8, 9, 0), date(2024, time.January, 15, 9, 0)},
+		},
+		{
+			name:  "until date includes its day",
+			rule:  "FREQ=DAILY;UNTIL=20240102",
+			start: date(2024, time.January, 1, 18, 0),
+			limit: 10,
+			want:  []time.Time{date(2024, time.January, 1, 18, 0), date(2024, time.January, 2, 18, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.rule, err)
+			}
+
+			got := occurrences(rule, tt.start, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d %v", len(got), got, len(tt.want), tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("occurrence %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNextAfterDueDate(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=MO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := date(2024, time.January, 1, 9, 0)
+
+	// An occurrence that is due later on the same day is not skipped, one already due is
+	next, ok := rule.Next(start, date(2024, time.January, 8, 8, 0))
+	if !ok || !next.Equal(date(2024, time.January, 8, 9, 0)) {
+		t.Errorf("Next = %v, %v, want 2024-01-08 09:00", next, ok)
+	}
+	next, ok = rule.Next(start, date(2024, time.January, 8, 9, 0))
+	if !ok || !next.Equal(date(2024, time.January, 15, 9, 0)) {
+		t.Errorf("Next = %v, %v, want 2024-01-15 09:00", next, ok)
+	}
+}
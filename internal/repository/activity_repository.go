@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const activitySelectColumns = `
+	a.id, a.workspace_id, a.task_id, a.subtask_id, a.entity, a.action, a.actor_id, a.changes, a.created_at,
+	u.id, u.name, u.src`
+
+// ActivityRepository handles database operations for the audit log of tasks and subtasks.
+// Every method is scoped to a single workspace
+type ActivityRepository struct {
+	db *sql.DB
+}
+
+// NewActivityRepository creates a new instance of ActivityRepository
+func NewActivityRepository(db *sql.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// RecordActivity appends an entry to the audit log
+func (r *ActivityRepository) RecordActivity(activity *models.Activity) error {
+	if err := requireWorkspace(activity.WorkspaceID); err != nil {
+		return err
+	}
+	return insertActivity(r.db, activity)
+}
+
+// insertActivity writes an audit log entry with db, which may be a transaction
+func insertActivity(db execer, activity *models.Activity) error {
+	changes, err := json.Marshal(activity.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO task_activity (id, workspace_id, task_id, subtask_id, entity, action, actor_id, changes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		activity.ID, activity.WorkspaceID, activity.TaskID, activity.SubTaskID, activity.Entity, activity.Action,
+		activity.ActorID, string(changes), activity.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record %s activity of task %s: %w", activity.Action, activity.TaskID, err)
+	}
+	return nil
+}
+
+// GetActivities retrieves the audit log entries matching filters, newest first.
+// Entries outlive their tasks, so they are found for tasks in the trash or purged as well
+func (r *ActivityRepository) GetActivities(workspaceID string, filters models.ActivityFilters, from *time.Time, to *time.Time) ([]models.Activity, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"a.workspace_id = $1"}
+	args := []any{workspaceID}
+	argIndex := 2
+
+	if filters.TaskID != "" {
+		conditions = append(conditions, fmt.Sprintf("a.task_id = $%d", argIndex))
+		args = append(args, filters.TaskID)
+		argIndex++
+	}
+	if filters.ActorID != "" {
+		conditions = append(conditions, fmt.Sprintf("a.actor_id = $%d", argIndex))
+		args = append(args, filters.ActorID)
+		argIndex++
+	}
+	if filters.Entity != "" {
+		conditions = append(conditions, fmt.Sprintf("a.entity = $%d", argIndex))
+		args = append(args, filters.Entity)
+		argIndex++
+	}
+	if filters.Action != "" {
+		conditions = append(conditions, fmt.Sprintf("a.action = $%d", argIndex))
+		args = append(args, filters.Action)
+		argIndex++
+	}
+	if filters.Field != "" {
+		field, err := json.Marshal([]map[string]string{{"field": filters.Field}})
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fmt.Sprintf("a.changes @> $%d::jsonb", argIndex))
+		args = append(args, string(field))
+		argIndex++
+	}
+	if from != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at >= $%d", argIndex))
+		args = append(args, *from)
+		argIndex++
+	}
+	if to != nil {
+		conditions = append(conditions, fmt.Sprintf("a.created_at < $%d", argIndex))
+		args = append(args, *to)
+		argIndex++
+	}
+
+	query := `SELECT ` + activitySelectColumns + `
+		FROM task_activity a
+		LEFT JOIN users u ON u.id = a.actor_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY a.created_at DESC, a.id`
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filters.Limit)
+		argIndex++
+	}
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activities := []models.Activity{}
+	for rows.Next() {
+		activity, err := scanActivity(rows)
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, *activity)
+	}
+
+	return activities, rows.Err()
+}
+
+func scanActivity(row rowScanner) (*models.Activity, error) {
+	activity := &models.Activity{}
+	var changes []byte
+	var actorID, actorName, actorSrc sql.NullString
+
+	err := row.Scan(
+		&activity.ID, &activity.WorkspaceID, &activity.TaskID, &activity.SubTaskID, &activity.Entity, &activity.Action,
+		&activity.ActorID, &changes, &activity.CreatedAt,
+		&actorID, &actorName, &actorSrc,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &activity.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of activity %s: %w", activity.ID, err)
+	}
+	if actorID.Valid {
+		activity.Actor = &models.User{ID: actorID.String, Name: actorName.String, Src: actorSrc.String}
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// ErrAttachmentQuotaExceeded is returned when a new attachment would push
+// a task over its total attachment size limit
+var ErrAttachmentQuotaExceeded = errors.New("task attachment quota exceeded")
+
+const attachmentSelectColumns = `id, task_id, filename, size, mime_type, checksum, storage_key, uploaded_by, created_at`
+
+// AttachmentRepository handles database operations for attachment metadata.
+// Every method is scoped to a single workspace through the attachment's task
+type AttachmentRepository struct {
+	db *sql.DB
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository
+func NewAttachmentRepository(db *sql.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// CreateAttachment stores attachment metadata. The task row is locked while the
+// quota is checked so concurrent uploads cannot together exceed maxTaskSize.
+// It returns sql.ErrNoRows if the task is not in the workspace
+func (r *AttachmentRepository) CreateAttachment(workspaceID string, attachment *models.Attachment, maxTaskSize int64) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var taskID string
+	if err := tx.QueryRow("SELECT id FROM tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE", attachment.TaskID, workspaceID).Scan(&taskID); err != nil {
+		return err
+	}
+
+	var used int64
+	err = tx.QueryRow("SELECT COALESCE(SUM(size), 0) FROM attachments WHERE task_id = $1", attachment.TaskID).Scan(&used)
+	if err != nil {
+		return err
+	}
+	if used+attachment.Size > maxTaskSize {
+		return ErrAttachmentQuotaExceeded
+	}
+
+	query := `
+		INSERT INTO attachments (id, task_id, filename, size, mime_type, checksum, storage_key, uploaded_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(query, attachment.ID, attachment.TaskID, attachment.Filename, attachment.Size,
+		attachment.MimeType, attachment.Checksum, attachment.StorageKey, attachment.UploadedBy, attachment.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAttachmentByID retrieves attachment metadata by its ID
+func (r *AttachmentRepository) GetAttachmentByID(workspaceID string, id string) (*models.Attachment, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + attachmentSelectColumns + ` FROM attachments a WHERE a.id = $1 AND ` + taskInWorkspace("a", 2)
+	return scanAttachment(r.db.QueryRow(query, id, workspaceID))
+}
+
+// GetTaskAttachments retrieves all attachments of a task, oldest first
+func (r *AttachmentRepository) GetTaskAttachments(workspaceID string, taskID string) ([]models.Attachment, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + attachmentSelectColumns + ` FROM attachments a
+		WHERE a.task_id = $1 AND ` + taskInWorkspace("a", 2) + `
+		ORDER BY a.created_at ASC`
+
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+// GetTaskAttachmentsSize returns the total size in bytes of a task's attachments
+func (r *AttachmentRepository) GetTaskAttachmentsSize(workspaceID string, taskID string) (int64, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return 0, err
+	}
+
+	var used int64
+	err := r.db.QueryRow(
+		"SELECT COALESCE(SUM(a.size), 0) FROM attachments a WHERE a.task_id = $1 AND "+taskInWorkspace("a", 2),
+		taskID, workspaceID).Scan(&used)
+	return used, err
+}
+
+// DeleteAttachment removes attachment metadata
+func (r *AttachmentRepository) DeleteAttachment(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec("DELETE FROM attachments WHERE id = $1 AND "+taskInWorkspace("attachments", 2), id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	attachment := &models.Attachment{}
+	err := row.Scan(
+		&attachment.ID, &attachment.TaskID, &attachment.Filename, &attachment.Size, &attachment.MimeType,
+		&attachment.Checksum, &attachment.StorageKey, &attachment.UploadedBy, &attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
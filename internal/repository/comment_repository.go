@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const commentSelectColumns = `
+	c.id, c.task_id, c.parent_id, c.author_id, c.body, c.created_at, c.updated_at, c.edited_at,
+	u.id, u.name, u.src`
+
+// CommentRepository handles database operations for task comments.
+// Every method is scoped to a single workspace through the comment's task
+type CommentRepository struct {
+	db *sql.DB
+}
+
+// NewCommentRepository creates a new instance of CommentRepository
+func NewCommentRepository(db *sql.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// CreateComment inserts a new comment. It returns sql.ErrNoRows if the task is not in the workspace
+func (r *CommentRepository) CreateComment(workspaceID string, comment *models.Comment) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO comments (id, task_id, parent_id, author_id, body, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7
+		WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $2 AND workspace_id = $8)`
+
+	result, err := r.db.Exec(query, comment.ID, comment.TaskID, comment.ParentID, comment.AuthorID,
+		comment.Body, comment.CreatedAt, comment.UpdatedAt, workspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetCommentByID retrieves a single comment with its author, without replies
+func (r *CommentRepository) GetCommentByID(workspaceID string, id string) (*models.Comment, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + commentSelectColumns + `
+		FROM comments c
+		LEFT JOIN users u ON u.id = c.author_id
+		WHERE c.id = $1 AND ` + taskInWorkspace("c", 2)
+
+	return scanComment(r.db.QueryRow(query, id, workspaceID))
+}
+
+// UpdateCommentBody replaces the text of a comment and marks it as edited
+func (r *CommentRepository) UpdateCommentBody(workspaceID string, id string, body string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE comments SET body = $1, updated_at = $2, edited_at = $2
+		WHERE id = $3 AND `+taskInWorkspace("comments", 4),
+		body, time.Now(), id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteComment removes a comment. Replies are removed with it by ON DELETE CASCADE
+func (r *CommentRepository) DeleteComment(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec("DELETE FROM comments WHERE id = $1 AND "+taskInWorkspace("comments", 2), id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountRootComments returns the number of top-level comments on a task
+func (r *CommentRepository) CountRootComments(workspaceID string, taskID string) (int, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return 0, err
+	}
+
+	var total int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM comments c
+		WHERE c.task_id = $1 AND c.parent_id IS NULL AND `+taskInWorkspace("c", 2),
+		taskID, workspaceID).Scan(&total)
+	return total, err
+}
+
+// GetCommentThreads retrieves a page of top-level comments on a task, oldest first,
+// with every reply nested under its parent
+func (r *CommentRepository) GetCommentThreads(workspaceID string, taskID string, limit int, offset int) ([]models.Comment, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH RECURSIVE roots AS (
+			SELECT c.id FROM comments c
+			WHERE c.task_id = $1 AND c.parent_id IS NULL AND ` + taskInWorkspace("c", 4) + `
+			ORDER BY c.created_at ASC, c.id ASC
+			LIMIT $2 OFFSET $3
+		), thread AS (
+			SELECT id FROM roots
+			UNION ALL
+			SELECT child.id FROM comments child JOIN thread ON child.parent_id = thread.id
+		)
+		SELECT ` + commentSelectColumns + `
+		FROM comments c
+		JOIN thread t ON t.id = c.id
+		LEFT JOIN users u ON u.id = c.author_id
+		ORDER BY c.created_at ASC, c.id ASC`
+
+	rows, err := r.db.Query(query, taskID, limit, offset, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flat []*models.Comment
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		flat = append(flat, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buildCommentTree(flat), nil
+}
+
+// buildCommentTree nests comments under their parents. The input must be ordered
+// so that every comment appears after its parent, which creation order guarantees
+func buildCommentTree(flat []*models.Comment) []models.Comment {
+	children := make(map[string][]*models.Comment)
+	var roots []*models.Comment
+	for _, comment := range flat {
+		if comment.ParentID == nil {
+			roots = append(roots, comment)
+			continue
+		}
+		children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+	}
+
+	var attach func(comment *models.Comment) models.Comment
+	attach = func(comment *models.Comment) models.Comment {
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, attach(child))
+		}
+		return *comment
+	}
+
+	result := make([]models.Comment, 0, len(roots))
+	for _, root := range roots {
+		result = append(result, attach(root))
+	}
+	return result
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanComment(row rowScanner) (*models.Comment, error) {
+	comment := &models.Comment{}
+	var authorID, authorName, authorSrc sql.NullString
+
+	err := row.Scan(
+		&comment.ID, &comment.TaskID, &comment.ParentID, &comment.AuthorID, &comment.Body,
+		&comment.CreatedAt, &comment.UpdatedAt, &comment.EditedAt,
+		&authorID, &authorName, &authorSrc,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if authorID.Valid {
+		comment.Author = &models.User{ID: authorID.String, Name: authorName.String, Src: authorSrc.String}
+	}
+
+	return comment, nil
+}
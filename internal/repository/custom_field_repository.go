@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const customFieldSelectColumns = "id, workspace_id, key, name, type, options, required, created_at, updated_at"
+
+// CustomFieldRepository handles database operations for custom field definitions.
+// Field values live in the custom_fields JSONB column of tasks, keyed by field key.
+// Every method is scoped to a single workspace
+type CustomFieldRepository struct {
+	db *sql.DB
+}
+
+// NewCustomFieldRepository creates a new instance of CustomFieldRepository
+func NewCustomFieldRepository(db *sql.DB) *CustomFieldRepository {
+	return &CustomFieldRepository{db: db}
+}
+
+// CreateCustomField inserts a new field definition. It returns ErrDuplicate if the key is taken in the workspace
+func (r *CustomFieldRepository) CreateCustomField(field *models.CustomField) error {
+	if err := requireWorkspace(field.WorkspaceID); err != nil {
+		return err
+	}
+
+	options, err := marshalOptions(field.Options)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		INSERT INTO custom_fields (id, workspace_id, key, name, type, options, required, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		field.ID, field.WorkspaceID, field.Key, field.Name, field.Type, options, field.Required, field.CreatedAt, field.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// GetCustomFieldByID retrieves a field definition by its ID
+func (r *CustomFieldRepository) GetCustomFieldByID(workspaceID string, id string) (*models.CustomField, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	return scanCustomField(r.db.QueryRow(
+		"SELECT "+customFieldSelectColumns+" FROM custom_fields WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	))
+}
+
+// GetCustomFields retrieves every field definition of the workspace ordered by name
+func (r *CustomFieldRepository) GetCustomFields(workspaceID string) ([]models.CustomField, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		"SELECT "+customFieldSelectColumns+" FROM custom_fields WHERE workspace_id = $1 ORDER BY lower(name) ASC", workspaceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []models.CustomField
+	for rows.Next() {
+		field, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, *field)
+	}
+
+	return fields, rows.Err()
+}
+
+// UpdateCustomField updates the name, options or required flag of a field definition
+func (r *CustomFieldRepository) UpdateCustomField(workspaceID string, id string, updates *models.UpdateCustomFieldRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Options != nil {
+		options, err := marshalOptions(*updates.Options)
+		if err != nil {
+			return err
+		}
+		setParts = append(setParts, fmt.Sprintf("options = $%d", argIndex))
+		args = append(args, options)
+		argIndex++
+	}
+	if updates.Required != nil {
+		setParts = append(setParts, fmt.Sprintf("required = $%d", argIndex))
+		args = append(args, *updates.Required)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE custom_fields SET %s WHERE id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteCustomField removes a field definition and its values from every task of the workspace
+func (r *CustomFieldRepository) DeleteCustomField(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var key string
+	err = tx.QueryRow("DELETE FROM custom_fields WHERE id = $1 AND workspace_id = $2 RETURNING key", id, workspaceID).Scan(&key)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE tasks SET custom_fields = custom_fields - $1 WHERE workspace_id = $2 AND custom_fields ? $1",
+		key, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to clear field values: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func marshalOptions(options []string) (string, error) {
+	if options == nil {
+		options = []string{}
+	}
+	data, err := json.Marshal(options)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func scanCustomField(row rowScanner) (*models.CustomField, error) {
+	field := &models.CustomField{}
+	var options []byte
+	err := row.Scan(&field.ID, &field.WorkspaceID, &field.Key, &field.Name, &field.Type, &options, &field.Required,
+		&field.CreatedAt, &field.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &field.Options); err != nil {
+		return nil, fmt.Errorf("failed to decode options of custom field %s: %w", field.ID, err)
+	}
+	return field, nil
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// ErrDependencyCycle is returned when a new dependency would make a task wait on itself
+var ErrDependencyCycle = errors.New("dependency would create a cycle")
+
+// DependencyRepository handles database operations for blocked-by relations between tasks.
+// Every method is scoped to a single workspace
+type DependencyRepository struct {
+	db *sql.DB
+}
+
+// NewDependencyRepository creates a new instance of DependencyRepository
+func NewDependencyRepository(db *sql.DB) *DependencyRepository {
+	return &DependencyRepository{db: db}
+}
+
+// AddDependency records that taskID is blocked by blockerID, doing nothing if the edge exists.
+// It returns ErrDependencyCycle if blockerID already waits on taskID, directly or transitively
+func (r *DependencyRepository) AddDependency(workspaceID string, taskID string, blockerID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+	if taskID == blockerID {
+		return ErrDependencyCycle
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Serialize writers so two concurrent inserts cannot close a cycle between them
+	if _, err := tx.Exec("LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return err
+	}
+
+	var cycle bool
+	err = tx.QueryRow(`
+		WITH RECURSIVE upstream(id) AS (
+			SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT d.blocked_by_id FROM task_dependencies d JOIN upstream u ON d.task_id = u.id
+		)
+		SELECT EXISTS (SELECT 1 FROM upstream WHERE id = $2)`, blockerID, taskID).Scan(&cycle)
+	if err != nil {
+		return err
+	}
+	if cycle {
+		return ErrDependencyCycle
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO task_dependencies (task_id, blocked_by_id, created_at)
+		SELECT t.id, b.id, $3 FROM tasks t, tasks b
+		WHERE t.id = $1 AND b.id = $2 AND t.workspace_id = $4 AND b.workspace_id = $4
+		ON CONFLICT (task_id, blocked_by_id) DO NOTHING`, taskID, blockerID, time.Now(), workspaceID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveDependency deletes a blocked-by edge.
+// It returns sql.ErrNoRows if taskID was not blocked by blockerID
+func (r *DependencyRepository) RemoveDependency(workspaceID string, taskID string, blockerID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		DELETE FROM task_dependencies
+		WHERE task_id = $1 AND blocked_by_id = $2
+		AND task_id IN (SELECT id FROM tasks WHERE workspace_id = $3)`, taskID, blockerID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetBlockers lists the tasks that taskID waits on
+func (r *DependencyRepository) GetBlockers(workspaceID string, taskID string) ([]models.TaskRef, error) {
+	return r.queryTaskRefs(workspaceID, `
+		SELECT t.id, t.title, t.status
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = $1 AND t.workspace_id = $2 AND t.deleted_at IS NULL
+		ORDER BY d.created_at ASC`, taskID)
+}
+
+// GetBlockedTasks lists the tasks that wait on taskID
+func (r *DependencyRepository) GetBlockedTasks(workspaceID string, taskID string) ([]models.TaskRef, error) {
+	return r.queryTaskRefs(workspaceID, `
+		SELECT t.id, t.title, t.status
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.task_id
+		WHERE d.blocked_by_id = $1 AND t.workspace_id = $2 AND t.deleted_at IS NULL
+		ORDER BY d.created_at ASC`, taskID)
+}
+
+// CountOpenBlockers returns how many of the tasks that taskID waits on are not in a done status.
+// Tasks in the trash do not block
+func (r *DependencyRepository) CountOpenBlockers(workspaceID string, taskID string) (int, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return 0, err
+	}
+
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM task_dependencies d
+		JOIN tasks t ON t.id = d.blocked_by_id
+		WHERE d.task_id = $1 AND t.workspace_id = $2 AND t.deleted_at IS NULL AND NOT `+statusInCategory("t", models.CategoryDone), taskID, workspaceID).Scan(&count)
+	return count, err
+}
+
+func (r *DependencyRepository) queryTaskRefs(workspaceID string, query string, taskID string) ([]models.TaskRef, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refs := []models.TaskRef{}
+	for rows.Next() {
+		var ref models.TaskRef
+		if err := rows.Scan(&ref.ID, &ref.Title, &ref.Status); err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/google/uuid"
+)
+
+const emailDeliverySelectColumns = `id, workspace_id, user_id, kind, notification_id, digest_date, to_address, subject,
+	status, attempts, last_error, next_attempt_at, sent_at, created_at`
+
+// EmailRepository handles database operations for email opt-outs and the email delivery log
+type EmailRepository struct {
+	db *sql.DB
+}
+
+// NewEmailRepository creates a new instance of EmailRepository
+func NewEmailRepository(db *sql.DB) *EmailRepository {
+	return &EmailRepository{db: db}
+}
+
+// GetOptOuts retrieves the email kinds a user opted out of
+func (r *EmailRepository) GetOptOuts(userID string) ([]models.EmailKind, error) {
+	rows, err := r.db.Query("SELECT kind FROM email_opt_outs WHERE user_id = $1 ORDER BY kind", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	kinds := []models.EmailKind{}
+	for rows.Next() {
+		var kind models.EmailKind
+		if err := rows.Scan(&kind); err != nil {
+			return nil, err
+		}
+		kinds = append(kinds, kind)
+	}
+
+	return kinds, rows.Err()
+}
+
+// SetOptOuts replaces the email kinds a user opted out of
+func (r *EmailRepository) SetOptOuts(userID string, kinds []models.EmailKind) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM email_opt_outs WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		if _, err := tx.Exec("INSERT INTO email_opt_outs (user_id, kind) VALUES ($1, $2)", userID, kind); err != nil {
+			return fmt.Errorf("failed to opt out of %s: %w", kind, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// IsOptedOut reports whether a user opted out of an email kind
+func (r *EmailRepository) IsOptedOut(userID string, kind models.EmailKind) (bool, error) {
+	var optedOut bool
+	err := r.db.QueryRow("SELECT EXISTS (SELECT 1 FROM email_opt_outs WHERE user_id = $1 AND kind = $2)",
+		userID, kind).Scan(&optedOut)
+	return optedOut, err
+}
+
+// QueueNotificationEmails adds up to limit emails of kind to the delivery log, one per notification
+// of types created since the given time, for recipients with an email address who did not opt out.
+// It returns the number of emails queued. Safe to run concurrently from several replicas:
+// a notification is emailed once
+func (r *EmailRepository) QueueNotificationEmails(kind models.EmailKind, types []models.NotificationType, since time.Time, limit int) (int, error) {
+	if len(types) == 0 {
+		return 0, nil
+	}
+
+	args := []any{kind, since, limit}
+	placeholders := make([]string, 0, len(types))
+	for _, notificationType := range types {
+		args = append(args, notificationType)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	rows, err := r.db.Query(`
+		SELECT n.id, n.workspace_id, n.user_id, u.email
+		FROM notifications n
+		JOIN users u ON u.id = n.user_id
+		WHERE n.type IN (`+strings.Join(placeholders, ", ")+`) AND n.created_at >= $2 AND u.email <> ''
+			AND NOT EXISTS (SELECT 1 FROM email_opt_outs o WHERE o.user_id = n.user_id AND o.kind = $1)
+			AND NOT EXISTS (SELECT 1 FROM email_deliveries d WHERE d.notification_id = n.id)
+		ORDER BY n.created_at ASC
+		LIMIT $3`, args...)
+	if err != nil {
+		return 0, err
+	}
+	var deliveries []models.EmailDelivery
+	for rows.Next() {
+		delivery := models.EmailDelivery{Kind: kind, NotificationID: new(string)}
+		if err := rows.Scan(delivery.NotificationID, &delivery.WorkspaceID, &delivery.UserID, &delivery.To); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.queue(deliveries, "notification_id")
+}
+
+// QueueDigests adds to the delivery log the digest of date for every user with an email address
+// who did not opt out and has unread notifications created between from and to in a workspace,
+// up to limit digests. It returns the number of digests queued. Safe to run concurrently from
+// several replicas: a user gets one digest a day per workspace
+func (r *EmailRepository) QueueDigests(date time.Time, from time.Time, to time.Time, limit int) (int, error) {
+	rows, err := r.db.Query(`
+		SELECT n.workspace_id, n.user_id, u.email
+		FROM notifications n
+		JOIN users u ON u.id = n.user_id
+		WHERE n.read_at IS NULL AND n.created_at >= $1 AND n.created_at < $2 AND u.email <> ''
+			AND NOT EXISTS (SELECT 1 FROM email_opt_outs o WHERE o.user_id = n.user_id AND o.kind = $3)
+			AND NOT EXISTS (
+				SELECT 1 FROM email_deliveries d
+				WHERE d.workspace_id = n.workspace_id AND d.user_id = n.user_id AND d.digest_date = $4
+			)
+		GROUP BY n.workspace_id, n.user_id, u.email
+		LIMIT $5`, from, to, models.EmailDigest, date, limit)
+	if err != nil {
+		return 0, err
+	}
+	var deliveries []models.EmailDelivery
+	for rows.Next() {
+		delivery := models.EmailDelivery{Kind: models.EmailDigest, DigestDate: &date}
+		if err := rows.Scan(&delivery.WorkspaceID, &delivery.UserID, &delivery.To); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.queue(deliveries, "workspace_id, user_id, digest_date")
+}
+
+// queue inserts pending deliveries, skipping those that conflict with one already queued on the given columns
+func (r *EmailRepository) queue(deliveries []models.EmailDelivery, conflictColumns string) (int, error) {
+	now := time.Now()
+	queued := 0
+	for _, delivery := range deliveries {
+		result, err := r.db.Exec(`
+			INSERT INTO email_deliveries (id, workspace_id, user_id, kind, notification_id, digest_date, to_address,
+				status, attempts, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, 0, $9, $9)
+			ON CONFLICT (`+conflictColumns+`) DO NOTHING`,
+			uuid.New().String(), delivery.WorkspaceID, delivery.UserID, delivery.Kind, delivery.NotificationID,
+			delivery.DigestDate, delivery.To, models.EmailPending, now)
+		if err != nil {
+			return queued, fmt.Errorf("failed to queue %s email for user %s: %w", delivery.Kind, delivery.UserID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return queued, err
+		}
+		queued += int(affected)
+	}
+	return queued, nil
+}
+
+// ClaimDeliveries takes up to limit pending emails that are due, counts an attempt for each and
+// hides them from other callers for lease, which the caller has to finish each of them in.
+// Safe to run concurrently from several replicas: an email is claimed by one caller at a time
+func (r *EmailRepository) ClaimDeliveries(lease time.Duration, limit int) ([]models.EmailDelivery, error) {
+	now := time.Now()
+	rows, err := r.db.Query(`
+		UPDATE email_deliveries SET attempts = attempts + 1, next_attempt_at = $1
+		WHERE id IN (
+			SELECT id FROM email_deliveries
+			WHERE status = $2 AND next_attempt_at <= $3
+			ORDER BY next_attempt_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+emailDeliverySelectColumns,
+		now.Add(lease), models.EmailPending, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.EmailDelivery
+	for rows.Next() {
+		delivery, err := scanEmailDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// FinishDelivery stores the outcome of an attempt to send an email
+func (r *EmailRepository) FinishDelivery(delivery *models.EmailDelivery) error {
+	_, err := r.db.Exec(`
+		UPDATE email_deliveries
+		SET status = $1, to_address = $2, subject = $3, last_error = $4, next_attempt_at = $5, sent_at = $6
+		WHERE id = $7`,
+		delivery.Status, delivery.To, delivery.Subject, delivery.LastError, delivery.NextAttemptAt, delivery.SentAt, delivery.ID)
+	return err
+}
+
+// GetDeliveries retrieves the entries of the delivery log matching filters, newest first
+func (r *EmailRepository) GetDeliveries(workspaceID string, filters models.EmailDeliveryFilters) ([]models.EmailDelivery, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"workspace_id = $1"}
+	args := []any{workspaceID}
+	argIndex := 2
+
+	if filters.UserID != "" {
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", argIndex))
+		args = append(args, filters.UserID)
+		argIndex++
+	}
+	if filters.Kind != "" {
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", argIndex))
+		args = append(args, filters.Kind)
+		argIndex++
+	}
+	if filters.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, filters.Status)
+		argIndex++
+	}
+
+	query := `SELECT ` + emailDeliverySelectColumns + ` FROM email_deliveries
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY created_at DESC, id`
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filters.Limit)
+		argIndex++
+	}
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.EmailDelivery{}
+	for rows.Next() {
+		delivery, err := scanEmailDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// GetDeliveryByID retrieves an entry of the delivery log
+func (r *EmailRepository) GetDeliveryByID(workspaceID string, id string) (*models.EmailDelivery, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	row := r.db.QueryRow(`SELECT `+emailDeliverySelectColumns+` FROM email_deliveries WHERE id = $1 AND workspace_id = $2`,
+		id, workspaceID)
+	return scanEmailDelivery(row)
+}
+
+// RetryDelivery queues a failed email again with a fresh set of attempts.
+// It returns sql.ErrNoRows if there is no such failed email
+func (r *EmailRepository) RetryDelivery(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE email_deliveries SET status = $1, attempts = 0, last_error = '', next_attempt_at = $2
+		WHERE id = $3 AND workspace_id = $4 AND status = $5`,
+		models.EmailPending, time.Now(), id, workspaceID, models.EmailFailed)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanEmailDelivery(row rowScanner) (*models.EmailDelivery, error) {
+	delivery := &models.EmailDelivery{}
+	err := row.Scan(
+		&delivery.ID, &delivery.WorkspaceID, &delivery.UserID, &delivery.Kind, &delivery.NotificationID, &delivery.DigestDate,
+		&delivery.To, &delivery.Subject, &delivery.Status, &delivery.Attempts, &delivery.LastError,
+		&delivery.NextAttemptAt, &delivery.SentAt, &delivery.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrDuplicate is returned when an insert or update violates a uniqueness constraint
+var ErrDuplicate = errors.New("duplicate record")
+
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation reports whether err is a Postgres unique_violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+// ErrInUse is returned when a delete is rejected because other records still reference the row
+var ErrInUse = errors.New("record is in use")
+
+const foreignKeyViolationCode = "23503"
+
+// isForeignKeyViolation reports whether err is a Postgres foreign_key_violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode
+}
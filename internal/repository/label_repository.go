@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// LabelRepository handles database operations for labels and their assignment to tasks.
+// Every method is scoped to a single workspace
+type LabelRepository struct {
+	db *sql.DB
+}
+
+// NewLabelRepository creates a new instance of LabelRepository
+func NewLabelRepository(db *sql.DB) *LabelRepository {
+	return &LabelRepository{db: db}
+}
+
+// CreateLabel inserts a new label. It returns ErrDuplicate if the name is taken in the workspace
+func (r *LabelRepository) CreateLabel(label *models.Label) error {
+	if err := requireWorkspace(label.WorkspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO labels (id, workspace_id, name, color, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)`
+
+	_, err := r.db.Exec(query, label.ID, label.WorkspaceID, label.Name, label.Color, time.Now())
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// GetLabelByID retrieves a label by its ID
+func (r *LabelRepository) GetLabelByID(workspaceID string, id string) (*models.Label, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	label := &models.Label{WorkspaceID: workspaceID}
+	err := r.db.QueryRow(
+		"SELECT id, name, color FROM labels WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	).Scan(&label.ID, &label.Name, &label.Color)
+	if err != nil {
+		return nil, err
+	}
+	return label, nil
+}
+
+// GetLabels retrieves all labels ordered by name
+func (r *LabelRepository) GetLabels(workspaceID string) ([]models.Label, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query("SELECT id, name, color FROM labels WHERE workspace_id = $1 ORDER BY lower(name) ASC", workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+// UpdateLabel updates an existing label. It returns ErrDuplicate if the new name is taken
+func (r *LabelRepository) UpdateLabel(workspaceID string, id string, updates *models.UpdateLabelRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Color != nil {
+		setParts = append(setParts, fmt.Sprintf("color = $%d", argIndex))
+		args = append(args, *updates.Color)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE labels SET %s WHERE id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	_, err := r.db.Exec(query, args...)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// DeleteLabel removes a label and detaches it from every task
+func (r *LabelRepository) DeleteLabel(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM labels WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	return err
+}
+
+// AssignLabel attaches a label to a task, doing nothing if it is already attached.
+// Both the task and the label must belong to the workspace
+func (r *LabelRepository) AssignLabel(workspaceID string, taskID string, labelID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO task_labels (task_id, label_id)
+		SELECT t.id, l.id FROM tasks t, labels l
+		WHERE t.id = $1 AND l.id = $2 AND t.workspace_id = $3 AND l.workspace_id = $3
+		ON CONFLICT (task_id, label_id) DO NOTHING`
+	_, err := r.db.Exec(query, taskID, labelID, workspaceID)
+	return err
+}
+
+// UnassignLabel detaches a label from a task.
+// It returns sql.ErrNoRows if the label was not attached to the task
+func (r *LabelRepository) UnassignLabel(workspaceID string, taskID string, labelID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		DELETE FROM task_labels
+		WHERE task_id = $1 AND label_id = $2
+		AND label_id IN (SELECT id FROM labels WHERE workspace_id = $3)`, taskID, labelID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanLabels(rows *sql.Rows) ([]models.Label, error) {
+	var labels []models.Label
+	for rows.Next() {
+		var label models.Label
+		if err := rows.Scan(&label.ID, &label.Name, &label.Color); err != nil {
+			return nil, err
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, rows.Err()
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const linkSelectColumns = `id, task_id, url, title, description, kind, created_at, updated_at`
+
+// LinkRepository handles database operations for task links.
+// Every method is scoped to a single workspace through the link's task
+type LinkRepository struct {
+	db *sql.DB
+}
+
+// NewLinkRepository creates a new instance of LinkRepository
+func NewLinkRepository(db *sql.DB) *LinkRepository {
+	return &LinkRepository{db: db}
+}
+
+// CreateLink inserts a new link. It returns ErrDuplicate if the task already has the URL
+// and sql.ErrNoRows if the task is not in the workspace
+func (r *LinkRepository) CreateLink(workspaceID string, link *models.TaskLink) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO task_links (id, task_id, url, title, description, kind, created_at, updated_at)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8
+		WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $2 AND workspace_id = $9)`
+
+	result, err := r.db.Exec(query, link.ID, link.TaskID, link.URL, link.Title, link.Description,
+		link.Kind, link.CreatedAt, link.UpdatedAt, workspaceID)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetLinkByID retrieves a link by its ID
+func (r *LinkRepository) GetLinkByID(workspaceID string, id string) (*models.TaskLink, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + linkSelectColumns + ` FROM task_links l WHERE l.id = $1 AND ` + taskInWorkspace("l", 2)
+	return scanLink(r.db.QueryRow(query, id, workspaceID))
+}
+
+// GetTaskLinks retrieves all links of a task in the order they were added
+func (r *LinkRepository) GetTaskLinks(workspaceID string, taskID string) ([]models.TaskLink, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + linkSelectColumns + ` FROM task_links l
+		WHERE l.task_id = $1 AND ` + taskInWorkspace("l", 2) + `
+		ORDER BY l.created_at ASC`
+
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var links []models.TaskLink
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	return links, rows.Err()
+}
+
+// UpdateLink updates an existing link. It returns ErrDuplicate if the new URL is already linked to the task
+func (r *LinkRepository) UpdateLink(workspaceID string, id string, updates *models.UpdateTaskLinkRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.URL != nil {
+		setParts = append(setParts, fmt.Sprintf("url = $%d", argIndex))
+		args = append(args, *updates.URL)
+		argIndex++
+	}
+	if updates.Title != nil {
+		setParts = append(setParts, fmt.Sprintf("title = $%d", argIndex))
+		args = append(args, *updates.Title)
+		argIndex++
+	}
+	if updates.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *updates.Description)
+		argIndex++
+	}
+	if updates.Kind != nil {
+		setParts = append(setParts, fmt.Sprintf("kind = $%d", argIndex))
+		args = append(args, *updates.Kind)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE task_links SET %s WHERE id = $%d AND %s",
+		strings.Join(setParts, ", "), argIndex, taskInWorkspace("task_links", argIndex+1))
+	result, err := r.db.Exec(query, args...)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteLink removes a link
+func (r *LinkRepository) DeleteLink(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec("DELETE FROM task_links WHERE id = $1 AND "+taskInWorkspace("task_links", 2), id, workspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanLink(row rowScanner) (*models.TaskLink, error) {
+	link := &models.TaskLink{}
+	err := row.Scan(&link.ID, &link.TaskID, &link.URL, &link.Title, &link.Description,
+		&link.Kind, &link.CreatedAt, &link.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const milestoneSelectColumns = "id, workspace_id, project_id, name, description, due_date, created_at, updated_at"
+
+// MilestoneRepository handles database operations for milestones.
+// Every method is scoped to a single workspace
+type MilestoneRepository struct {
+	db *sql.DB
+}
+
+// NewMilestoneRepository creates a new instance of MilestoneRepository
+func NewMilestoneRepository(db *sql.DB) *MilestoneRepository {
+	return &MilestoneRepository{db: db}
+}
+
+// CreateMilestone inserts a new milestone
+func (r *MilestoneRepository) CreateMilestone(milestone *models.Milestone) error {
+	if err := requireWorkspace(milestone.WorkspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO milestones (id, workspace_id, project_id, name, description, due_date, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		milestone.ID, milestone.WorkspaceID, milestone.ProjectID, milestone.Name, milestone.Description, milestone.DueDate,
+		milestone.CreatedAt, milestone.UpdatedAt)
+	return err
+}
+
+// GetMilestoneByID retrieves a milestone with its task counts
+func (r *MilestoneRepository) GetMilestoneByID(workspaceID string, id string) (*models.Milestone, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	milestone, err := scanMilestone(r.db.QueryRow(
+		"SELECT "+milestoneSelectColumns+" FROM milestones WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := countTasksByStatus(r.db, "milestone_id", workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	setMilestoneCounts(milestone, counts)
+
+	return milestone, nil
+}
+
+// GetMilestones retrieves the milestones of the workspace, optionally of one project,
+// ordered by due date with their task counts
+func (r *MilestoneRepository) GetMilestones(workspaceID string, projectID string) ([]models.Milestone, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + milestoneSelectColumns + " FROM milestones WHERE workspace_id = $1"
+	args := []any{workspaceID}
+	if projectID != "" {
+		query += " AND project_id = $2"
+		args = append(args, projectID)
+	}
+	query += " ORDER BY due_date ASC, lower(name) ASC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var milestones []models.Milestone
+	for rows.Next() {
+		milestone, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, *milestone)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts, err := countTasksByStatus(r.db, "milestone_id", workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range milestones {
+		setMilestoneCounts(&milestones[i], counts)
+	}
+
+	return milestones, nil
+}
+
+// UpdateMilestone updates an existing milestone with the provided changes
+func (r *MilestoneRepository) UpdateMilestone(workspaceID string, id string, updates *models.UpdateMilestoneRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *updates.Description)
+		argIndex++
+	}
+	if updates.DueDate != nil {
+		setParts = append(setParts, fmt.Sprintf("due_date = $%d", argIndex))
+		args = append(args, *updates.DueDate)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE milestones SET %s WHERE id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteMilestone removes a milestone. Its tasks are kept and detached by ON DELETE SET NULL
+func (r *MilestoneRepository) DeleteMilestone(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM milestones WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	return err
+}
+
+// setMilestoneCounts fills in TaskCounts, TotalTasks and Progress
+func setMilestoneCounts(milestone *models.Milestone, counts *taskCounts) {
+	milestone.TaskCounts, milestone.TotalTasks = counts.tally(milestone.ID)
+	milestone.Progress = counts.progress(milestone.ID)
+}
+
+func scanMilestone(row rowScanner) (*models.Milestone, error) {
+	milestone := &models.Milestone{}
+	err := row.Scan(&milestone.ID, &milestone.WorkspaceID, &milestone.ProjectID, &milestone.Name, &milestone.Description,
+		&milestone.DueDate, &milestone.CreatedAt, &milestone.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
@@ -0,0 +1,418 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/google/uuid"
+)
+
+const notificationSelectColumns = `
+	n.id, n.workspace_id, n.user_id, n.task_id, n.subtask_id, n.comment_id, n.type, n.action, n.actor_id, n.changes,
+	n.due_date, n.read_at, n.created_at, COALESCE(t.title, ''),
+	u.id, u.name, u.src`
+
+// NotificationRepository handles database operations for task watchers, notifications
+// and muted notification types. Every method is scoped to a single workspace
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// AddWatcher makes a user watch a task; watching it already is not an error
+func (r *NotificationRepository) AddWatcher(workspaceID string, taskID string, userID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+	return addWatcher(r.db, workspaceID, taskID, userID)
+}
+
+// addWatcher makes a user watch a task with db, which may be a transaction
+func addWatcher(db execer, workspaceID string, taskID string, userID string) error {
+	_, err := db.Exec(`
+		INSERT INTO task_watchers (task_id, user_id, workspace_id, created_at)
+		SELECT id, $2, workspace_id, $3 FROM tasks WHERE id = $1 AND workspace_id = $4
+		ON CONFLICT (task_id, user_id) DO NOTHING`,
+		taskID, userID, time.Now(), workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to add watcher %s: %w", userID, err)
+	}
+	return nil
+}
+
+// RemoveWatcher stops a user watching a task.
+// It returns sql.ErrNoRows if the user does not watch the task
+func (r *NotificationRepository) RemoveWatcher(workspaceID string, taskID string, userID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec("DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2 AND workspace_id = $3",
+		taskID, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetWatchers retrieves the users watching a task, by name
+func (r *NotificationRepository) GetWatchers(workspaceID string, taskID string) ([]models.User, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT u.id, u.name, u.src
+		FROM task_watchers w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.task_id = $1 AND w.workspace_id = $2
+		ORDER BY u.name, u.id`, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Src); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// NotifyWatchers stores a copy of notification for every watcher of its task other than its actor.
+// Users who muted the notification's type are skipped. It returns the number of notifications stored
+func (r *NotificationRepository) NotifyWatchers(notification *models.Notification) (int, error) {
+	if err := requireWorkspace(notification.WorkspaceID); err != nil {
+		return 0, err
+	}
+
+	rows, err := r.db.Query("SELECT user_id FROM task_watchers WHERE task_id = $1 AND workspace_id = $2",
+		notification.TaskID, notification.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	return r.NotifyUsers(notification, userIDs)
+}
+
+// NotifyUsers stores a copy of notification, with an ID of its own, for each of userIDs other than its actor.
+// Users who muted the notification's type are skipped. It returns the number of notifications stored
+func (r *NotificationRepository) NotifyUsers(notification *models.Notification, userIDs []string) (int, error) {
+	if err := requireWorkspace(notification.WorkspaceID); err != nil {
+		return 0, err
+	}
+
+	changes, err := json.Marshal(notification.Changes)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stored := 0
+	for _, userID := range userIDs {
+		if notification.ActorID != nil && userID == *notification.ActorID {
+			continue
+		}
+		result, err := tx.Exec(`
+			INSERT INTO notifications (id, workspace_id, user_id, task_id, subtask_id, comment_id, type, action, actor_id, changes, due_date, created_at)
+			SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			WHERE NOT EXISTS (
+				SELECT 1 FROM notification_mutes m WHERE m.workspace_id = $2 AND m.user_id = $3 AND m.type = $7
+			)`,
+			uuid.New().String(), notification.WorkspaceID, userID, notification.TaskID, notification.SubTaskID, notification.CommentID,
+			notification.Type, notification.Action, notification.ActorID, string(changes), notification.DueDate, notification.CreatedAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to notify user %s: %w", userID, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		stored += int(affected)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return stored, nil
+}
+
+// GetNotifications retrieves the notifications of a user matching filters, newest first
+func (r *NotificationRepository) GetNotifications(workspaceID string, userID string, filters models.NotificationFilters) ([]models.Notification, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"n.workspace_id = $1", "n.user_id = $2"}
+	args := []any{workspaceID, userID}
+	argIndex := 3
+
+	if filters.Unread {
+		conditions = append(conditions, "n.read_at IS NULL")
+	}
+	if filters.Type != "" {
+		conditions = append(conditions, fmt.Sprintf("n.type = $%d", argIndex))
+		args = append(args, filters.Type)
+		argIndex++
+	}
+
+	query := `SELECT ` + notificationSelectColumns + `
+		FROM notifications n
+		LEFT JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN users u ON u.id = n.actor_id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY n.created_at DESC, n.id`
+	if filters.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filters.Limit)
+		argIndex++
+	}
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, filters.Offset)
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// GetNotification retrieves a notification of a user
+func (r *NotificationRepository) GetNotification(workspaceID string, userID string, id string) (*models.Notification, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	row := r.db.QueryRow(`SELECT `+notificationSelectColumns+`
+		FROM notifications n
+		LEFT JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN users u ON u.id = n.actor_id
+		WHERE n.id = $1 AND n.user_id = $2 AND n.workspace_id = $3`, id, userID, workspaceID)
+	return scanNotification(row)
+}
+
+// GetUnreadBetween retrieves up to limit unread notifications of a user created from from until before to, oldest first
+func (r *NotificationRepository) GetUnreadBetween(workspaceID string, userID string, from time.Time, to time.Time, limit int) ([]models.Notification, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`SELECT `+notificationSelectColumns+`
+		FROM notifications n
+		LEFT JOIN tasks t ON t.id = n.task_id
+		LEFT JOIN users u ON u.id = n.actor_id
+		WHERE n.workspace_id = $1 AND n.user_id = $2 AND n.read_at IS NULL AND n.created_at >= $3 AND n.created_at < $4
+		ORDER BY n.created_at ASC, n.id
+		LIMIT $5`, workspaceID, userID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// GetUnreadCounts counts the unread notifications of a user per type
+func (r *NotificationRepository) GetUnreadCounts(workspaceID string, userID string) (map[models.NotificationType]int, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT type, COUNT(*) FROM notifications
+		WHERE workspace_id = $1 AND user_id = $2 AND read_at IS NULL
+		GROUP BY type`, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[models.NotificationType]int)
+	for rows.Next() {
+		var notificationType models.NotificationType
+		var count int
+		if err := rows.Scan(&notificationType, &count); err != nil {
+			return nil, err
+		}
+		counts[notificationType] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// MarkRead marks a notification of a user as read; marking it again keeps the first read time.
+// It returns sql.ErrNoRows if the user has no such notification
+func (r *NotificationRepository) MarkRead(workspaceID string, userID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE notifications SET read_at = COALESCE(read_at, $1)
+		WHERE id = $2 AND user_id = $3 AND workspace_id = $4`,
+		time.Now(), id, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns how many there were
+func (r *NotificationRepository) MarkAllRead(workspaceID string, userID string) (int, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return 0, err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE notifications SET read_at = $1
+		WHERE user_id = $2 AND workspace_id = $3 AND read_at IS NULL`,
+		time.Now(), userID, workspaceID)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// GetMutedTypes retrieves the notification types a user muted
+func (r *NotificationRepository) GetMutedTypes(workspaceID string, userID string) ([]models.NotificationType, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query("SELECT type FROM notification_mutes WHERE workspace_id = $1 AND user_id = $2 ORDER BY type",
+		workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []models.NotificationType{}
+	for rows.Next() {
+		var notificationType models.NotificationType
+		if err := rows.Scan(&notificationType); err != nil {
+			return nil, err
+		}
+		types = append(types, notificationType)
+	}
+
+	return types, rows.Err()
+}
+
+// SetMutedTypes replaces the notification types a user muted
+func (r *NotificationRepository) SetMutedTypes(workspaceID string, userID string, types []models.NotificationType) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM notification_mutes WHERE workspace_id = $1 AND user_id = $2", workspaceID, userID); err != nil {
+		return err
+	}
+	for _, notificationType := range types {
+		_, err := tx.Exec("INSERT INTO notification_mutes (workspace_id, user_id, type) VALUES ($1, $2, $3)",
+			workspaceID, userID, notificationType)
+		if err != nil {
+			return fmt.Errorf("failed to mute %s: %w", notificationType, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func scanNotification(row rowScanner) (*models.Notification, error) {
+	notification := &models.Notification{}
+	var changes []byte
+	var actorID, actorName, actorSrc sql.NullString
+
+	err := row.Scan(
+		&notification.ID, &notification.WorkspaceID, &notification.UserID, &notification.TaskID, &notification.SubTaskID,
+		&notification.CommentID, &notification.Type, &notification.Action, &notification.ActorID, &changes,
+		&notification.DueDate, &notification.ReadAt, &notification.CreatedAt, &notification.TaskTitle,
+		&actorID, &actorName, &actorSrc,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &notification.Changes); err != nil {
+		return nil, fmt.Errorf("failed to decode changes of notification %s: %w", notification.ID, err)
+	}
+	if actorID.Valid {
+		notification.Actor = &models.User{ID: actorID.String, Name: actorName.String, Src: actorSrc.String}
+	}
+
+	return notification, nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const projectSelectColumns = "id, workspace_id, name, description, created_at, updated_at"
+
+// ProjectRepository handles database operations for projects.
+// Every method is scoped to a single workspace
+type ProjectRepository struct {
+	db *sql.DB
+}
+
+// NewProjectRepository creates a new instance of ProjectRepository
+func NewProjectRepository(db *sql.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+// CreateProject inserts a new project
+func (r *ProjectRepository) CreateProject(project *models.Project) error {
+	if err := requireWorkspace(project.WorkspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO projects (id, workspace_id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.Exec(query, project.ID, project.WorkspaceID, project.Name, project.Description, project.CreatedAt, project.UpdatedAt)
+	return err
+}
+
+// GetProjectByID retrieves a project with its task counts
+func (r *ProjectRepository) GetProjectByID(workspaceID string, id string) (*models.Project, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	project := &models.Project{}
+	err := r.db.QueryRow(
+		"SELECT "+projectSelectColumns+" FROM projects WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	).Scan(&project.ID, &project.WorkspaceID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := r.getTaskCounts(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	setProjectCounts(project, counts)
+
+	return project, nil
+}
+
+// GetProjects retrieves all projects ordered by name, with their task counts
+func (r *ProjectRepository) GetProjects(workspaceID string) ([]models.Project, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		"SELECT "+projectSelectColumns+" FROM projects WHERE workspace_id = $1 ORDER BY lower(name) ASC", workspaceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []models.Project
+	for rows.Next() {
+		var project models.Project
+		if err := rows.Scan(&project.ID, &project.WorkspaceID, &project.Name, &project.Description, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts, err := r.getTaskCounts(workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range projects {
+		setProjectCounts(&projects[i], counts)
+	}
+
+	return projects, nil
+}
+
+// UpdateProject updates an existing project with the provided changes
+func (r *ProjectRepository) UpdateProject(workspaceID string, id string, updates *models.UpdateProjectRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Description != nil {
+		setParts = append(setParts, fmt.Sprintf("description = $%d", argIndex))
+		args = append(args, *updates.Description)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE projects SET %s WHERE id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteProject removes a project. Its tasks are kept and detached by ON DELETE SET NULL
+func (r *ProjectRepository) DeleteProject(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM projects WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	return err
+}
+
+// getTaskCounts returns task counts per status of each project.
+// An empty projectID counts tasks of every project in the workspace
+func (r *ProjectRepository) getTaskCounts(workspaceID string, projectID string) (*taskCounts, error) {
+	return countTasksByStatus(r.db, "project_id", workspaceID, projectID)
+}
+
+// taskCounts holds the task counts per status of projects, sprints or milestones, keyed by their ID,
+// and the workflow statuses of their workspace
+type taskCounts struct {
+	groups   map[string]map[models.TaskStatus]int
+	statuses []models.WorkflowStatus
+}
+
+// countTasksByStatus returns task counts per status grouped by the value of column, which is one of
+// the task columns project_id, sprint_id or milestone_id. An empty id counts every group in the workspace
+func countTasksByStatus(db *sql.DB, column string, workspaceID string, id string) (*taskCounts, error) {
+	statuses, err := getWorkflowStatuses(db, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %[1]s, status, COUNT(*) FROM tasks WHERE workspace_id = $1 AND deleted_at IS NULL AND %[1]s IS NOT NULL", column)
+	args := []any{workspaceID}
+	if id != "" {
+		query += fmt.Sprintf(" AND %s = $2", column)
+		args = append(args, id)
+	}
+	query += fmt.Sprintf(" GROUP BY %s, status", column)
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := &taskCounts{groups: make(map[string]map[models.TaskStatus]int), statuses: statuses}
+	for rows.Next() {
+		var id string
+		var status models.TaskStatus
+		var count int
+		if err := rows.Scan(&id, &status, &count); err != nil {
+			return nil, err
+		}
+		if counts.groups[id] == nil {
+			counts.groups[id] = make(map[models.TaskStatus]int)
+		}
+		counts.groups[id][status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// tally returns the counts of group id, with zero for workflow statuses without tasks, and their sum
+func (c *taskCounts) tally(id string) (map[models.TaskStatus]int, int) {
+	tally := make(map[models.TaskStatus]int, len(c.statuses))
+	for _, status := range c.statuses {
+		tally[status.Key] = 0
+	}
+	total := 0
+	for status, count := range c.groups[id] {
+		tally[status] = count
+		total += count
+	}
+	return tally, total
+}
+
+// progress is the share of tasks of group id that are in a done status, rounded down
+func (c *taskCounts) progress(id string) int {
+	done, total := 0, 0
+	for _, status := range c.statuses {
+		count := c.groups[id][status.Key]
+		if status.Category == models.CategoryDone {
+			done += count
+		}
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	return done * 100 / total
+}
+
+// setProjectCounts fills in TaskCounts, reporting zero for statuses without tasks
+func setProjectCounts(project *models.Project, counts *taskCounts) {
+	project.TaskCounts, project.TotalTasks = counts.tally(project.ID)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// ReminderRepository handles database operations for the due-date reminders sent for tasks.
+// It works across workspaces, since reminders are sent by a background scheduler
+type ReminderRepository struct {
+	db *sql.DB
+}
+
+// NewReminderRepository creates a new instance of ReminderRepository
+func NewReminderRepository(db *sql.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// ClaimReminders records the reminder at offset before the due date as sent for up to limit open tasks
+// due after from and at or before to, skipping tasks it was already sent for at their current due date.
+// It returns the reminders recorded by this call. Safe to run concurrently from several replicas:
+// each reminder is claimed by exactly one caller
+func (r *ReminderRepository) ClaimReminders(offset time.Duration, from time.Time, to time.Time, limit int) ([]models.TaskReminder, error) {
+	offsetMinutes := int(offset / time.Minute)
+
+	rows, err := r.db.Query(`
+		INSERT INTO task_reminders (task_id, workspace_id, due_date, offset_minutes, sent_at)
+		SELECT t.id, t.workspace_id, t.due_date, $1, $2
+		FROM tasks t
+		WHERE t.due_date > $3 AND t.due_date <= $4 AND t.deleted_at IS NULL
+			AND NOT `+statusInCategory("t", models.CategoryDone)+`
+			AND NOT EXISTS (
+				SELECT 1 FROM task_reminders tr
+				WHERE tr.task_id = t.id AND tr.due_date = t.due_date AND tr.offset_minutes = $1
+			)
+		ORDER BY t.due_date ASC
+		LIMIT $5
+		ON CONFLICT (task_id, due_date, offset_minutes) DO NOTHING
+		RETURNING task_id, workspace_id, due_date, offset_minutes, sent_at`,
+		offsetMinutes, time.Now(), from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reminders []models.TaskReminder
+	for rows.Next() {
+		var reminder models.TaskReminder
+		var minutes int
+		if err := rows.Scan(&reminder.TaskID, &reminder.WorkspaceID, &reminder.DueDate, &minutes, &reminder.SentAt); err != nil {
+			return nil, err
+		}
+		reminder.Offset = time.Duration(minutes) * time.Minute
+		reminders = append(reminders, reminder)
+	}
+
+	return reminders, rows.Err()
+}
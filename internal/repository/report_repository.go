@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// taskEffortRemaining computes the remaining hours and story points of task t from the task and all
+// of its subtasks outside the trash. Items in a done status count as zero and an unset remaining_hours falls back to the estimate
+var taskEffortRemaining = `
+	CASE WHEN ` + statusInCategory("t", models.CategoryDone) + ` THEN 0 ELSE
+		COALESCE(t.remaining_hours, t.estimate_hours, 0) + COALESCE((
+			SELECT SUM(COALESCE(s.remaining_hours, s.estimate_hours, 0))
+			FROM sub_tasks s WHERE s.task_id = t.id AND s.deleted_at IS NULL AND NOT ` + statusInCategory("s", models.CategoryDone) + `), 0)
+	END,
+	CASE WHEN ` + statusInCategory("t", models.CategoryDone) + ` THEN 0 ELSE
+		COALESCE(t.story_points, 0) + COALESCE((
+			SELECT SUM(COALESCE(s.story_points, 0))
+			FROM sub_tasks s WHERE s.task_id = t.id AND s.deleted_at IS NULL AND NOT ` + statusInCategory("s", models.CategoryDone) + `), 0)
+	END`
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// recordTaskEffort stores a snapshot of the remaining effort of a task for burndown reports.
+// Nothing is stored when the effort did not change since the last snapshot
+func recordTaskEffort(db execer, workspaceID string, taskID string) error {
+	_, err := db.Exec(`
+		WITH effort AS (
+			SELECT t.id, t.workspace_id, `+taskEffortRemaining+`
+			FROM tasks t WHERE t.id = $1 AND t.workspace_id = $2
+		), latest AS (
+			SELECT remaining_hours, remaining_points FROM task_effort_snapshots
+			WHERE task_id = $1 ORDER BY recorded_at DESC LIMIT 1
+		)
+		INSERT INTO task_effort_snapshots (task_id, workspace_id, remaining_hours, remaining_points)
+		SELECT * FROM effort e(id, workspace_id, hours, points)
+		WHERE NOT EXISTS (SELECT 1 FROM latest l WHERE l.remaining_hours = e.hours AND l.remaining_points = e.points)`,
+		taskID, workspaceID)
+	if err != nil {
+		return fmt.Errorf("failed to record effort of task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// ReportRepository handles the aggregate queries behind reports.
+// Every method is scoped to a single workspace
+type ReportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository creates a new instance of ReportRepository
+func NewReportRepository(db *sql.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// GetBurndown returns the remaining effort at the end of each day from `from` through `days` days,
+// summed over the tasks matching filters. A task counts from its first effort snapshot on
+func (r *ReportRepository) GetBurndown(workspaceID string, filters models.TaskFilters, from time.Time, days int) (int, []models.BurndownDay, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return 0, nil, err
+	}
+
+	conditions, args := taskFilterConditions(workspaceID, filters)
+	matching := "SELECT id FROM tasks WHERE " + strings.Join(conditions, " AND ")
+
+	var tasks int
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM ("+matching+") m", args...).Scan(&tasks); err != nil {
+		return 0, nil, err
+	}
+
+	argIndex := len(args) + 1
+	args = append(args, from, days-1)
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT d.i, COALESCE(SUM(s.remaining_hours), 0), COALESCE(SUM(s.remaining_points), 0)
+		FROM generate_series(0, $%[2]d::int) AS d(i)
+		LEFT JOIN LATERAL (
+			SELECT DISTINCT ON (es.task_id) es.remaining_hours, es.remaining_points
+			FROM task_effort_snapshots es
+			WHERE es.task_id IN (%[3]s)
+				AND es.recorded_at < $%[1]d::timestamptz + (d.i + 1) * INTERVAL '24 hours'
+			ORDER BY es.task_id, es.recorded_at DESC
+		) s ON TRUE
+		GROUP BY d.i
+		ORDER BY d.i`, argIndex, argIndex+1, matching), args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	result := make([]models.BurndownDay, 0, days)
+	for rows.Next() {
+		var offset int
+		var day models.BurndownDay
+		if err := rows.Scan(&offset, &day.RemainingHours, &day.RemainingPoints); err != nil {
+			return 0, nil, err
+		}
+		day.Date = from.AddDate(0, 0, offset).Format(time.DateOnly)
+		result = append(result, day)
+	}
+
+	return tasks, result, rows.Err()
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const revisionSelectColumns = `
+	r.id, r.workspace_id, r.task_id, r.revision, r.actor_id, r.restored_from, r.created_at,
+	u.id, u.name, u.src`
+
+// RevisionRepository handles database operations for task revisions.
+// Every method is scoped to a single workspace
+type RevisionRepository struct {
+	db *sql.DB
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository
+func NewRevisionRepository(db *sql.DB) *RevisionRepository {
+	return &RevisionRepository{db: db}
+}
+
+// CreateRevision stores revision as the next revision of its task and sets its number.
+// A snapshot equal to the task's latest one is not stored again, leaving revision.Revision at 0
+func (r *RevisionRepository) CreateRevision(revision *models.TaskRevision) error {
+	if err := requireWorkspace(revision.WorkspaceID); err != nil {
+		return err
+	}
+
+	snapshot, err := json.Marshal(revision.Snapshot)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Locking the task serializes concurrent writers so revision numbers stay gapless
+	_, err = tx.Exec("SELECT 1 FROM tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE", revision.TaskID, revision.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRow(`
+		INSERT INTO task_revisions (id, workspace_id, task_id, revision, actor_id, restored_from, snapshot, created_at)
+		SELECT $1, $2, $3, COALESCE(MAX(revision), 0) + 1, $4, $5, $6, $7
+		FROM task_revisions WHERE task_id = $3
+		HAVING (SELECT latest.snapshot FROM task_revisions latest WHERE latest.task_id = $3
+			ORDER BY latest.revision DESC LIMIT 1) IS DISTINCT FROM $6::jsonb
+		RETURNING revision`,
+		revision.ID, revision.WorkspaceID, revision.TaskID, revision.ActorID, revision.RestoredFrom, string(snapshot), revision.CreatedAt,
+	).Scan(&revision.Revision)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record revision of task %s: %w", revision.TaskID, err)
+	}
+
+	return tx.Commit()
+}
+
+// GetRevisions retrieves the revisions of a task without their snapshots, newest first
+func (r *RevisionRepository) GetRevisions(workspaceID string, taskID string) ([]models.TaskRevision, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + revisionSelectColumns + `
+		FROM task_revisions r
+		LEFT JOIN users u ON u.id = r.actor_id
+		WHERE r.task_id = $1 AND r.workspace_id = $2
+		ORDER BY r.revision DESC`
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.TaskRevision{}
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+
+	return revisions, rows.Err()
+}
+
+// GetRevision retrieves one revision of a task together with its snapshot
+func (r *RevisionRepository) GetRevision(workspaceID string, taskID string, number int) (*models.TaskRevision, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + revisionSelectColumns + `, r.snapshot
+		FROM task_revisions r
+		LEFT JOIN users u ON u.id = r.actor_id
+		WHERE r.task_id = $1 AND r.workspace_id = $2 AND r.revision = $3`
+	var snapshot []byte
+	revision, err := scanRevision(r.db.QueryRow(query, taskID, workspaceID, number), &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(snapshot, &revision.Snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode revision %d of task %s: %w", number, taskID, err)
+	}
+
+	return revision, nil
+}
+
+// scanRevision reads a row selected with revisionSelectColumns followed by extra columns
+func scanRevision(row rowScanner, extra ...any) (*models.TaskRevision, error) {
+	revision := &models.TaskRevision{}
+	var actorID, actorName, actorSrc sql.NullString
+
+	dest := []any{
+		&revision.ID, &revision.WorkspaceID, &revision.TaskID, &revision.Revision, &revision.ActorID, &revision.RestoredFrom, &revision.CreatedAt,
+		&actorID, &actorName, &actorSrc,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if actorID.Valid {
+		revision.Actor = &models.User{ID: actorID.String, Name: actorName.String, Src: actorSrc.String}
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const seriesSelectColumns = "id, workspace_id, rrule, start_date, occurrences, current_task_id, active, created_at, updated_at"
+
+// SeriesRepository handles database operations for recurring task series
+type SeriesRepository struct {
+	db *sql.DB
+}
+
+// NewSeriesRepository creates a new instance of SeriesRepository
+func NewSeriesRepository(db *sql.DB) *SeriesRepository {
+	return &SeriesRepository{db: db}
+}
+
+// GetSeriesByID retrieves a series of the workspace by its ID
+func (r *SeriesRepository) GetSeriesByID(workspaceID string, id string) (*models.TaskSeries, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	return scanSeries(r.db.QueryRow(
+		"SELECT "+seriesSelectColumns+" FROM task_series WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	))
+}
+
+// StartSeries creates a series whose first occurrence is an existing task
+func (r *SeriesRepository) StartSeries(series *models.TaskSeries) error {
+	if err := requireWorkspace(series.WorkspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO task_series (id, workspace_id, rrule, start_date, occurrences, current_task_id, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, TRUE, $7, $8)`,
+		series.ID, series.WorkspaceID, series.RRule, series.StartDate, series.Occurrences, series.CurrentTaskID,
+		series.CreatedAt, series.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec("UPDATE tasks SET series_id = $1 WHERE id = $2 AND workspace_id = $3",
+		series.ID, series.CurrentTaskID, series.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// UpdateRule replaces the rule of a series and reactivates it
+func (r *SeriesRepository) UpdateRule(workspaceID string, id string, rrule string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE task_series SET rrule = $1, active = TRUE, updated_at = $2 WHERE id = $3 AND workspace_id = $4",
+		rrule, time.Now(), id, workspaceID)
+	return err
+}
+
+// EndSeries stops a series from spawning further occurrences
+func (r *SeriesRepository) EndSeries(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(
+		"UPDATE task_series SET active = FALSE, updated_at = $1 WHERE id = $2 AND workspace_id = $3",
+		time.Now(), id, workspaceID)
+	return err
+}
+
+// GetDueSeries returns active series, across all workspaces, whose latest occurrence was due before now
+// and is not in the trash.
+// It is meant for the background recurrence sweep, not for request handling
+func (r *SeriesRepository) GetDueSeries(now time.Time, limit int) ([]models.TaskSeries, error) {
+	rows, err := r.db.Query(`
+		SELECT s.id, s.workspace_id, s.rrule, s.start_date, s.occurrences, s.current_task_id, s.active, s.created_at, s.updated_at
+		FROM task_series s
+		JOIN tasks t ON t.id = s.current_task_id
+		WHERE s.active AND t.due_date < $1 AND t.deleted_at IS NULL
+		ORDER BY t.due_date ASC
+		LIMIT $2`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []models.TaskSeries
+	for rows.Next() {
+		item, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, *item)
+	}
+
+	return series, rows.Err()
+}
+
+// CreateOccurrence inserts the next occurrence of a series and makes it the current one.
+// The series must still point at expectedCurrentTaskID; otherwise another caller already
+// advanced it and nothing is written. The returned flag reports whether the task was created
+func (r *SeriesRepository) CreateOccurrence(seriesID string, expectedCurrentTaskID string, occurrences int, task *models.Task) (bool, error) {
+	if err := requireWorkspace(task.WorkspaceID); err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`
+		SELECT id FROM task_series
+		WHERE id = $1 AND workspace_id = $2 AND current_task_id = $3 AND active
+		FOR UPDATE`, seriesID, task.WorkspaceID, expectedCurrentTaskID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := insertTask(tx, task); err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(
+		"UPDATE task_series SET current_task_id = $1, occurrences = $2, updated_at = $3 WHERE id = $4",
+		task.ID, occurrences, time.Now(), seriesID)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// FinishSeries deactivates a series whose rule has no occurrences left,
+// provided it still points at expectedCurrentTaskID
+func (r *SeriesRepository) FinishSeries(workspaceID string, id string, expectedCurrentTaskID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		UPDATE task_series SET active = FALSE, updated_at = $1
+		WHERE id = $2 AND workspace_id = $3 AND current_task_id = $4`,
+		time.Now(), id, workspaceID, expectedCurrentTaskID)
+	return err
+}
+
+func scanSeries(row rowScanner) (*models.TaskSeries, error) {
+	series := &models.TaskSeries{}
+	err := row.Scan(&series.ID, &series.WorkspaceID, &series.RRule, &series.StartDate, &series.Occurrences,
+		&series.CurrentTaskID, &series.Active, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
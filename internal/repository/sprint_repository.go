@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const sprintSelectColumns = "id, workspace_id, project_id, name, goal, start_date, end_date, state, started_at, closed_at, carried_over, created_at, updated_at"
+
+// SprintRepository handles database operations for sprints.
+// Every method is scoped to a single workspace
+type SprintRepository struct {
+	db *sql.DB
+}
+
+// NewSprintRepository creates a new instance of SprintRepository
+func NewSprintRepository(db *sql.DB) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+// CreateSprint inserts a new sprint
+func (r *SprintRepository) CreateSprint(sprint *models.Sprint) error {
+	if err := requireWorkspace(sprint.WorkspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO sprints (id, workspace_id, project_id, name, goal, start_date, end_date, state, carried_over, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		sprint.ID, sprint.WorkspaceID, sprint.ProjectID, sprint.Name, sprint.Goal, sprint.StartDate, sprint.EndDate, sprint.State,
+		sprint.CarriedOver, sprint.CreatedAt, sprint.UpdatedAt)
+	return err
+}
+
+// GetSprintByID retrieves a sprint with its task counts
+func (r *SprintRepository) GetSprintByID(workspaceID string, id string) (*models.Sprint, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	sprint, err := scanSprint(r.db.QueryRow(
+		"SELECT "+sprintSelectColumns+" FROM sprints WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := countTasksByStatus(r.db, "sprint_id", workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	setSprintCounts(sprint, counts)
+
+	return sprint, nil
+}
+
+// GetSprints retrieves the sprints matching filters, latest start date first, with their task counts
+func (r *SprintRepository) GetSprints(workspaceID string, filters models.SprintFilters) ([]models.Sprint, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + sprintSelectColumns + " FROM sprints WHERE workspace_id = $1"
+	args := []any{workspaceID}
+	if filters.State != "" {
+		args = append(args, filters.State)
+		query += fmt.Sprintf(" AND state = $%d", len(args))
+	}
+	if filters.ProjectID != "" {
+		args = append(args, filters.ProjectID)
+		query += fmt.Sprintf(" AND project_id = $%d", len(args))
+	}
+	query += " ORDER BY start_date DESC, created_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sprints []models.Sprint
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, *sprint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts, err := countTasksByStatus(r.db, "sprint_id", workspaceID, "")
+	if err != nil {
+		return nil, err
+	}
+	for i := range sprints {
+		setSprintCounts(&sprints[i], counts)
+	}
+
+	return sprints, nil
+}
+
+// GetNextPlannedSprint retrieves the planned sprint of the same project (or of no project)
+// that starts first, other than excludeID. It returns sql.ErrNoRows if there is none
+func (r *SprintRepository) GetNextPlannedSprint(workspaceID string, projectID *string, excludeID string) (*models.Sprint, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	return scanSprint(r.db.QueryRow(`
+		SELECT `+sprintSelectColumns+` FROM sprints
+		WHERE workspace_id = $1 AND project_id IS NOT DISTINCT FROM $2 AND state = $3 AND id <> $4
+		ORDER BY start_date ASC, created_at ASC
+		LIMIT 1`, workspaceID, projectID, models.SprintPlanned, excludeID))
+}
+
+// UpdateSprint updates an existing sprint with the provided changes
+func (r *SprintRepository) UpdateSprint(workspaceID string, id string, updates *models.UpdateSprintRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Goal != nil {
+		setParts = append(setParts, fmt.Sprintf("goal = $%d", argIndex))
+		args = append(args, *updates.Goal)
+		argIndex++
+	}
+	if updates.StartDate != nil {
+		setParts = append(setParts, fmt.Sprintf("start_date = $%d", argIndex))
+		args = append(args, *updates.StartDate)
+		argIndex++
+	}
+	if updates.EndDate != nil {
+		setParts = append(setParts, fmt.Sprintf("end_date = $%d", argIndex))
+		args = append(args, *updates.EndDate)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE sprints SET %s WHERE id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// StartSprint moves a planned sprint to active. It returns ErrDuplicate if the project
+// already has an active sprint and sql.ErrNoRows if the sprint is not planned
+func (r *SprintRepository) StartSprint(workspaceID string, id string, startedAt time.Time) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE sprints SET state = $1, started_at = $2, updated_at = $2
+		WHERE id = $3 AND workspace_id = $4 AND state = $5`,
+		models.SprintActive, startedAt, id, workspaceID, models.SprintPlanned)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CloseSprint closes an active sprint and, in the same transaction, moves its tasks that are neither done
+// nor in the trash to carryOverTo, or to the backlog when carryOverTo is nil. It returns the IDs of the tasks moved,
+// or sql.ErrNoRows if the sprint is not active
+func (r *SprintRepository) CloseSprint(workspaceID string, id string, carryOverTo *string, closedAt time.Time) ([]string, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var state models.SprintState
+	err = tx.QueryRow("SELECT state FROM sprints WHERE id = $1 AND workspace_id = $2 FOR UPDATE", id, workspaceID).Scan(&state)
+	if err != nil {
+		return nil, err
+	}
+	if state != models.SprintActive {
+		return nil, sql.ErrNoRows
+	}
+
+	rows, err := tx.Query(`
+		UPDATE tasks SET sprint_id = $1, updated_at = $2
+		WHERE sprint_id = $3 AND workspace_id = $4 AND deleted_at IS NULL AND NOT `+statusInCategory("tasks", models.CategoryDone)+`
+		RETURNING id`,
+		carryOverTo, closedAt, id, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to carry over tasks: %w", err)
+	}
+	var carried []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		carried = append(carried, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to carry over tasks: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE sprints SET state = $1, closed_at = $2, carried_over = $3, updated_at = $2
+		WHERE id = $4 AND workspace_id = $5`,
+		models.SprintClosed, closedAt, len(carried), id, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return carried, tx.Commit()
+}
+
+// DeleteSprint removes a sprint. Its tasks are kept and detached by ON DELETE SET NULL
+func (r *SprintRepository) DeleteSprint(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM sprints WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	return err
+}
+
+// setSprintCounts fills in TaskCounts, TotalTasks and Progress
+func setSprintCounts(sprint *models.Sprint, counts *taskCounts) {
+	sprint.TaskCounts, sprint.TotalTasks = counts.tally(sprint.ID)
+	sprint.Progress = counts.progress(sprint.ID)
+}
+
+func scanSprint(row rowScanner) (*models.Sprint, error) {
+	sprint := &models.Sprint{}
+	err := row.Scan(&sprint.ID, &sprint.WorkspaceID, &sprint.ProjectID, &sprint.Name, &sprint.Goal, &sprint.StartDate, &sprint.EndDate,
+		&sprint.State, &sprint.StartedAt, &sprint.ClosedAt, &sprint.CarriedOver, &sprint.CreatedAt, &sprint.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
@@ -9,7 +9,12 @@ import (
 	"github.com/Sasha125588/event_app/internal/models"
 )
 
-// SubTaskRepository handles database operations for subtasks
+var subTaskSelectColumns = `id, workspace_id, task_id, parent_subtask_id, title, description, status,
+	` + statusCategoryOf("sub_tasks") + ` AS status_category, "order",
+	estimate_hours, story_points, remaining_hours, created_at, updated_at, deleted_at`
+
+// SubTaskRepository handles database operations for subtasks.
+// Every method is scoped to a single workspace
 type SubTaskRepository struct {
 	db *sql.DB
 }
@@ -19,12 +24,37 @@ func NewSubTaskRepository(db *sql.DB) *SubTaskRepository {
 	return &SubTaskRepository{db: db}
 }
 
+// lockSubTaskOrders locks the row of a task so that writers of its subtasks' orders run one at a time.
+// Every change to the order of subtasks takes this lock first, in its own transaction
+func lockSubTaskOrders(tx *sql.Tx, workspaceID string, taskID string) error {
+	var id string
+	return tx.QueryRow("SELECT id FROM tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE", taskID, workspaceID).Scan(&id)
+}
+
 // CreateSubTask creates a new subtask in the database
-// The order is automatically set to be the last in the task's subtask list
+// The order is automatically set to be the last among its siblings under the same parent
 func (r *SubTaskRepository) CreateSubTask(subTask *models.SubTask) error {
-	// Get the maximum order for the task
+	if err := requireWorkspace(subTask.WorkspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockSubTaskOrders(tx, subTask.WorkspaceID, subTask.TaskID); err != nil {
+		return err
+	}
+
+	// Get the maximum order among the siblings, counting those in the trash so that they can be restored in place
 	var maxOrder int
-	err := r.db.QueryRow("SELECT COALESCE(MAX(\"order\"), -1) FROM sub_tasks WHERE task_id = $1", subTask.TaskID).Scan(&maxOrder)
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX("order"), -1) FROM sub_tasks
+		WHERE task_id = $1 AND workspace_id = $2 AND parent_subtask_id IS NOT DISTINCT FROM $3`,
+		subTask.TaskID, subTask.WorkspaceID, subTask.ParentSubTaskID,
+	).Scan(&maxOrder)
 	if err != nil {
 		return err
 	}
@@ -33,45 +63,48 @@ func (r *SubTaskRepository) CreateSubTask(subTask *models.SubTask) error {
 	subTask.Order = maxOrder + 1
 
 	query := `
-		INSERT INTO sub_tasks (id, task_id, title, description, status, "order", created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO sub_tasks (id, workspace_id, task_id, parent_subtask_id, title, description, status, "order",
+			estimate_hours, story_points, remaining_hours, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id`
 
-	return r.db.QueryRow(
+	err = tx.QueryRow(
 		query,
 		subTask.ID,
+		subTask.WorkspaceID,
 		subTask.TaskID,
+		subTask.ParentSubTaskID,
 		subTask.Title,
 		subTask.Description,
 		subTask.Status,
 		subTask.Order,
+		subTask.EstimateHours,
+		subTask.StoryPoints,
+		subTask.RemainingHours,
 		subTask.CreatedAt,
 		subTask.UpdatedAt,
 	).Scan(&subTask.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := recordTaskEffort(tx, subTask.WorkspaceID, subTask.TaskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 // GetSubTaskByID retrieves a subtask by its ID
-func (r *SubTaskRepository) GetSubTaskByID(id string) (*models.SubTask, error) {
-	query := `
-		SELECT id, task_id, title, description, status, "order", created_at, updated_at
-		FROM sub_tasks WHERE id = $1
-	`
-	fmt.Printf("GetSubTaskByID query: %s with id: %s\n", query, id)
-
-	subTask := &models.SubTask{}
-	row := r.db.QueryRow(query, id)
+func (r *SubTaskRepository) GetSubTaskByID(workspaceID string, id string) (*models.SubTask, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
 
-	err := row.Scan(
-		&subTask.ID,
-		&subTask.TaskID,
-		&subTask.Title,
-		&subTask.Description,
-		&subTask.Status,
-		&subTask.Order,
-		&subTask.CreatedAt,
-		&subTask.UpdatedAt,
-	)
+	query := `SELECT ` + subTaskSelectColumns + ` FROM sub_tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`
+	fmt.Printf("GetSubTaskByID query: %s with id: %s\n", query, id)
 
+	subTask, err := scanSubTask(r.db.QueryRow(query, id, workspaceID))
 	if err != nil {
 		fmt.Printf("GetSubTaskByID error: %v\n", err)
 		return nil, err
@@ -82,7 +115,11 @@ func (r *SubTaskRepository) GetSubTaskByID(id string) (*models.SubTask, error) {
 }
 
 // UpdateSubTask updates an existing subtask with the provided changes
-func (r *SubTaskRepository) UpdateSubTask(id string, updates *models.UpdateSubTaskRequest) error {
+func (r *SubTaskRepository) UpdateSubTask(workspaceID string, id string, updates *models.UpdateSubTaskRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
 	setParts := []string{}
 	args := []interface{}{}
 	argIndex := 1
@@ -102,6 +139,21 @@ func (r *SubTaskRepository) UpdateSubTask(id string, updates *models.UpdateSubTa
 		args = append(args, *updates.Status)
 		argIndex++
 	}
+	if updates.EstimateHours != nil {
+		setParts = append(setParts, fmt.Sprintf("estimate_hours = $%d", argIndex))
+		args = append(args, *updates.EstimateHours)
+		argIndex++
+	}
+	if updates.StoryPoints != nil {
+		setParts = append(setParts, fmt.Sprintf("story_points = $%d", argIndex))
+		args = append(args, *updates.StoryPoints)
+		argIndex++
+	}
+	if updates.RemainingHours != nil {
+		setParts = append(setParts, fmt.Sprintf("remaining_hours = $%d", argIndex))
+		args = append(args, *updates.RemainingHours)
+		argIndex++
+	}
 
 	if len(setParts) == 0 {
 		return fmt.Errorf("no fields to update")
@@ -111,29 +163,65 @@ func (r *SubTaskRepository) UpdateSubTask(id string, updates *models.UpdateSubTa
 	args = append(args, time.Now())
 	argIndex++
 
-	args = append(args, id)
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE sub_tasks SET %s WHERE id = $%d AND workspace_id = $%d AND deleted_at IS NULL RETURNING task_id",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	var taskID string
+	err := r.db.QueryRow(query, args...).Scan(&taskID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	query := fmt.Sprintf("UPDATE sub_tasks SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
-	_, err := r.db.Exec(query, args...)
-	return err
+	return recordTaskEffort(r.db, workspaceID, taskID)
 }
 
-// DeleteSubTask removes a subtask from the database
-func (r *SubTaskRepository) DeleteSubTask(id string) error {
-	query := "DELETE FROM sub_tasks WHERE id = $1"
-	_, err := r.db.Exec(query, id)
-	return err
+// DeleteSubTask moves a subtask and its descendants to the trash.
+// They share one deleted_at so that RestoreSubTask brings them back together
+func (r *SubTaskRepository) DeleteSubTask(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	query := `
+		WITH RECURSIVE deleted AS (
+			SELECT id FROM sub_tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+			UNION ALL
+			SELECT s.id FROM sub_tasks s JOIN deleted d ON s.parent_subtask_id = d.id
+			WHERE s.deleted_at IS NULL
+		)
+		UPDATE sub_tasks SET deleted_at = $3
+		WHERE id IN (SELECT id FROM deleted)
+		RETURNING task_id`
+	var taskID string
+	err := r.db.QueryRow(query, id, workspaceID, time.Now()).Scan(&taskID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return recordTaskEffort(r.db, workspaceID, taskID)
 }
 
-// GetSubTasksByTaskID retrieves all subtasks for a specific task, ordered by their order field
-func (r *SubTaskRepository) GetSubTasksByTaskID(taskID string) ([]models.SubTask, error) {
+// GetSubTasksByTaskID retrieves all subtasks of a task at every depth,
+// ordered by their order field within each parent
+func (r *SubTaskRepository) GetSubTasksByTaskID(workspaceID string, taskID string) ([]models.SubTask, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
 	query := `
-		SELECT id, task_id, title, description, status, "order", created_at, updated_at
+		SELECT ` + subTaskSelectColumns + `
 		FROM sub_tasks
-		WHERE task_id = $1
-		ORDER BY "order" ASC`
+		WHERE task_id = $1 AND workspace_id = $2 AND deleted_at IS NULL
+		ORDER BY "order" ASC, created_at ASC`
 
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.db.Query(query, taskID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -141,28 +229,22 @@ func (r *SubTaskRepository) GetSubTasksByTaskID(taskID string) ([]models.SubTask
 
 	var subTasks []models.SubTask
 	for rows.Next() {
-		var subTask models.SubTask
-		err := rows.Scan(
-			&subTask.ID,
-			&subTask.TaskID,
-			&subTask.Title,
-			&subTask.Description,
-			&subTask.Status,
-			&subTask.Order,
-			&subTask.CreatedAt,
-			&subTask.UpdatedAt,
-		)
+		subTask, err := scanSubTask(rows)
 		if err != nil {
 			return nil, err
 		}
-		subTasks = append(subTasks, subTask)
+		subTasks = append(subTasks, *subTask)
 	}
 
 	return subTasks, nil
 }
 
-// ReorderSubTask updates the order of a subtask and adjusts other subtasks' orders accordingly
-func (r *SubTaskRepository) ReorderSubTask(taskID string, subTaskID string, newOrder int) error {
+// ReorderSubTask updates the order of a subtask and adjusts its siblings' orders accordingly
+func (r *SubTaskRepository) ReorderSubTask(workspaceID string, taskID string, subTaskID string, newOrder int) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
 	fmt.Printf("ReorderSubTask called with taskID: %s, subTaskID: %s, newOrder: %d\n", taskID, subTaskID, newOrder)
 
 	tx, err := r.db.Begin()
@@ -171,9 +253,17 @@ func (r *SubTaskRepository) ReorderSubTask(taskID string, subTaskID string, newO
 	}
 	defer tx.Rollback()
 
-	// Get current order of the subtask
+	if err := lockSubTaskOrders(tx, workspaceID, taskID); err != nil {
+		return fmt.Errorf("failed to lock task: %w", err)
+	}
+
+	// Get current order and parent of the subtask
 	var currentOrder int
-	err = tx.QueryRow("SELECT \"order\" FROM sub_tasks WHERE id = $1", subTaskID).Scan(&currentOrder)
+	var parentID *string
+	err = tx.QueryRow(
+		"SELECT \"order\", parent_subtask_id FROM sub_tasks WHERE id = $1 AND task_id = $2 AND workspace_id = $3 AND deleted_at IS NULL",
+		subTaskID, taskID, workspaceID,
+	).Scan(&currentOrder, &parentID)
 	if err != nil {
 		return fmt.Errorf("failed to get current order: %w", err)
 	}
@@ -187,23 +277,25 @@ func (r *SubTaskRepository) ReorderSubTask(taskID string, subTaskID string, newO
 	if currentOrder < newOrder {
 		// Сдвигаем элементы вверх
 		_, err = tx.Exec(`
-            UPDATE sub_tasks 
-            SET "order" = "order" - 1 
-            WHERE task_id = $1 
-            AND "order" > $2 
+            UPDATE sub_tasks
+            SET "order" = "order" - 1
+            WHERE task_id = $1
+            AND parent_subtask_id IS NOT DISTINCT FROM $5
+            AND "order" > $2
             AND "order" <= $3
             AND id != $4`,
-			taskID, currentOrder, newOrder, subTaskID)
+			taskID, currentOrder, newOrder, subTaskID, parentID)
 	} else {
 		// Сдвигаем элементы вниз
 		_, err = tx.Exec(`
-            UPDATE sub_tasks 
-            SET "order" = "order" + 1 
-            WHERE task_id = $1 
-            AND "order" >= $2 
+            UPDATE sub_tasks
+            SET "order" = "order" + 1
+            WHERE task_id = $1
+            AND parent_subtask_id IS NOT DISTINCT FROM $5
+            AND "order" >= $2
             AND "order" < $3
             AND id != $4`,
-			taskID, newOrder, currentOrder, subTaskID)
+			taskID, newOrder, currentOrder, subTaskID, parentID)
 	}
 	if err != nil {
 		return fmt.Errorf("failed to update other tasks order: %w", err)
@@ -222,3 +314,281 @@ func (r *SubTaskRepository) ReorderSubTask(taskID string, subTaskID string, newO
 
 	return nil
 }
+
+// SetSubTaskOrders sets the order of the given live subtasks of a task as is, without shifting their siblings
+func (r *SubTaskRepository) SetSubTaskOrders(workspaceID string, taskID string, orders map[string]int) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockSubTaskOrders(tx, workspaceID, taskID); err != nil {
+		return err
+	}
+
+	for id, order := range orders {
+		_, err := tx.Exec(`UPDATE sub_tasks SET "order" = $1, updated_at = $2
+			WHERE id = $3 AND task_id = $4 AND workspace_id = $5 AND deleted_at IS NULL`,
+			order, time.Now(), id, taskID, workspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to set order of subtask %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RollupStatus recomputes the status of parentID and then of each of its ancestors from their
+// direct children outside the trash: done when every child is done, todo when none has started, doing otherwise.
+// A subtask already in the resulting category keeps its status; otherwise it moves to the first
+// status of that category in the workflow, regardless of transition rules, and the change is recorded
+// in the audit log without an actor. The walk stops at the first subtask that has no children
+func (r *SubTaskRepository) RollupStatus(workspaceID string, parentID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for id := &parentID; id != nil; {
+		var total, done, todo int
+		err := tx.QueryRow(`
+			SELECT COUNT(*),
+				COUNT(*) FILTER (WHERE wf.category = $3),
+				COUNT(*) FILTER (WHERE wf.category = $4)
+			FROM sub_tasks s
+			JOIN workflow_statuses wf ON wf.workspace_id = s.workspace_id AND wf.key = s.status
+			WHERE s.parent_subtask_id = $1 AND s.workspace_id = $2 AND s.deleted_at IS NULL`,
+			*id, workspaceID, models.CategoryDone, models.CategoryTodo,
+		).Scan(&total, &done, &todo)
+		if err != nil {
+			return err
+		}
+		if total == 0 {
+			break
+		}
+
+		category := models.CategoryDoing
+		switch total {
+		case done:
+			category = models.CategoryDone
+		case todo:
+			category = models.CategoryTodo
+		}
+
+		// wf still holds the row of the status before the update
+		var taskID string
+		var next *string
+		var oldStatus, newStatus models.TaskStatus
+		err = tx.QueryRow(`
+			UPDATE sub_tasks SET
+				status = CASE WHEN wf.category = $1 THEN sub_tasks.status ELSE (
+					SELECT d.key FROM workflow_statuses d
+					WHERE d.workspace_id = sub_tasks.workspace_id AND d.category = $1
+					ORDER BY d.position, d.key LIMIT 1) END,
+				updated_at = CASE WHEN wf.category = $1 THEN sub_tasks.updated_at ELSE $2 END
+			FROM workflow_statuses wf
+			WHERE sub_tasks.id = $3 AND sub_tasks.workspace_id = $4
+				AND wf.workspace_id = sub_tasks.workspace_id AND wf.key = sub_tasks.status
+			RETURNING sub_tasks.task_id, sub_tasks.parent_subtask_id, wf.key, sub_tasks.status`,
+			category, time.Now(), *id, workspaceID,
+		).Scan(&taskID, &next, &oldStatus, &newStatus)
+		if err == sql.ErrNoRows {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if oldStatus != newStatus {
+			subTaskID := *id
+			activity := models.NewActivity(workspaceID, taskID, &subTaskID, "", models.ActionUpdated,
+				[]models.FieldChange{{Field: "status", Old: oldStatus, New: newStatus}})
+			if err := insertActivity(tx, activity); err != nil {
+				return err
+			}
+		}
+		id = next
+	}
+
+	// Done subtasks no longer count towards the remaining effort
+	var taskID string
+	err = tx.QueryRow("SELECT task_id FROM sub_tasks WHERE id = $1 AND workspace_id = $2", parentID, workspaceID).Scan(&taskID)
+	if err == nil {
+		err = recordTaskEffort(tx, workspaceID, taskID)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetDeletedSubTaskByID retrieves a subtask in the trash by its ID
+func (r *SubTaskRepository) GetDeletedSubTaskByID(workspaceID string, id string) (*models.SubTask, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + subTaskSelectColumns + ` FROM sub_tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL`
+	return scanSubTask(r.db.QueryRow(query, id, workspaceID))
+}
+
+// GetDeletedSubTasks retrieves the subtasks in the trash of tasks that are not, most recently deleted first.
+// Subtasks deleted together with their parent subtask are left out; they come back when the parent is restored
+func (r *SubTaskRepository) GetDeletedSubTasks(workspaceID string) ([]models.SubTask, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT ` + subTaskSelectColumns + `
+		FROM sub_tasks
+		WHERE workspace_id = $1 AND deleted_at IS NOT NULL
+			AND EXISTS (SELECT 1 FROM tasks t WHERE t.id = sub_tasks.task_id AND t.deleted_at IS NULL)
+			AND NOT EXISTS (
+				SELECT 1 FROM sub_tasks p
+				WHERE p.id = sub_tasks.parent_subtask_id AND p.deleted_at = sub_tasks.deleted_at)
+		ORDER BY deleted_at DESC`
+	rows, err := r.db.Query(query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subTasks := []models.SubTask{}
+	for rows.Next() {
+		subTask, err := scanSubTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		subTasks = append(subTasks, *subTask)
+	}
+
+	return subTasks, rows.Err()
+}
+
+// RestoreSubTask takes a subtask out of the trash together with the descendants deleted with it.
+// It returns to its original order among its siblings; if another subtask took that place
+// meanwhile, that sibling and the ones after it move down by one.
+// It returns sql.ErrNoRows if the subtask is not in the trash
+func (r *SubTaskRepository) RestoreSubTask(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var taskID string
+	err = tx.QueryRow("SELECT task_id FROM sub_tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL",
+		id, workspaceID).Scan(&taskID)
+	if err != nil {
+		return err
+	}
+	if err := lockSubTaskOrders(tx, workspaceID, taskID); err != nil {
+		return err
+	}
+
+	var parentID *string
+	var order int
+	err = tx.QueryRow(`
+		SELECT parent_subtask_id, "order" FROM sub_tasks
+		WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NOT NULL
+		FOR UPDATE`, id, workspaceID).Scan(&parentID, &order)
+	if err != nil {
+		return err
+	}
+
+	var taken bool
+	err = tx.QueryRow(`
+		SELECT EXISTS (
+			SELECT 1 FROM sub_tasks
+			WHERE task_id = $1 AND parent_subtask_id IS NOT DISTINCT FROM $2 AND "order" = $3 AND deleted_at IS NULL)`,
+		taskID, parentID, order).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		_, err = tx.Exec(`
+			UPDATE sub_tasks SET "order" = "order" + 1
+			WHERE task_id = $1 AND parent_subtask_id IS NOT DISTINCT FROM $2 AND "order" >= $3 AND deleted_at IS NULL`,
+			taskID, parentID, order)
+		if err != nil {
+			return fmt.Errorf("failed to make room for the subtask: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		WITH RECURSIVE restored AS (
+			SELECT id, deleted_at FROM sub_tasks WHERE id = $1
+			UNION ALL
+			SELECT s.id, s.deleted_at FROM sub_tasks s JOIN restored r ON s.parent_subtask_id = r.id
+			WHERE s.deleted_at = r.deleted_at
+		)
+		UPDATE sub_tasks SET deleted_at = NULL WHERE id IN (SELECT id FROM restored)`, id)
+	if err != nil {
+		return err
+	}
+
+	if err := recordTaskEffort(tx, workspaceID, taskID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// PurgeDeletedSubTasks permanently removes up to limit subtasks, across all workspaces, that were
+// moved to the trash before the given time, and returns how many were removed.
+// Their descendants are removed by ON DELETE CASCADE. Safe to run concurrently from several replicas
+func (r *SubTaskRepository) PurgeDeletedSubTasks(before time.Time, limit int) (int, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM sub_tasks WHERE id IN (
+			SELECT id FROM sub_tasks WHERE deleted_at < $1
+			ORDER BY deleted_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED)`, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
+
+func scanSubTask(row rowScanner) (*models.SubTask, error) {
+	subTask := &models.SubTask{}
+	err := row.Scan(
+		&subTask.ID,
+		&subTask.WorkspaceID,
+		&subTask.TaskID,
+		&subTask.ParentSubTaskID,
+		&subTask.Title,
+		&subTask.Description,
+		&subTask.Status,
+		&subTask.StatusCategory,
+		&subTask.Order,
+		&subTask.EstimateHours,
+		&subTask.StoryPoints,
+		&subTask.RemainingHours,
+		&subTask.CreatedAt,
+		&subTask.UpdatedAt,
+		&subTask.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return subTask, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +10,31 @@ import (
 	"github.com/Sasha125588/event_app/internal/models"
 )
 
+// taskSelectColumns lists the columns read for every task. Counters backed by
+// their own tables are computed here rather than stored on the task row
+var taskSelectColumns = `id, workspace_id, project_id, sprint_id, milestone_id, title, icon_name, start_time, end_time, due_date, progress, status,
+	` + statusCategoryOf("tasks") + ` AS status_category, priority,
+	estimate_hours, story_points, remaining_hours, series_id, (SELECT s.rrule FROM task_series s WHERE s.id = tasks.series_id AND s.active) AS recurrence,
+	(SELECT COUNT(*) FROM comments c WHERE c.task_id = tasks.id) AS comments,
+	(SELECT COUNT(*) FROM attachments a WHERE a.task_id = tasks.id) AS attachments,
+	(SELECT COUNT(*) FROM task_links l WHERE l.task_id = tasks.id) AS links,
+	EXISTS (
+		SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocked_by_id
+		WHERE d.task_id = tasks.id AND b.deleted_at IS NULL AND NOT ` + statusInCategory("b", models.CategoryDone) + `
+	) AS blocked,
+	created_at, updated_at, custom_fields, deleted_at`
+
+// taskSortColumns maps the sort_by values accepted by GetTasks to SQL expressions.
+// Status sorts by the position in the workflow and priority by rank so that "desc" puts urgent tasks first
+var taskSortColumns = map[string]string{
+	"due_date":   "due_date",
+	"created_at": "created_at",
+	"status":     "(SELECT wf.position FROM workflow_statuses wf WHERE wf.workspace_id = tasks.workspace_id AND wf.key = tasks.status)",
+	"priority":   "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END",
+}
+
+// TaskRepository handles database operations for tasks.
+// Every method is scoped to a single workspace
 type TaskRepository struct {
 	db *sql.DB
 }
@@ -17,30 +43,109 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
+// CreateTask inserts a task together with its assignees from task.Users and
+// its labels from task.Labels in a single transaction. When task.SeriesID and
+// task.Recurrence are set, a new series starting at the task's due date is created with it
 func (r *TaskRepository) CreateTask(task *models.Task) error {
-	query := `
-		INSERT INTO tasks (id, title, icon_name, start_time, end_time, due_date, progress, status, comments, attachments, links, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
-	`
-	_, err := r.db.Exec(query, task.ID, task.Title, task.IconName, task.StartTime, task.EndTime,
-		task.DueDate, task.Progress, task.Status, task.Comments, task.Attachments, task.Links,
-		task.CreatedAt, task.UpdatedAt)
-	return err
+	if err := requireWorkspace(task.WorkspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	startsSeries := task.SeriesID != nil && task.Recurrence != nil
+	if startsSeries {
+		_, err = tx.Exec(`
+			INSERT INTO task_series (id, workspace_id, rrule, start_date, occurrences, active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, 1, TRUE, $5, $5)`,
+			*task.SeriesID, task.WorkspaceID, *task.Recurrence, task.DueDate, task.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create series: %w", err)
+		}
+	}
+
+	if err := insertTask(tx, task); err != nil {
+		return err
+	}
+
+	if startsSeries {
+		_, err = tx.Exec("UPDATE task_series SET current_task_id = $1 WHERE id = $2", task.ID, *task.SeriesID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (r *TaskRepository) GetTaskByID(id string) (*models.Task, error) {
+// insertTask writes a task row with its assignees, labels and subtasks inside tx
+// and records its initial effort
+func insertTask(tx *sql.Tx, task *models.Task) error {
 	query := `
-		SELECT id, title, icon_name, start_time, end_time, due_date, progress, status, comments, attachments, links, created_at, updated_at
-		FROM tasks WHERE id = $1
+		INSERT INTO tasks (id, workspace_id, project_id, sprint_id, milestone_id, series_id, title, icon_name, start_time, end_time, due_date,
+			progress, status, priority, estimate_hours, story_points, remaining_hours, created_at, updated_at, custom_fields)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
 	`
+	customFields, err := marshalCustomFields(task.CustomFields)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(query, task.ID, task.WorkspaceID, task.ProjectID, task.SprintID, task.MilestoneID, task.SeriesID, task.Title, task.IconName, task.StartTime, task.EndTime,
+		task.DueDate, task.Progress, task.Status, task.Priority, task.EstimateHours, task.StoryPoints, task.RemainingHours,
+		task.CreatedAt, task.UpdatedAt, customFields)
+	if err != nil {
+		return err
+	}
+
+	// Assignees watch the task
+	for _, user := range task.Users {
+		_, err = tx.Exec(
+			"INSERT INTO task_user_assignments (task_id, user_id, assigned_at) VALUES ($1, $2, $3)",
+			task.ID, user.ID, task.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to assign user %s: %w", user.ID, err)
+		}
+		if err := addWatcher(tx, task.WorkspaceID, task.ID, user.ID); err != nil {
+			return err
+		}
+	}
+
+	for _, label := range task.Labels {
+		_, err = tx.Exec("INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2)", task.ID, label.ID)
+		if err != nil {
+			return fmt.Errorf("failed to assign label %s: %w", label.ID, err)
+		}
+	}
+
+	// Subtasks must be listed parents first so that parent_subtask_id references resolve
+	for _, subTask := range task.SubTasks {
+		_, err = tx.Exec(`
+			INSERT INTO sub_tasks (id, workspace_id, task_id, parent_subtask_id, title, description, status, "order",
+				estimate_hours, story_points, remaining_hours, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+			subTask.ID, task.WorkspaceID, task.ID, subTask.ParentSubTaskID, subTask.Title, subTask.Description, subTask.Status,
+			subTask.Order, subTask.EstimateHours, subTask.StoryPoints, subTask.RemainingHours, subTask.CreatedAt, subTask.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to create subtask %s: %w", subTask.ID, err)
+		}
+	}
+
+	return recordTaskEffort(tx, task.WorkspaceID, task.ID)
+}
+
+func (r *TaskRepository) GetTaskByID(workspaceID string, id string) (*models.Task, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + taskSelectColumns + ` FROM tasks WHERE id = $1 AND workspace_id = $2 AND deleted_at IS NULL`
 	fmt.Printf("GetTaskByID query: %s with id: %s\n", query, id)
 
-	task := &models.Task{}
-	err := r.db.QueryRow(query, id).Scan(
-		&task.ID, &task.Title, &task.IconName, &task.StartTime, &task.EndTime,
-		&task.DueDate, &task.Progress, &task.Status, &task.Comments, &task.Attachments,
-		&task.Links, &task.CreatedAt, &task.UpdatedAt,
-	)
+	task, err := scanTask(r.db.QueryRow(query, id, workspaceID))
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -51,22 +156,50 @@ func (r *TaskRepository) GetTaskByID(id string) (*models.Task, error) {
 		return nil, err
 	}
 
-	subTasks, err := r.GetTaskSubTasks(id)
-	if err != nil {
-		fmt.Printf("GetTaskSubTasks error: %v\n", err)
-		return nil, err
+	if err := r.loadTaskRelations(task); err != nil {
+		return nil, fmt.Errorf("failed to load relations of task %s: %w", id, err)
 	}
-	task.SubTasks = subTasks
 
 	fmt.Printf("GetTaskByID success: found task with id: %s\n", id)
 	return task, nil
 }
 
-func (r *TaskRepository) UpdateTask(id string, updates *models.UpdateTaskRequest) error {
+func (r *TaskRepository) UpdateTask(workspaceID string, id string, updates *models.UpdateTaskRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
 	setParts := []string{}
 	args := []any{}
 	argIndex := 1
 
+	if updates.ProjectID != nil {
+		setParts = append(setParts, fmt.Sprintf("project_id = $%d", argIndex))
+		if *updates.ProjectID == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.ProjectID)
+		}
+		argIndex++
+	}
+	if updates.SprintID != nil {
+		setParts = append(setParts, fmt.Sprintf("sprint_id = $%d", argIndex))
+		if *updates.SprintID == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.SprintID)
+		}
+		argIndex++
+	}
+	if updates.MilestoneID != nil {
+		setParts = append(setParts, fmt.Sprintf("milestone_id = $%d", argIndex))
+		if *updates.MilestoneID == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.MilestoneID)
+		}
+		argIndex++
+	}
 	if updates.Title != nil {
 		setParts = append(setParts, fmt.Sprintf("title = $%d", argIndex))
 		args = append(args, *updates.Title)
@@ -77,6 +210,9 @@ func (r *TaskRepository) UpdateTask(id string, updates *models.UpdateTaskRequest
 		args = append(args, *updates.IconName)
 		argIndex++
 	}
+	if updates.ClearSchedule {
+		setParts = append(setParts, "start_time = NULL", "end_time = NULL")
+	}
 	if updates.StartTime != nil {
 		setParts = append(setParts, fmt.Sprintf("start_time = $%d", argIndex))
 		args = append(args, *updates.StartTime)
@@ -102,19 +238,34 @@ func (r *TaskRepository) UpdateTask(id string, updates *models.UpdateTaskRequest
 		args = append(args, *updates.Status)
 		argIndex++
 	}
-	if updates.Comments != nil {
-		setParts = append(setParts, fmt.Sprintf("comments = $%d", argIndex))
-		args = append(args, *updates.Comments)
+	if updates.Priority != nil {
+		setParts = append(setParts, fmt.Sprintf("priority = $%d", argIndex))
+		args = append(args, *updates.Priority)
+		argIndex++
+	}
+	if updates.EstimateHours != nil {
+		setParts = append(setParts, fmt.Sprintf("estimate_hours = $%d", argIndex))
+		args = append(args, *updates.EstimateHours)
 		argIndex++
 	}
-	if updates.Attachments != nil {
-		setParts = append(setParts, fmt.Sprintf("attachments = $%d", argIndex))
-		args = append(args, *updates.Attachments)
+	if updates.StoryPoints != nil {
+		setParts = append(setParts, fmt.Sprintf("story_points = $%d", argIndex))
+		args = append(args, *updates.StoryPoints)
 		argIndex++
 	}
-	if updates.Links != nil {
-		setParts = append(setParts, fmt.Sprintf("links = $%d", argIndex))
-		args = append(args, *updates.Links)
+	if updates.RemainingHours != nil {
+		setParts = append(setParts, fmt.Sprintf("remaining_hours = $%d", argIndex))
+		args = append(args, *updates.RemainingHours)
+		argIndex++
+	}
+	if updates.CustomFields != nil {
+		// Merge the patch into the stored values; null values remove their keys
+		customFields, err := marshalCustomFields(*updates.CustomFields)
+		if err != nil {
+			return err
+		}
+		setParts = append(setParts, fmt.Sprintf("custom_fields = jsonb_strip_nulls(custom_fields || $%d::jsonb)", argIndex))
+		args = append(args, customFields)
 		argIndex++
 	}
 
@@ -126,44 +277,218 @@ func (r *TaskRepository) UpdateTask(id string, updates *models.UpdateTaskRequest
 	args = append(args, time.Now())
 	argIndex++
 
-	args = append(args, id)
+	args = append(args, id, workspaceID)
+
+	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = $%d AND workspace_id = $%d AND deleted_at IS NULL",
+		strings.Join(setParts, ", "), argIndex, argIndex+1)
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return err
+	}
+
+	return recordTaskEffort(r.db, workspaceID, id)
+}
+
+// DeleteTask moves a task to the trash together with its subtasks, which share its deleted_at
+// so that RestoreTask can tell them from subtasks that were trashed on their own before.
+// It returns sql.ErrNoRows if the task does not exist or is already in the trash
+func (r *TaskRepository) DeleteTask(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	query := fmt.Sprintf("UPDATE tasks SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
-	_, err := r.db.Exec(query, args...)
-	return err
+	now := time.Now()
+	result, err := tx.Exec("UPDATE tasks SET deleted_at = $1 WHERE id = $2 AND workspace_id = $3 AND deleted_at IS NULL",
+		now, id, workspaceID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec("UPDATE sub_tasks SET deleted_at = $1 WHERE task_id = $2 AND deleted_at IS NULL", now, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete subtasks: %w", err)
+	}
+
+	return tx.Commit()
 }
 
-func (r *TaskRepository) DeleteTask(id string) error {
-	query := "DELETE FROM tasks WHERE id = $1"
-	_, err := r.db.Exec(query, id)
-	return err
+// RestoreTask takes a task out of the trash together with the subtasks that were deleted with it.
+// It returns sql.ErrNoRows if the task is not in the trash
+func (r *TaskRepository) RestoreTask(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deleted bool
+	err = tx.QueryRow("SELECT deleted_at IS NOT NULL FROM tasks WHERE id = $1 AND workspace_id = $2 FOR UPDATE",
+		id, workspaceID).Scan(&deleted)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec("UPDATE sub_tasks SET deleted_at = NULL WHERE task_id = $1 AND deleted_at = (SELECT deleted_at FROM tasks WHERE id = $1)", id)
+	if err != nil {
+		return fmt.Errorf("failed to restore subtasks: %w", err)
+	}
+
+	if _, err := tx.Exec("UPDATE tasks SET deleted_at = NULL WHERE id = $1", id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (r *TaskRepository) GetTasks(filters models.TaskFilters) ([]models.Task, error) {
-	query := "SELECT id, title, icon_name, start_time, end_time, due_date, progress, status, comments, attachments, links, created_at, updated_at FROM tasks"
-	args := []any{}
-	whereConditions := []string{}
-	argIndex := 1
+// GetDeletedTasks retrieves the tasks in the trash of the workspace, most recently deleted first.
+// Each task comes with the subtasks that were deleted with it
+func (r *TaskRepository) GetDeletedTasks(workspaceID string) ([]models.Task, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
 
-	if filters.Status != "" && filters.Status != "all" {
-		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
-		args = append(args, filters.Status)
-		argIndex++
+	query := "SELECT " + taskSelectColumns + " FROM tasks WHERE workspace_id = $1 AND deleted_at IS NOT NULL ORDER BY deleted_at DESC"
+	rows, err := r.db.Query(query, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := r.loadTaskRelations(task); err != nil {
+			return nil, err
+		}
+
+		tasks = append(tasks, *task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// PurgeDeletedTasks permanently removes up to limit tasks, across all workspaces, that were moved
+// to the trash before the given time, along with everything that belongs to them.
+// It returns the storage keys of their attachments, whose files the caller still has to delete,
+// and the number of tasks removed. Safe to run concurrently from several replicas
+func (r *TaskRepository) PurgeDeletedTasks(before time.Time, limit int) ([]string, int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`
+		SELECT id FROM tasks WHERE deleted_at < $1
+		ORDER BY deleted_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`, before, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	var placeholders []string
+	var args []any
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(args) == 0 {
+		return nil, 0, nil
+	}
+	ids := strings.Join(placeholders, ", ")
+
+	rows, err = tx.Query("SELECT storage_key FROM attachments WHERE task_id IN ("+ids+")", args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var storageKeys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+		storageKeys = append(storageKeys, key)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
 	}
 
-	if len(whereConditions) > 0 {
-		query += " WHERE " + strings.Join(whereConditions, " AND ")
+	// Subtasks, comments, attachments and the other task data are removed by ON DELETE CASCADE
+	if _, err := tx.Exec("DELETE FROM tasks WHERE id IN ("+ids+")", args...); err != nil {
+		return nil, 0, err
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+
+	return storageKeys, len(args), nil
+}
+
+func (r *TaskRepository) GetTasks(workspaceID string, filters models.TaskFilters) ([]models.Task, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	whereConditions, args := taskFilterConditions(workspaceID, filters)
+	argIndex := len(args) + 1
+
+	query := "SELECT " + taskSelectColumns + " FROM tasks"
+	query += " WHERE " + strings.Join(whereConditions, " AND ")
+
 	if filters.SortBy != "" {
-		sortColumn := "due_date"
+		sortColumn, ok := taskSortColumns[filters.SortBy]
+		if key, isCustom := strings.CutPrefix(filters.SortBy, models.CustomFieldPrefix); isCustom {
+			sortColumn = fmt.Sprintf("custom_fields->>$%d", argIndex)
+			if filters.SortNumeric {
+				sortColumn = "(" + sortColumn + ")::numeric"
+			}
+			args = append(args, key)
+			argIndex++
+		} else if !ok {
+			sortColumn = taskSortColumns["due_date"]
+		}
 		sortDirection := "ASC"
 
 		if filters.SortType == "desc" {
 			sortDirection = "DESC"
 		}
 
-		query += fmt.Sprintf(" ORDER BY %s %s", sortColumn, sortDirection)
+		query += fmt.Sprintf(" ORDER BY %s %s, created_at DESC", sortColumn, sortDirection)
 	} else {
 		query += " ORDER BY created_at DESC"
 	}
@@ -187,36 +512,197 @@ func (r *TaskRepository) GetTasks(filters models.TaskFilters) ([]models.Task, er
 
 	var tasks []models.Task
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID, &task.Title, &task.IconName, &task.StartTime, &task.EndTime,
-			&task.DueDate, &task.Progress, &task.Status, &task.Comments, &task.Attachments,
-			&task.Links, &task.CreatedAt, &task.UpdatedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		subTasks, err := r.GetTaskSubTasks(task.ID)
-		if err != nil {
+		if err := r.loadTaskRelations(task); err != nil {
 			return nil, err
 		}
-		task.SubTasks = subTasks
 
-		tasks = append(tasks, task)
+		tasks = append(tasks, *task)
 	}
 
 	return tasks, nil
 }
 
-func (r *TaskRepository) GetTaskSubTasks(taskID string) ([]models.SubTask, error) {
+// taskFilterConditions builds the WHERE conditions on the tasks table for filters, leaving out tasks in the trash.
+// Sorting and paging fields are ignored; the returned args start with workspaceID as $1
+func taskFilterConditions(workspaceID string, filters models.TaskFilters) ([]string, []any) {
+	args := []any{workspaceID}
+	whereConditions := []string{"workspace_id = $1", "deleted_at IS NULL"}
+	argIndex := 2
+
+	if filters.ProjectID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("project_id = $%d", argIndex))
+		args = append(args, filters.ProjectID)
+		argIndex++
+	}
+
+	if filters.SprintID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("sprint_id = $%d", argIndex))
+		args = append(args, filters.SprintID)
+		argIndex++
+	}
+
+	if filters.MilestoneID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("milestone_id = $%d", argIndex))
+		args = append(args, filters.MilestoneID)
+		argIndex++
+	}
+
+	// The built-in statuses match every status of their category
+	if category, ok := filters.Status.LegacyCategory(); ok {
+		whereConditions = append(whereConditions, fmt.Sprintf("%s = $%d", statusCategoryOf("tasks"), argIndex))
+		args = append(args, category)
+		argIndex++
+	} else if filters.Status != "" && filters.Status != "all" {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+		args = append(args, filters.Status)
+		argIndex++
+	}
+
+	if filters.Category != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("%s = $%d", statusCategoryOf("tasks"), argIndex))
+		args = append(args, filters.Category)
+		argIndex++
+	}
+
+	if filters.Priority != "" && filters.Priority != "all" {
+		whereConditions = append(whereConditions, fmt.Sprintf("priority = $%d", argIndex))
+		args = append(args, filters.Priority)
+		argIndex++
+	}
+
+	if filters.SeriesID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("series_id = $%d", argIndex))
+		args = append(args, filters.SeriesID)
+		argIndex++
+	}
+
+	if filters.UserID != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_user_assignments tua WHERE tua.task_id = tasks.id AND tua.user_id = $%d)", argIndex))
+		args = append(args, filters.UserID)
+		argIndex++
+	}
+
+	if names := normalizeLabelNames(filters.Labels); len(names) > 0 {
+		placeholders := make([]string, len(names))
+		for i, name := range names {
+			placeholders[i] = fmt.Sprintf("$%d", argIndex)
+			args = append(args, name)
+			argIndex++
+		}
+
+		matching := fmt.Sprintf(`SELECT COUNT(DISTINCT l.id) FROM task_labels tl
+			JOIN labels l ON l.id = tl.label_id
+			WHERE tl.task_id = tasks.id AND lower(l.name) IN (%s)`, strings.Join(placeholders, ", "))
+
+		if filters.LabelMatch == "all" {
+			whereConditions = append(whereConditions, fmt.Sprintf("(%s) = %d", matching, len(names)))
+		} else {
+			whereConditions = append(whereConditions, fmt.Sprintf("(%s) > 0", matching))
+		}
+	}
+
+	if filters.CustomFieldMatch != "" {
+		whereConditions = append(whereConditions, fmt.Sprintf("custom_fields @> $%d::jsonb", argIndex))
+		args = append(args, filters.CustomFieldMatch)
+		argIndex++
+	}
+
+	return whereConditions, args
+}
+
+// loadTaskRelations fills in the subtasks, assignees and labels of a task
+func (r *TaskRepository) loadTaskRelations(task *models.Task) error {
+	subTasks, err := r.GetTaskSubTasks(task.WorkspaceID, task.ID)
+	if err != nil {
+		return err
+	}
+	task.SubTasks = subTasks
+
+	users, err := r.GetTaskUsers(task.WorkspaceID, task.ID)
+	if err != nil {
+		return err
+	}
+	task.Users = users
+
+	labels, err := r.GetTaskLabels(task.WorkspaceID, task.ID)
+	if err != nil {
+		return err
+	}
+	task.Labels = labels
+
+	return nil
+}
+
+// normalizeLabelNames lower-cases and de-duplicates label names used as filters
+func normalizeLabelNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// scanTask reads a row selected with taskSelectColumns
+func scanTask(row rowScanner) (*models.Task, error) {
+	task := &models.Task{}
+	var customFields []byte
+	err := row.Scan(
+		&task.ID, &task.WorkspaceID, &task.ProjectID, &task.SprintID, &task.MilestoneID, &task.Title, &task.IconName, &task.StartTime, &task.EndTime,
+		&task.DueDate, &task.Progress, &task.Status, &task.StatusCategory, &task.Priority, &task.EstimateHours, &task.StoryPoints, &task.RemainingHours, &task.SeriesID, &task.Recurrence, &task.Comments, &task.Attachments,
+		&task.Links, &task.Blocked, &task.CreatedAt, &task.UpdatedAt, &customFields, &task.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(customFields, &task.CustomFields); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields of task %s: %w", task.ID, err)
+	}
+	if task.StartTime != nil && task.EndTime != nil {
+		duration := int64(task.EndTime.Sub(*task.StartTime) / time.Second)
+		task.DurationSeconds = &duration
+	}
+	return task, nil
+}
+
+// marshalCustomFields encodes custom field values for a JSONB column, storing no values as an empty object
+func marshalCustomFields(values models.CustomFieldValues) (string, error) {
+	if values == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (r *TaskRepository) GetTaskSubTasks(workspaceID string, taskID string) ([]models.SubTask, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	// Creation order lists every parent before its children. A task in the trash
+	// comes with the subtasks deleted with it, any other task with its live subtasks
 	query := `
-		SELECT id, task_id, title, description, status, created_at, updated_at 
+		SELECT ` + subTaskSelectColumns + `
 		FROM sub_tasks 
-		WHERE task_id = $1 
+		WHERE task_id = $1 AND workspace_id = $2
+			AND deleted_at IS NOT DISTINCT FROM (SELECT t.deleted_at FROM tasks t WHERE t.id = sub_tasks.task_id)
 		ORDER BY created_at ASC
 	`
-	rows, err := r.db.Query(query, taskID)
+	rows, err := r.db.Query(query, taskID, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -224,14 +710,120 @@ func (r *TaskRepository) GetTaskSubTasks(taskID string) ([]models.SubTask, error
 
 	var subTasks []models.SubTask
 	for rows.Next() {
-		var subTask models.SubTask
-		err := rows.Scan(&subTask.ID, &subTask.TaskID, &subTask.Title,
-			&subTask.Description, &subTask.Status, &subTask.CreatedAt, &subTask.UpdatedAt)
+		subTask, err := scanSubTask(rows)
 		if err != nil {
 			return nil, err
 		}
-		subTasks = append(subTasks, subTask)
+		subTasks = append(subTasks, *subTask)
 	}
 
 	return subTasks, nil
 }
+
+// GetTaskUsers retrieves the users assigned to a task in assignment order
+func (r *TaskRepository) GetTaskUsers(workspaceID string, taskID string) ([]models.User, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT u.id, u.name, u.src
+		FROM task_user_assignments tua
+		JOIN tasks t ON t.id = tua.task_id AND t.workspace_id = $2
+		JOIN users u ON u.id = tua.user_id
+		WHERE tua.task_id = $1
+		ORDER BY tua.assigned_at ASC, u.name ASC
+	`
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Src); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// AssignUser assigns a user to a task and makes them watch it, doing nothing if the assignment already exists
+func (r *TaskRepository) AssignUser(workspaceID string, taskID string, userID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO task_user_assignments (task_id, user_id, assigned_at)
+		SELECT id, $2, $3 FROM tasks WHERE id = $1 AND workspace_id = $4
+		ON CONFLICT (task_id, user_id) DO NOTHING`
+	if _, err := tx.Exec(query, taskID, userID, time.Now(), workspaceID); err != nil {
+		return err
+	}
+
+	// Assignees watch the task
+	if err := addWatcher(tx, workspaceID, taskID, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UnassignUser removes a user from a task.
+// It returns sql.ErrNoRows if the user was not assigned to the task
+func (r *TaskRepository) UnassignUser(workspaceID string, taskID string, userID string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`
+		DELETE FROM task_user_assignments
+		WHERE task_id = $1 AND user_id = $2
+		AND task_id IN (SELECT id FROM tasks WHERE workspace_id = $3)`, taskID, userID, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetTaskLabels retrieves the labels attached to a task ordered by name
+func (r *TaskRepository) GetTaskLabels(workspaceID string, taskID string) ([]models.Label, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT l.id, l.name, l.color
+		FROM task_labels tl
+		JOIN labels l ON l.id = tl.label_id AND l.workspace_id = $2
+		WHERE tl.task_id = $1
+		ORDER BY lower(l.name) ASC
+	`
+	rows, err := r.db.Query(query, taskID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
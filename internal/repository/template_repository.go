@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const templateSelectColumns = "id, workspace_id, name, title, icon_name, project_id, priority, due_in_days, custom_fields, subtasks, created_at, updated_at"
+
+// TemplateRepository handles database operations for task templates.
+// Subtask blueprints are stored as a JSON tree on the template row.
+// Every method is scoped to a single workspace
+type TemplateRepository struct {
+	db *sql.DB
+}
+
+// NewTemplateRepository creates a new instance of TemplateRepository
+func NewTemplateRepository(db *sql.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// CreateTemplate inserts a template together with its labels from template.Labels
+func (r *TemplateRepository) CreateTemplate(template *models.TaskTemplate) error {
+	if err := requireWorkspace(template.WorkspaceID); err != nil {
+		return err
+	}
+
+	customFields, subTasks, err := marshalTemplateDocuments(template)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO task_templates (id, workspace_id, name, title, icon_name, project_id, priority, due_in_days, custom_fields, subtasks, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		template.ID, template.WorkspaceID, template.Name, template.Title, template.IconName, template.ProjectID, template.Priority,
+		template.DueInDays, customFields, subTasks, template.CreatedAt, template.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := insertTemplateLabels(tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetTemplateByID retrieves a template with its labels
+func (r *TemplateRepository) GetTemplateByID(workspaceID string, id string) (*models.TaskTemplate, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	template, err := scanTemplate(r.db.QueryRow(
+		"SELECT "+templateSelectColumns+" FROM task_templates WHERE id = $1 AND workspace_id = $2", id, workspaceID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	template.Labels, err = r.getTemplateLabels(template.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// GetTemplates retrieves every template of the workspace ordered by name
+func (r *TemplateRepository) GetTemplates(workspaceID string) ([]models.TaskTemplate, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		"SELECT "+templateSelectColumns+" FROM task_templates WHERE workspace_id = $1 ORDER BY lower(name) ASC", workspaceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var templates []models.TaskTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range templates {
+		templates[i].Labels, err = r.getTemplateLabels(templates[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return templates, nil
+}
+
+// SaveTemplate overwrites a template and replaces its labels with template.Labels
+func (r *TemplateRepository) SaveTemplate(template *models.TaskTemplate) error {
+	if err := requireWorkspace(template.WorkspaceID); err != nil {
+		return err
+	}
+
+	customFields, subTasks, err := marshalTemplateDocuments(template)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE task_templates SET
+			name = $1, title = $2, icon_name = $3, project_id = $4, priority = $5, due_in_days = $6,
+			custom_fields = $7, subtasks = $8, updated_at = $9
+		WHERE id = $10 AND workspace_id = $11`,
+		template.Name, template.Title, template.IconName, template.ProjectID, template.Priority, template.DueInDays,
+		customFields, subTasks, template.UpdatedAt, template.ID, template.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec("DELETE FROM task_template_labels WHERE template_id = $1", template.ID); err != nil {
+		return err
+	}
+	if err := insertTemplateLabels(tx, template); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteTemplate removes a template. Tasks created from it are not affected
+func (r *TemplateRepository) DeleteTemplate(workspaceID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM task_templates WHERE id = $1 AND workspace_id = $2", id, workspaceID)
+	return err
+}
+
+func (r *TemplateRepository) getTemplateLabels(templateID string) ([]models.Label, error) {
+	rows, err := r.db.Query(`
+		SELECT l.id, l.name, l.color
+		FROM labels l
+		JOIN task_template_labels ttl ON ttl.label_id = l.id
+		WHERE ttl.template_id = $1
+		ORDER BY lower(l.name) ASC`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLabels(rows)
+}
+
+// insertTemplateLabels attaches template.Labels inside tx, skipping labels of other workspaces
+func insertTemplateLabels(tx *sql.Tx, template *models.TaskTemplate) error {
+	for _, label := range template.Labels {
+		_, err := tx.Exec(`
+			INSERT INTO task_template_labels (template_id, label_id)
+			SELECT $1, id FROM labels WHERE id = $2 AND workspace_id = $3`,
+			template.ID, label.ID, template.WorkspaceID)
+		if err != nil {
+			return fmt.Errorf("failed to assign label %s: %w", label.ID, err)
+		}
+	}
+	return nil
+}
+
+func marshalTemplateDocuments(template *models.TaskTemplate) (string, string, error) {
+	customFields, err := marshalCustomFields(template.CustomFields)
+	if err != nil {
+		return "", "", err
+	}
+
+	subTasks := template.SubTasks
+	if subTasks == nil {
+		subTasks = []models.TemplateSubTask{}
+	}
+	data, err := json.Marshal(subTasks)
+	if err != nil {
+		return "", "", err
+	}
+
+	return customFields, string(data), nil
+}
+
+func scanTemplate(row rowScanner) (*models.TaskTemplate, error) {
+	template := &models.TaskTemplate{}
+	var customFields, subTasks []byte
+	err := row.Scan(&template.ID, &template.WorkspaceID, &template.Name, &template.Title, &template.IconName, &template.ProjectID,
+		&template.Priority, &template.DueInDays, &customFields, &subTasks, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(customFields, &template.CustomFields); err != nil {
+		return nil, fmt.Errorf("failed to decode custom fields of template %s: %w", template.ID, err)
+	}
+	if err := json.Unmarshal(subTasks, &template.SubTasks); err != nil {
+		return nil, fmt.Errorf("failed to decode subtasks of template %s: %w", template.ID, err)
+	}
+	return template, nil
+}
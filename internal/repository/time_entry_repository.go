@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+const timeEntrySelectColumns = "id, workspace_id, user_id, task_id, subtask_id, started_at, ended_at, note, created_at, updated_at"
+
+// timeEntrySeconds is the part of an entry inside the range [$from, $to), with running entries
+// counting up to now. $from and $to are nullable, meaning an open bound
+const timeEntrySeconds = `EXTRACT(EPOCH FROM (
+	LEAST(COALESCE(e.ended_at, NOW()), COALESCE($3::timestamptz, 'infinity'))
+	- GREATEST(e.started_at, COALESCE($2::timestamptz, '-infinity'))))`
+
+const timeEntryInRange = `e.started_at < COALESCE($3::timestamptz, 'infinity')
+	AND COALESCE(e.ended_at, NOW()) > COALESCE($2::timestamptz, '-infinity')`
+
+// TimeEntryRepository handles database operations for time entries.
+// Every method is scoped to a single workspace
+type TimeEntryRepository struct {
+	db *sql.DB
+}
+
+// NewTimeEntryRepository creates a new instance of TimeEntryRepository
+func NewTimeEntryRepository(db *sql.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+// CreateTimeEntry inserts a time entry. Inserting a running entry returns ErrDuplicate
+// when the user already has one, which a partial unique index guarantees across replicas
+func (r *TimeEntryRepository) CreateTimeEntry(entry *models.TimeEntry) error {
+	if err := requireWorkspace(entry.WorkspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO time_entries (id, workspace_id, user_id, task_id, subtask_id, started_at, ended_at, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		entry.ID, entry.WorkspaceID, entry.UserID, entry.TaskID, entry.SubTaskID, entry.StartedAt, entry.EndedAt, entry.Note,
+		entry.CreatedAt, entry.UpdatedAt)
+	if isUniqueViolation(err) {
+		return ErrDuplicate
+	}
+	return err
+}
+
+// GetTimeEntryByID retrieves a time entry of a task
+func (r *TimeEntryRepository) GetTimeEntryByID(workspaceID string, taskID string, id string) (*models.TimeEntry, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	return scanTimeEntry(r.db.QueryRow(
+		"SELECT "+timeEntrySelectColumns+" FROM time_entries WHERE id = $1 AND task_id = $2 AND workspace_id = $3",
+		id, taskID, workspaceID,
+	))
+}
+
+// GetRunningTimeEntry retrieves the running timer of a user in the workspace
+func (r *TimeEntryRepository) GetRunningTimeEntry(workspaceID string, userID string) (*models.TimeEntry, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	return scanTimeEntry(r.db.QueryRow(
+		"SELECT "+timeEntrySelectColumns+" FROM time_entries WHERE user_id = $1 AND workspace_id = $2 AND ended_at IS NULL",
+		userID, workspaceID,
+	))
+}
+
+// StopTimer ends the user's running timer on a task and returns its ID.
+// It returns sql.ErrNoRows if no timer of the user is running on the task
+func (r *TimeEntryRepository) StopTimer(workspaceID string, taskID string, userID string, endedAt time.Time) (string, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return "", err
+	}
+
+	var id string
+	err := r.db.QueryRow(`
+		UPDATE time_entries SET ended_at = GREATEST($1, started_at), updated_at = $1
+		WHERE user_id = $2 AND task_id = $3 AND workspace_id = $4 AND ended_at IS NULL
+		RETURNING id`, endedAt, userID, taskID, workspaceID).Scan(&id)
+	return id, err
+}
+
+// GetTimeEntries lists the entries of a task overlapping the range, newest first
+func (r *TimeEntryRepository) GetTimeEntries(workspaceID string, taskID string, userID string, from *time.Time, to *time.Time) ([]models.TimeEntry, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + timeEntrySelectColumns + ` FROM time_entries e
+		WHERE e.workspace_id = $1 AND ` + timeEntryInRange + ` AND e.task_id = $4`
+	args := []any{workspaceID, from, to, taskID}
+	if userID != "" {
+		query += " AND e.user_id = $5"
+		args = append(args, userID)
+	}
+	query += " ORDER BY e.started_at DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []models.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// UpdateTimeEntry updates an existing time entry with the provided changes
+func (r *TimeEntryRepository) UpdateTimeEntry(workspaceID string, taskID string, id string, updates *models.UpdateTimeEntryRequest) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.SubTaskID != nil {
+		setParts = append(setParts, fmt.Sprintf("subtask_id = $%d", argIndex))
+		if *updates.SubTaskID == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.SubTaskID)
+		}
+		argIndex++
+	}
+	if updates.StartedAt != nil {
+		setParts = append(setParts, fmt.Sprintf("started_at = $%d", argIndex))
+		args = append(args, *updates.StartedAt)
+		argIndex++
+	}
+	if updates.EndedAt != nil {
+		setParts = append(setParts, fmt.Sprintf("ended_at = $%d", argIndex))
+		args = append(args, *updates.EndedAt)
+		argIndex++
+	}
+	if updates.Note != nil {
+		setParts = append(setParts, fmt.Sprintf("note = $%d", argIndex))
+		if *updates.Note == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *updates.Note)
+		}
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id, taskID, workspaceID)
+
+	query := fmt.Sprintf("UPDATE time_entries SET %s WHERE id = $%d AND task_id = $%d AND workspace_id = $%d",
+		strings.Join(setParts, ", "), argIndex, argIndex+1, argIndex+2)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteTimeEntry removes a time entry of a task
+func (r *TimeEntryRepository) DeleteTimeEntry(workspaceID string, taskID string, id string) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	_, err := r.db.Exec("DELETE FROM time_entries WHERE id = $1 AND task_id = $2 AND workspace_id = $3", id, taskID, workspaceID)
+	return err
+}
+
+// GetTaskTotals sums the time spent on a task within the range per user, largest first
+func (r *TimeEntryRepository) GetTaskTotals(workspaceID string, taskID string, from *time.Time, to *time.Time) ([]models.UserTime, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT e.user_id, u.name, SUM(`+timeEntrySeconds+`)::bigint AS seconds
+		FROM time_entries e
+		JOIN users u ON u.id = e.user_id
+		WHERE e.workspace_id = $1 AND `+timeEntryInRange+` AND e.task_id = $4
+		GROUP BY e.user_id, u.name
+		ORDER BY seconds DESC`, workspaceID, from, to, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserTime{}
+	for rows.Next() {
+		var item models.UserTime
+		if err := rows.Scan(&item.UserID, &item.Name, &item.Seconds); err != nil {
+			return nil, err
+		}
+		users = append(users, item)
+	}
+
+	return users, rows.Err()
+}
+
+// GetUserTotals sums the time a user spent within the range per task, largest first
+func (r *TimeEntryRepository) GetUserTotals(workspaceID string, userID string, from *time.Time, to *time.Time) ([]models.TaskTime, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT e.task_id, t.title, SUM(`+timeEntrySeconds+`)::bigint AS seconds
+		FROM time_entries e
+		JOIN tasks t ON t.id = e.task_id
+		WHERE e.workspace_id = $1 AND `+timeEntryInRange+` AND e.user_id = $4
+		GROUP BY e.task_id, t.title
+		ORDER BY seconds DESC`, workspaceID, from, to, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []models.TaskTime{}
+	for rows.Next() {
+		var item models.TaskTime
+		if err := rows.Scan(&item.TaskID, &item.Title, &item.Seconds); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, item)
+	}
+
+	return tasks, rows.Err()
+}
+
+func scanTimeEntry(row rowScanner) (*models.TimeEntry, error) {
+	entry := &models.TimeEntry{}
+	err := row.Scan(&entry.ID, &entry.WorkspaceID, &entry.UserID, &entry.TaskID, &entry.SubTaskID, &entry.StartedAt, &entry.EndedAt,
+		&entry.Note, &entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	if entry.EndedAt != nil {
+		end = *entry.EndedAt
+	} else {
+		entry.Running = true
+	}
+	entry.DurationSeconds = int64(max(end.Sub(entry.StartedAt), 0) / time.Second)
+	return entry, nil
+}
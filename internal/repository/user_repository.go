@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// UserRepository handles database operations for users
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new instance of UserRepository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// CreateUser inserts a new user into the database
+func (r *UserRepository) CreateUser(user *models.User) error {
+	query := `
+		INSERT INTO users (id, name, src, email, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)`
+
+	_, err := r.db.Exec(query, user.ID, user.Name, user.Src, user.Email, time.Now())
+	return err
+}
+
+// GetUserByID retrieves a user by its ID
+func (r *UserRepository) GetUserByID(id string) (*models.User, error) {
+	query := `SELECT id, name, src, email FROM users WHERE id = $1`
+
+	user := &models.User{}
+	err := r.db.QueryRow(query, id).Scan(&user.ID, &user.Name, &user.Src, &user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetUsers retrieves all users ordered by name
+func (r *UserRepository) GetUsers() ([]models.User, error) {
+	query := `SELECT id, name, src, email FROM users ORDER BY name ASC, created_at ASC`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Src, &user.Email); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// UpdateUser updates an existing user with the provided changes
+func (r *UserRepository) UpdateUser(id string, updates *models.UpdateUserRequest) error {
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+	if updates.Src != nil {
+		setParts = append(setParts, fmt.Sprintf("src = $%d", argIndex))
+		args = append(args, *updates.Src)
+		argIndex++
+	}
+	if updates.Email != nil {
+		setParts = append(setParts, fmt.Sprintf("email = $%d", argIndex))
+		args = append(args, *updates.Email)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE users SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteUser removes a user from the database
+func (r *UserRepository) DeleteUser(id string) error {
+	query := "DELETE FROM users WHERE id = $1"
+	_, err := r.db.Exec(query, id)
+	return err
+}
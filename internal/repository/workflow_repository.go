@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// statusInCategory returns an SQL condition that holds when the status of the tasks or sub_tasks
+// row aliased by alias belongs to category in the workflow of the row's workspace
+func statusInCategory(alias string, category models.StatusCategory) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM workflow_statuses wf
+		WHERE wf.workspace_id = %[1]s.workspace_id AND wf.key = %[1]s.status AND wf.category = '%[2]s')`, alias, category)
+}
+
+// statusCategoryOf returns an SQL expression selecting the category of the status of the row aliased by alias
+func statusCategoryOf(alias string) string {
+	return fmt.Sprintf(`(
+		SELECT wf.category FROM workflow_statuses wf
+		WHERE wf.workspace_id = %[1]s.workspace_id AND wf.key = %[1]s.status)`, alias)
+}
+
+// WorkflowRepository handles database operations for the workflow of a workspace:
+// its statuses and the transitions allowed between them.
+// Every method is scoped to a single workspace
+type WorkflowRepository struct {
+	db *sql.DB
+}
+
+// NewWorkflowRepository creates a new instance of WorkflowRepository
+func NewWorkflowRepository(db *sql.DB) *WorkflowRepository {
+	return &WorkflowRepository{db: db}
+}
+
+// GetWorkflow retrieves the statuses of the workspace in board order and its transitions
+func (r *WorkflowRepository) GetWorkflow(workspaceID string) (*models.Workflow, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	statuses, err := getWorkflowStatuses(r.db, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT from_status, to_status FROM workflow_transitions
+		WHERE workspace_id = $1 ORDER BY from_status, to_status`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workflow := &models.Workflow{Statuses: statuses, Transitions: []models.WorkflowTransition{}}
+	for rows.Next() {
+		var transition models.WorkflowTransition
+		if err := rows.Scan(&transition.From, &transition.To); err != nil {
+			return nil, err
+		}
+		workflow.Transitions = append(workflow.Transitions, transition)
+	}
+
+	return workflow, rows.Err()
+}
+
+// GetStatusUsage counts the tasks and subtasks of the workspace per status
+func (r *WorkflowRepository) GetStatusUsage(workspaceID string) (map[models.TaskStatus]int, error) {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(`
+		SELECT status, COUNT(*) FROM (
+			SELECT status FROM tasks WHERE workspace_id = $1
+			UNION ALL
+			SELECT status FROM sub_tasks WHERE workspace_id = $1
+		) used
+		GROUP BY status`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make(map[models.TaskStatus]int)
+	for rows.Next() {
+		var status models.TaskStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		usage[status] = count
+	}
+
+	return usage, rows.Err()
+}
+
+// ReplaceWorkflow stores statuses, in board order, and transitions as the workflow of the workspace
+// in a single transaction. Statuses that are not listed are removed; it returns ErrInUse if any of
+// them is still used by a task or subtask
+func (r *WorkflowRepository) ReplaceWorkflow(workspaceID string, statuses []models.WorkflowStatusRequest, transitions []models.WorkflowTransition) error {
+	if err := requireWorkspace(workspaceID); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Concurrent replacements of the same workflow are applied one after the other
+	if _, err := tx.Exec("SELECT 1 FROM workspaces WHERE id = $1 FOR UPDATE", workspaceID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	keys := make([]string, 0, len(statuses))
+	args := []any{workspaceID}
+	for i, status := range statuses {
+		_, err := tx.Exec(`
+			INSERT INTO workflow_statuses (workspace_id, key, name, category, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6)
+			ON CONFLICT (workspace_id, key) DO UPDATE SET
+				name = EXCLUDED.name, category = EXCLUDED.category, position = EXCLUDED.position, updated_at = EXCLUDED.updated_at`,
+			workspaceID, status.Key, status.Name, status.Category, i, now)
+		if err != nil {
+			return fmt.Errorf("failed to save status %s: %w", status.Key, err)
+		}
+		args = append(args, status.Key)
+		keys = append(keys, fmt.Sprintf("$%d", len(args)))
+	}
+
+	if _, err := tx.Exec("DELETE FROM workflow_transitions WHERE workspace_id = $1", workspaceID); err != nil {
+		return fmt.Errorf("failed to clear transitions: %w", err)
+	}
+
+	_, err = tx.Exec("DELETE FROM workflow_statuses WHERE workspace_id = $1 AND key NOT IN ("+strings.Join(keys, ", ")+")", args...)
+	if isForeignKeyViolation(err) {
+		return ErrInUse
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove statuses: %w", err)
+	}
+
+	for _, transition := range transitions {
+		_, err := tx.Exec(`
+			INSERT INTO workflow_transitions (workspace_id, from_status, to_status) VALUES ($1, $2, $3)
+			ON CONFLICT DO NOTHING`,
+			workspaceID, transition.From, transition.To)
+		if err != nil {
+			return fmt.Errorf("failed to save transition %s -> %s: %w", transition.From, transition.To, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// seedWorkflow gives a new workspace the default workflow
+func seedWorkflow(db execer, workspaceID string, now time.Time) error {
+	for i, status := range models.DefaultWorkflow() {
+		_, err := db.Exec(`
+			INSERT INTO workflow_statuses (workspace_id, key, name, category, position, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $6)`,
+			workspaceID, status.Key, status.Name, status.Category, i, now)
+		if err != nil {
+			return fmt.Errorf("failed to create status %s: %w", status.Key, err)
+		}
+	}
+	return nil
+}
+
+// getWorkflowStatuses retrieves the statuses of the workspace in board order
+func getWorkflowStatuses(db *sql.DB, workspaceID string) ([]models.WorkflowStatus, error) {
+	rows, err := db.Query(`
+		SELECT key, name, category, position, created_at, updated_at FROM workflow_statuses
+		WHERE workspace_id = $1 ORDER BY position, key`, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []models.WorkflowStatus{}
+	for rows.Next() {
+		var status models.WorkflowStatus
+		err := rows.Scan(&status.Key, &status.Name, &status.Category, &status.Position, &status.CreatedAt, &status.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, rows.Err()
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrWorkspaceRequired is returned by workspace-scoped repositories when called without a workspace ID.
+// Every query on tenant data must be filtered by workspace, so an empty ID is a programming error
+var ErrWorkspaceRequired = errors.New("workspace id is required")
+
+func requireWorkspace(workspaceID string) error {
+	if workspaceID == "" {
+		return ErrWorkspaceRequired
+	}
+	return nil
+}
+
+// taskInWorkspace returns an SQL condition that holds when the task referenced by the task_id of the
+// row aliased by alias belongs to the workspace bound to parameter $param. Comments, attachments and
+// links have no workspace_id of their own and are scoped through their task
+func taskInWorkspace(alias string, param int) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM tasks wt
+		WHERE wt.id = %s.task_id AND wt.workspace_id = $%d)`, alias, param)
+}
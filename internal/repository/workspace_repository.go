@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+)
+
+// WorkspaceRepository handles database operations for workspaces
+type WorkspaceRepository struct {
+	db *sql.DB
+}
+
+// NewWorkspaceRepository creates a new instance of WorkspaceRepository
+func NewWorkspaceRepository(db *sql.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// CreateWorkspace inserts a new workspace together with the default workflow
+func (r *WorkspaceRepository) CreateWorkspace(workspace *models.Workspace) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO workspaces (id, name, created_at, updated_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err = tx.Exec(query, workspace.ID, workspace.Name, workspace.CreatedAt, workspace.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	if err := seedWorkflow(tx, workspace.ID, workspace.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetWorkspaceByID retrieves a workspace by its ID
+func (r *WorkspaceRepository) GetWorkspaceByID(id string) (*models.Workspace, error) {
+	workspace := &models.Workspace{}
+	err := r.db.QueryRow(
+		"SELECT id, name, created_at, updated_at FROM workspaces WHERE id = $1", id,
+	).Scan(&workspace.ID, &workspace.Name, &workspace.CreatedAt, &workspace.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// GetWorkspaces retrieves all workspaces ordered by name
+func (r *WorkspaceRepository) GetWorkspaces() ([]models.Workspace, error) {
+	rows, err := r.db.Query("SELECT id, name, created_at, updated_at FROM workspaces ORDER BY lower(name) ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []models.Workspace
+	for rows.Next() {
+		var workspace models.Workspace
+		if err := rows.Scan(&workspace.ID, &workspace.Name, &workspace.CreatedAt, &workspace.UpdatedAt); err != nil {
+			return nil, err
+		}
+		workspaces = append(workspaces, workspace)
+	}
+
+	return workspaces, rows.Err()
+}
+
+// UpdateWorkspace updates an existing workspace with the provided changes
+func (r *WorkspaceRepository) UpdateWorkspace(id string, updates *models.UpdateWorkspaceRequest) error {
+	setParts := []string{}
+	args := []any{}
+	argIndex := 1
+
+	if updates.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", argIndex))
+		args = append(args, *updates.Name)
+		argIndex++
+	}
+
+	if len(setParts) == 0 {
+		return fmt.Errorf("no fields to update")
+	}
+
+	setParts = append(setParts, fmt.Sprintf("updated_at = $%d", argIndex))
+	args = append(args, time.Now())
+	argIndex++
+
+	args = append(args, id)
+
+	query := fmt.Sprintf("UPDATE workspaces SET %s WHERE id = $%d", strings.Join(setParts, ", "), argIndex)
+	_, err := r.db.Exec(query, args...)
+	return err
+}
+
+// DeleteWorkspace removes a workspace together with its projects and labels
+func (r *WorkspaceRepository) DeleteWorkspace(id string) error {
+	_, err := r.db.Exec("DELETE FROM workspaces WHERE id = $1", id)
+	return err
+}
+
+// CountWorkspaceTasks returns the number of tasks stored in a workspace, including those in the trash
+func (r *WorkspaceRepository) CountWorkspaceTasks(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM tasks WHERE workspace_id = $1", id).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// maxActivityLimit caps how many audit log entries one request returns
+const maxActivityLimit = 200
+
+// ActivityService handles business logic for the audit log of tasks and subtasks
+type ActivityService struct {
+	activityRepo *repository.ActivityRepository
+}
+
+// NewActivityService creates a new instance of ActivityService
+func NewActivityService(activityRepo *repository.ActivityRepository) *ActivityService {
+	return &ActivityService{activityRepo: activityRepo}
+}
+
+// GetTaskActivity retrieves the audit log of a task and its subtasks, newest first
+func (s *ActivityService) GetTaskActivity(workspaceID string, taskID string, filters models.ActivityFilters) ([]models.Activity, error) {
+	filters.TaskID = taskID
+	return s.GetActivities(workspaceID, filters)
+}
+
+// GetActivities retrieves the audit log entries of the workspace matching filters, newest first
+func (s *ActivityService) GetActivities(workspaceID string, filters models.ActivityFilters) ([]models.Activity, error) {
+	switch filters.Entity {
+	case "", models.EntityTask, models.EntitySubTask:
+	default:
+		return nil, fmt.Errorf("%w: unknown entity %q", ErrInvalidInput, filters.Entity)
+	}
+	switch filters.Action {
+	case "", models.ActionCreated, models.ActionUpdated, models.ActionDeleted, models.ActionRestored, models.ActionReordered:
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidInput, filters.Action)
+	}
+	if filters.Limit < 0 || filters.Limit > maxActivityLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxActivityLimit)
+	}
+	if filters.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+
+	from, to, err := parseTimeRange(filters.From, filters.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.activityRepo.GetActivities(workspaceID, filters, from, to)
+}
+
+// recordActivity appends an entry to the audit log. The change it describes is already stored,
+// so a failure is logged rather than returned
+func recordActivity(activityRepo *repository.ActivityRepository, activity *models.Activity) {
+	if err := activityRepo.RecordActivity(activity); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// auditField is one field of a task or subtask as it appears in the audit log
+type auditField struct {
+	name  string
+	value any
+}
+
+// taskAuditFields lists the audited fields of a task; unset optional fields are nil
+func taskAuditFields(task *models.Task) []auditField {
+	fields := []auditField{
+		{"project_id", deref(task.ProjectID)},
+		{"sprint_id", deref(task.SprintID)},
+		{"milestone_id", deref(task.MilestoneID)},
+		{"title", task.Title},
+		{"icon_name", task.IconName},
+		{"start_time", deref(task.StartTime)},
+		{"end_time", deref(task.EndTime)},
+		{"due_date", task.DueDate},
+		{"progress", task.Progress},
+		{"status", task.Status},
+		{"priority", task.Priority},
+		{"estimate_hours", deref(task.EstimateHours)},
+		{"story_points", deref(task.StoryPoints)},
+		{"remaining_hours", deref(task.RemainingHours)},
+		{"recurrence", deref(task.Recurrence)},
+		{"assignees", auditIDs(task.Users, func(user models.User) string { return user.ID })},
+		{"labels", auditIDs(task.Labels, func(label models.Label) string { return label.ID })},
+	}
+
+	keys := make([]string, 0, len(task.CustomFields))
+	for key := range task.CustomFields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		fields = append(fields, auditField{models.CustomFieldPrefix + key, task.CustomFields[key]})
+	}
+
+	return fields
+}
+
+// subTaskAuditFields lists the audited fields of a subtask; unset optional fields are nil
+func subTaskAuditFields(subTask *models.SubTask) []auditField {
+	return []auditField{
+		{"parent_subtask_id", deref(subTask.ParentSubTaskID)},
+		{"title", subTask.Title},
+		{"description", deref(subTask.Description)},
+		{"status", subTask.Status},
+		{"order", subTask.Order},
+		{"estimate_hours", deref(subTask.EstimateHours)},
+		{"story_points", deref(subTask.StoryPoints)},
+		{"remaining_hours", deref(subTask.RemainingHours)},
+	}
+}
+
+// diffAuditFields returns the fields whose values differ between before and after, in the order of after
+// followed by fields only before has. A nil before describes a newly created item
+func diffAuditFields(before []auditField, after []auditField) []models.FieldChange {
+	old := make(map[string]any, len(before))
+	for _, field := range before {
+		old[field.name] = field.value
+	}
+
+	changes := []models.FieldChange{}
+	for _, field := range after {
+		if !sameAuditValue(old[field.name], field.value) {
+			changes = append(changes, models.FieldChange{Field: field.name, Old: old[field.name], New: field.value})
+		}
+		delete(old, field.name)
+	}
+	for _, field := range before {
+		if value, removed := old[field.name]; removed && value != nil {
+			changes = append(changes, models.FieldChange{Field: field.name, Old: value})
+		}
+	}
+
+	return changes
+}
+
+// sameAuditValue compares two field values by their JSON encoding, which is how the audit log stores them
+func sameAuditValue(a any, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
+
+// auditIDs lists the IDs of items sorted, so that the order they were added in is not a change,
+// or nil when there are none
+func auditIDs[T any](items []T, id func(T) string) any {
+	if len(items) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, id(item))
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// deref returns the value value points to, or nil for a nil pointer
+func deref[T any](value *T) any {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
 
 	"github.com/Sasha125588/event_app/internal/config"
 	"github.com/Sasha125588/event_app/internal/env"
+	"github.com/Sasha125588/event_app/internal/mail"
 	"github.com/Sasha125588/event_app/internal/repository"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,9 +16,31 @@ import (
 )
 
 type App struct {
-	Router      *gin.Engine
-	DB          *sql.DB
-	TaskService *TaskService
+	Router              *gin.Engine
+	DB                  *sql.DB
+	TaskService         *TaskService
+	UserService         *UserService
+	CommentService      *CommentService
+	AttachmentService   *AttachmentService
+	LinkService         *LinkService
+	LabelService        *LabelService
+	ProjectService      *ProjectService
+	WorkspaceService    *WorkspaceService
+	DependencyService   *DependencyService
+	CustomFieldService  *CustomFieldService
+	TemplateService     *TemplateService
+	TimeEntryService    *TimeEntryService
+	ReportService       *ReportService
+	SprintService       *SprintService
+	MilestoneService    *MilestoneService
+	WorkflowService     *WorkflowService
+	ActivityService     *ActivityService
+	RevisionService     *RevisionService
+	NotificationService *NotificationService
+	ReminderService     *ReminderService
+	EmailService        *EmailService
+
+	stopWorkers context.CancelFunc
 }
 
 func NewApp() (*App, error) {
@@ -34,17 +58,85 @@ func NewApp() (*App, error) {
 		return nil, err
 	}
 
+	storageConfig := config.NewStorageConfig()
+	fileStore, err := storageConfig.OpenStorage()
+	if err != nil {
+		return nil, err
+	}
+
+	emailConfig := config.NewEmailConfig()
+	mailSender, err := emailConfig.OpenSender()
+	if err != nil {
+		return nil, err
+	}
+	mailTemplates, err := mail.LoadTemplates()
+	if err != nil {
+		return nil, err
+	}
+
 	taskRepo := repository.NewTaskRepository(db)
 	subTaskRepo := repository.NewSubTaskRepository(db)
-
-	taskService := NewTaskService(taskRepo, subTaskRepo)
+	userRepo := repository.NewUserRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	linkRepo := repository.NewLinkRepository(db)
+	labelRepo := repository.NewLabelRepository(db)
+	projectRepo := repository.NewProjectRepository(db)
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	dependencyRepo := repository.NewDependencyRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+	customFieldRepo := repository.NewCustomFieldRepository(db)
+	templateRepo := repository.NewTemplateRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	reportRepo := repository.NewReportRepository(db)
+	sprintRepo := repository.NewSprintRepository(db)
+	milestoneRepo := repository.NewMilestoneRepository(db)
+	workflowRepo := repository.NewWorkflowRepository(db)
+	activityRepo := repository.NewActivityRepository(db)
+	revisionRepo := repository.NewRevisionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	emailRepo := repository.NewEmailRepository(db)
+
+	taskConfig := config.NewTaskConfig()
+	taskService := NewTaskService(taskRepo, subTaskRepo, userRepo, labelRepo, projectRepo, attachmentRepo, dependencyRepo, seriesRepo, customFieldRepo, sprintRepo, milestoneRepo, workflowRepo, activityRepo, revisionRepo, notificationRepo, fileStore,
+		TaskOptions{RejectBlockedCompletion: taskConfig.RejectBlockedCompletion})
+	userService := NewUserService(userRepo)
+	commentService := NewCommentService(commentRepo, taskRepo, userRepo, notificationRepo)
+	attachmentService := NewAttachmentService(attachmentRepo, taskRepo, userRepo, fileStore,
+		storageConfig.MaxFileSize, storageConfig.MaxTaskSize)
+	linkService := NewLinkService(linkRepo, taskRepo)
+	labelService := NewLabelService(labelRepo, taskRepo, activityRepo, revisionRepo, notificationRepo)
+	projectService := NewProjectService(projectRepo, taskRepo, customFieldRepo)
+	workspaceService := NewWorkspaceService(workspaceRepo)
+	dependencyService := NewDependencyService(dependencyRepo, taskRepo)
+	customFieldService := NewCustomFieldService(customFieldRepo)
+	templateService := NewTemplateService(templateRepo, taskRepo, userRepo, labelRepo, projectRepo, customFieldRepo, workflowRepo, activityRepo, revisionRepo, notificationRepo)
+	timeEntryService := NewTimeEntryService(timeEntryRepo, taskRepo, subTaskRepo, userRepo)
+	reportService := NewReportService(reportRepo, customFieldRepo)
+	sprintService := NewSprintService(sprintRepo, projectRepo, taskRepo, customFieldRepo, activityRepo, revisionRepo, notificationRepo)
+	milestoneService := NewMilestoneService(milestoneRepo, projectRepo, taskRepo, customFieldRepo)
+	workflowService := NewWorkflowService(workflowRepo)
+	activityService := NewActivityService(activityRepo)
+	revisionService := NewRevisionService(revisionRepo, taskService)
+	notificationService := NewNotificationService(notificationRepo, taskRepo, userRepo)
+	reminderService := NewReminderService(reminderRepo, notificationRepo,
+		ReminderOptions{Offsets: taskConfig.ReminderOffsets, Overdue: taskConfig.OverdueReminders})
+	emailService := NewEmailService(emailRepo, notificationRepo, userRepo, mailSender, mailTemplates,
+		EmailOptions{AppURL: emailConfig.AppURL, DigestHour: emailConfig.DigestHour, MaxAttempts: emailConfig.MaxAttempts})
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	go taskService.RunRecurrence(workerCtx, taskConfig.RecurrenceInterval)
+	go taskService.RunPurge(workerCtx, taskConfig.PurgeInterval, taskConfig.TrashRetention)
+	go reminderService.RunReminders(workerCtx, taskConfig.ReminderInterval)
+	go emailService.RunDelivery(workerCtx, emailConfig.DeliveryInterval)
 
 	router := gin.Default()
 
 	corsConfig := cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "https://task-hub-ruby.vercel.app", "https://task-hub-ruby.vercel.app/*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-Workspace-ID", "X-User-ID"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * 60 * 60, // 12 hours
@@ -57,9 +149,30 @@ func NewApp() (*App, error) {
 	})
 
 	app := &App{
-		Router:      router,
-		DB:          db,
-		TaskService: taskService,
+		Router:              router,
+		DB:                  db,
+		TaskService:         taskService,
+		UserService:         userService,
+		CommentService:      commentService,
+		AttachmentService:   attachmentService,
+		LinkService:         linkService,
+		LabelService:        labelService,
+		ProjectService:      projectService,
+		WorkspaceService:    workspaceService,
+		DependencyService:   dependencyService,
+		CustomFieldService:  customFieldService,
+		TemplateService:     templateService,
+		TimeEntryService:    timeEntryService,
+		ReportService:       reportService,
+		SprintService:       sprintService,
+		MilestoneService:    milestoneService,
+		WorkflowService:     workflowService,
+		ActivityService:     activityService,
+		RevisionService:     revisionService,
+		NotificationService: notificationService,
+		ReminderService:     reminderService,
+		EmailService:        emailService,
+		stopWorkers:         stopWorkers,
 	}
 
 	return app, nil
@@ -72,6 +185,9 @@ func (a *App) Run() {
 }
 
 func (a *App) Close() {
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+	}
 	if a.DB != nil {
 		a.DB.Close()
 	}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+	"github.com/Sasha125588/event_app/internal/storage"
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+)
+
+const maxFilenameLength = 255
+
+// AttachmentService handles uploading, downloading and deleting task attachments
+type AttachmentService struct {
+	attachmentRepo *repository.AttachmentRepository
+	taskRepo       *repository.TaskRepository
+	userRepo       *repository.UserRepository
+	store          storage.Storage
+	maxFileSize    int64
+	maxTaskSize    int64
+}
+
+// NewAttachmentService creates a new instance of AttachmentService.
+// maxFileSize limits a single upload and maxTaskSize limits the total size of a task's attachments
+func NewAttachmentService(attachmentRepo *repository.AttachmentRepository, taskRepo *repository.TaskRepository, userRepo *repository.UserRepository, store storage.Storage, maxFileSize int64, maxTaskSize int64) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		userRepo:       userRepo,
+		store:          store,
+		maxFileSize:    maxFileSize,
+		maxTaskSize:    maxTaskSize,
+	}
+}
+
+// MaxFileSize returns the largest accepted upload in bytes
+func (s *AttachmentService) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
+// UploadAttachment stores the content of file and records its metadata on the task.
+// The MIME type is detected from the content rather than trusted from the client
+func (s *AttachmentService) UploadAttachment(ctx context.Context, workspaceID string, taskID string, uploadedBy *string, filename string, file io.ReadSeeker, size int64) (*models.Attachment, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if size > s.maxFileSize {
+		return nil, fmt.Errorf("%w: file exceeds the %d byte limit", ErrTooLarge, s.maxFileSize)
+	}
+
+	if uploadedBy != nil {
+		if _, err := s.userRepo.GetUserByID(*uploadedBy); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: user %s does not exist", ErrInvalidInput, *uploadedBy)
+			}
+			return nil, err
+		}
+	}
+
+	used, err := s.attachmentRepo.GetTaskAttachmentsSize(workspaceID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if used+size > s.maxTaskSize {
+		return nil, fmt.Errorf("%w: task attachments would exceed the %d byte limit", ErrTooLarge, s.maxTaskSize)
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	detected, err := mimetype.DetectReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect file type: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	id := uuid.New().String()
+	attachment := &models.Attachment{
+		ID:         id,
+		TaskID:     taskID,
+		Filename:   sanitizeFilename(filename),
+		Size:       size,
+		MimeType:   detected.String(),
+		Checksum:   hex.EncodeToString(hash.Sum(nil)),
+		StorageKey: fmt.Sprintf("tasks/%s/%s", taskID, id),
+		UploadedBy: uploadedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := s.store.Put(ctx, attachment.StorageKey, file, size, attachment.MimeType); err != nil {
+		return nil, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	if err := s.attachmentRepo.CreateAttachment(workspaceID, attachment, s.maxTaskSize); err != nil {
+		s.deleteObject(ctx, attachment.StorageKey)
+		if errors.Is(err, repository.ErrAttachmentQuotaExceeded) {
+			return nil, fmt.Errorf("%w: task attachments would exceed the %d byte limit", ErrTooLarge, s.maxTaskSize)
+		}
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// GetAttachments lists a task's attachments
+func (s *AttachmentService) GetAttachments(workspaceID string, taskID string) ([]models.Attachment, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	return s.attachmentRepo.GetTaskAttachments(workspaceID, taskID)
+}
+
+// OpenAttachment returns an attachment's metadata and a reader for its content.
+// The caller must close the reader
+func (s *AttachmentService) OpenAttachment(ctx context.Context, workspaceID string, taskID string, attachmentID string) (*models.Attachment, io.ReadCloser, error) {
+	attachment, err := s.getTaskAttachment(workspaceID, taskID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, fmt.Errorf("attachment content missing: %w", sql.ErrNoRows)
+		}
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment removes an attachment's metadata and its stored content
+func (s *AttachmentService) DeleteAttachment(ctx context.Context, workspaceID string, taskID string, attachmentID string) error {
+	attachment, err := s.getTaskAttachment(workspaceID, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.DeleteAttachment(workspaceID, attachmentID); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	s.deleteObject(ctx, attachment.StorageKey)
+	return nil
+}
+
+// getTaskAttachment loads an attachment and reports sql.ErrNoRows if it is not on the given task
+// or the task is outside the workspace
+func (s *AttachmentService) getTaskAttachment(workspaceID string, taskID string, attachmentID string) (*models.Attachment, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachmentByID(workspaceID, attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("attachment not found: %w", err)
+	}
+	if attachment.TaskID != taskID {
+		return nil, fmt.Errorf("attachment not found: %w", sql.ErrNoRows)
+	}
+
+	return attachment, nil
+}
+
+// deleteObject removes stored content on a best-effort basis; metadata is the source of truth
+func (s *AttachmentService) deleteObject(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		log.Printf("Warning: failed to delete stored object %s: %v", key, err)
+	}
+}
+
+// sanitizeFilename strips directories and control characters from a client supplied name
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[:maxFilenameLength])
+	}
+
+	return name
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const maxCommentLength = 10000
+
+// mentionPattern matches a mention of a user in a comment body, written as <@user_id>
+var mentionPattern = regexp.MustCompile(`<@([^<>\s]+)>`)
+
+// CommentService handles business logic for task comments
+type CommentService struct {
+	commentRepo      *repository.CommentRepository
+	taskRepo         *repository.TaskRepository
+	userRepo         *repository.UserRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewCommentService creates a new instance of CommentService
+func NewCommentService(commentRepo *repository.CommentRepository, taskRepo *repository.TaskRepository, userRepo *repository.UserRepository, notificationRepo *repository.NotificationRepository) *CommentService {
+	return &CommentService{
+		commentRepo:      commentRepo,
+		taskRepo:         taskRepo,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+// CreateComment adds a comment to a task.
+// A reply must point at a comment on the same task. The author starts watching the task,
+// and its other watchers are notified. Users mentioned as <@user_id> are notified too.
+// Notifications name actorID as their actor, or the author for anonymous requests;
+// when the request has an actor, the author must be the actor
+func (s *CommentService) CreateComment(workspaceID string, actorID string, taskID string, req models.CreateCommentRequest) (*models.Comment, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	body, err := normalizeCommentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = body
+
+	if actorID != "" && req.AuthorID != actorID {
+		return nil, fmt.Errorf("%w: author_id must be the user making the request", ErrInvalidInput)
+	}
+	_, err = s.userRepo.GetUserByID(req.AuthorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: author %s does not exist", ErrInvalidInput, req.AuthorID)
+		}
+		return nil, err
+	}
+
+	if req.ParentID != nil {
+		parent, err := s.commentRepo.GetCommentByID(workspaceID, *req.ParentID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: parent comment %s does not exist", ErrInvalidInput, *req.ParentID)
+			}
+			return nil, err
+		}
+		if parent.TaskID != taskID {
+			return nil, fmt.Errorf("%w: parent comment belongs to another task", ErrInvalidInput)
+		}
+	}
+
+	comment := models.NewComment(taskID, req)
+	if err := s.commentRepo.CreateComment(workspaceID, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+
+	// Anonymous comments are attributed to their author, who is then not notified about them
+	if actorID == "" {
+		actorID = req.AuthorID
+	}
+	if err := s.notificationRepo.AddWatcher(workspaceID, taskID, req.AuthorID); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	notification := models.NewNotification(workspaceID, taskID, models.NotifyCommentAdded, actorID)
+	notification.CommentID = &comment.ID
+	if _, err := s.notificationRepo.NotifyWatchers(notification); err != nil {
+		log.Printf("Warning: failed to notify watchers of task %s: %v", taskID, err)
+	}
+	s.notifyMentions(workspaceID, taskID, comment.ID, actorID, commentMentions(body))
+
+	return s.commentRepo.GetCommentByID(workspaceID, comment.ID)
+}
+
+// GetComments returns a page of comment threads for a task and the total number of threads
+func (s *CommentService) GetComments(workspaceID string, taskID string, filters models.CommentFilters) ([]models.Comment, int, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("task not found: %w", err)
+	}
+
+	total, err := s.commentRepo.CountRootComments(workspaceID, taskID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	comments, err := s.commentRepo.GetCommentThreads(workspaceID, taskID, filters.Limit, filters.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// UpdateComment edits the body of a comment that belongs to the given task.
+// Only users the edit newly mentions are notified, with actorID, or for anonymous requests the author, as the actor
+func (s *CommentService) UpdateComment(workspaceID string, actorID string, taskID string, commentID string, req models.UpdateCommentRequest) (*models.Comment, error) {
+	comment, err := s.getTaskComment(workspaceID, taskID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := normalizeCommentBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.commentRepo.UpdateCommentBody(workspaceID, commentID, body); err != nil {
+		return nil, fmt.Errorf("failed to update comment: %w", err)
+	}
+
+	previous := commentMentions(comment.Body)
+	var mentioned []string
+	for _, userID := range commentMentions(body) {
+		if !slices.Contains(previous, userID) {
+			mentioned = append(mentioned, userID)
+		}
+	}
+	if actorID == "" && comment.AuthorID != nil {
+		actorID = *comment.AuthorID
+	}
+	s.notifyMentions(workspaceID, taskID, commentID, actorID, mentioned)
+
+	return s.commentRepo.GetCommentByID(workspaceID, commentID)
+}
+
+// DeleteComment removes a comment and all of its replies
+func (s *CommentService) DeleteComment(workspaceID string, taskID string, commentID string) error {
+	if _, err := s.getTaskComment(workspaceID, taskID, commentID); err != nil {
+		return err
+	}
+
+	return s.commentRepo.DeleteComment(workspaceID, commentID)
+}
+
+// getTaskComment loads a comment and reports sql.ErrNoRows if it is not on the given task
+// or the task is outside the workspace
+func (s *CommentService) getTaskComment(workspaceID string, taskID string, commentID string) (*models.Comment, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	comment, err := s.commentRepo.GetCommentByID(workspaceID, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment not found: %w", err)
+	}
+	if comment.TaskID != taskID {
+		return nil, fmt.Errorf("comment not found: %w", sql.ErrNoRows)
+	}
+
+	return comment, nil
+}
+
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: comment body must not be empty", ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("%w: comment body must be at most %d characters", ErrInvalidInput, maxCommentLength)
+	}
+
+	return body, nil
+}
+
+// notifyMentions tells the mentioned users that exist about a comment. Unknown users are ignored,
+// and the comment is already stored, so a failure is logged rather than returned
+func (s *CommentService) notifyMentions(workspaceID string, taskID string, commentID string, actorID string, userIDs []string) {
+	var recipients []string
+	for _, userID := range userIDs {
+		if _, err := s.userRepo.GetUserByID(userID); err == nil {
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	notification := models.NewNotification(workspaceID, taskID, models.NotifyMentioned, actorID)
+	notification.CommentID = &commentID
+	if _, err := s.notificationRepo.NotifyUsers(notification, recipients); err != nil {
+		log.Printf("Warning: failed to notify users mentioned on task %s: %v", taskID, err)
+	}
+}
+
+// commentMentions returns the IDs of the users a comment body mentions, each once
+func commentMentions(body string) []string {
+	var userIDs []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		if !slices.Contains(userIDs, match[1]) {
+			userIDs = append(userIDs, match[1])
+		}
+	}
+	return userIDs
+}
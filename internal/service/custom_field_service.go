@@ -0,0 +1,343 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const (
+	maxCustomFieldKeyLength    = 50
+	maxCustomFieldNameLength   = 100
+	maxCustomFieldOptionLength = 100
+	maxCustomFieldTextLength   = 1000
+
+	// customFieldDateLayout is the format of date field values
+	customFieldDateLayout = "2006-01-02"
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CustomFieldService handles business logic for custom field definitions
+type CustomFieldService struct {
+	customFieldRepo *repository.CustomFieldRepository
+}
+
+// NewCustomFieldService creates a new instance of CustomFieldService
+func NewCustomFieldService(customFieldRepo *repository.CustomFieldRepository) *CustomFieldService {
+	return &CustomFieldService{customFieldRepo: customFieldRepo}
+}
+
+func (s *CustomFieldService) CreateCustomField(workspaceID string, req models.CreateCustomFieldRequest) (*models.CustomField, error) {
+	if len(req.Key) > maxCustomFieldKeyLength || !customFieldKeyPattern.MatchString(req.Key) {
+		return nil, fmt.Errorf("%w: key must start with a lowercase letter, contain only lowercase letters, digits and underscores and be at most %d characters",
+			ErrInvalidInput, maxCustomFieldKeyLength)
+	}
+	if !req.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown custom field type %q", ErrInvalidInput, req.Type)
+	}
+
+	name, err := normalizeCustomFieldName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	req.Name = name
+
+	options, err := normalizeCustomFieldOptions(req.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+	req.Options = options
+
+	field := models.NewCustomField(workspaceID, req)
+	if err := s.customFieldRepo.CreateCustomField(field); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: custom field %q already exists", ErrConflict, req.Key)
+		}
+		return nil, fmt.Errorf("failed to create custom field: %w", err)
+	}
+
+	return s.customFieldRepo.GetCustomFieldByID(workspaceID, field.ID)
+}
+
+func (s *CustomFieldService) GetCustomFields(workspaceID string) ([]models.CustomField, error) {
+	return s.customFieldRepo.GetCustomFields(workspaceID)
+}
+
+func (s *CustomFieldService) GetCustomField(workspaceID string, id string) (*models.CustomField, error) {
+	return s.customFieldRepo.GetCustomFieldByID(workspaceID, id)
+}
+
+// UpdateCustomField changes a field definition. Values already stored on tasks are kept
+// even if they no longer match the new options; they are validated again when next written
+func (s *CustomFieldService) UpdateCustomField(workspaceID string, id string, req models.UpdateCustomFieldRequest) (*models.CustomField, error) {
+	field, err := s.customFieldRepo.GetCustomFieldByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("custom field not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name, err := normalizeCustomFieldName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		req.Name = &name
+	}
+	if req.Options != nil {
+		options, err := normalizeCustomFieldOptions(field.Type, *req.Options)
+		if err != nil {
+			return nil, err
+		}
+		req.Options = &options
+	}
+
+	if err := s.customFieldRepo.UpdateCustomField(workspaceID, id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update custom field: %w", err)
+	}
+
+	return s.customFieldRepo.GetCustomFieldByID(workspaceID, id)
+}
+
+// DeleteCustomField removes a field definition together with its values on every task
+func (s *CustomFieldService) DeleteCustomField(workspaceID string, id string) error {
+	_, err := s.customFieldRepo.GetCustomFieldByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("custom field not found: %w", err)
+	}
+
+	return s.customFieldRepo.DeleteCustomField(workspaceID, id)
+}
+
+func normalizeCustomFieldName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: custom field name must not be empty", ErrInvalidInput)
+	}
+	if len([]rune(name)) > maxCustomFieldNameLength {
+		return "", fmt.Errorf("%w: custom field name must be at most %d characters", ErrInvalidInput, maxCustomFieldNameLength)
+	}
+	return name, nil
+}
+
+// normalizeCustomFieldOptions trims the options of a select field and rejects empty or repeated ones.
+// Fields of other types must not have options
+func normalizeCustomFieldOptions(fieldType models.CustomFieldType, options []string) ([]string, error) {
+	if !fieldType.IsSelect() {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("%w: only select fields have options", ErrInvalidInput)
+		}
+		return nil, nil
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("%w: select fields need at least one option", ErrInvalidInput)
+	}
+
+	seen := make(map[string]bool, len(options))
+	normalized := make([]string, 0, len(options))
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" || len([]rune(option)) > maxCustomFieldOptionLength {
+			return nil, fmt.Errorf("%w: options must be 1 to %d characters long", ErrInvalidInput, maxCustomFieldOptionLength)
+		}
+		if seen[option] {
+			return nil, fmt.Errorf("%w: option %q is listed more than once", ErrInvalidInput, option)
+		}
+		seen[option] = true
+		normalized = append(normalized, option)
+	}
+	return normalized, nil
+}
+
+// normalizeCustomFieldValues validates task field values against the workspace's definitions and
+// returns them in stored form. When creating, every required field must have a value. When
+// updating, values is a patch: a null value clears the field, which required fields do not allow
+func normalizeCustomFieldValues(fields []models.CustomField, userRepo *repository.UserRepository, values models.CustomFieldValues, creating bool) (models.CustomFieldValues, error) {
+	byKey := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	normalized := make(models.CustomFieldValues, len(values))
+	for key, value := range values {
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown custom field %q", ErrInvalidInput, key)
+		}
+
+		if value == nil {
+			if field.Required {
+				return nil, fmt.Errorf("%w: custom field %q is required", ErrInvalidInput, key)
+			}
+			if !creating {
+				normalized[key] = nil
+			}
+			continue
+		}
+
+		v, err := normalizeCustomFieldValue(field, userRepo, value)
+		if err != nil {
+			return nil, err
+		}
+		normalized[key] = v
+	}
+
+	if creating {
+		for _, field := range fields {
+			if _, ok := normalized[field.Key]; field.Required && !ok {
+				return nil, fmt.Errorf("%w: custom field %q is required", ErrInvalidInput, field.Key)
+			}
+		}
+	}
+
+	return normalized, nil
+}
+
+func normalizeCustomFieldValue(field models.CustomField, userRepo *repository.UserRepository, value any) (any, error) {
+	invalid := func(expected string) error {
+		return fmt.Errorf("%w: custom field %q must be %s", ErrInvalidInput, field.Key, expected)
+	}
+
+	switch field.Type {
+	case models.CustomFieldNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, invalid("a number")
+		}
+		return n, nil
+
+	case models.CustomFieldMultiSelect:
+		items, ok := value.([]any)
+		if !ok {
+			return nil, invalid("a list of options")
+		}
+		seen := make(map[string]bool, len(items))
+		selected := make([]string, 0, len(items))
+		for _, item := range items {
+			option, ok := item.(string)
+			if !ok || !containsOption(field.Options, option) {
+				return nil, invalid("a list of: " + strings.Join(field.Options, ", "))
+			}
+			if !seen[option] {
+				seen[option] = true
+				selected = append(selected, option)
+			}
+		}
+		if field.Required && len(selected) == 0 {
+			return nil, fmt.Errorf("%w: custom field %q is required", ErrInvalidInput, field.Key)
+		}
+		return selected, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return nil, invalid("a string")
+	}
+
+	switch field.Type {
+	case models.CustomFieldText:
+		if len([]rune(s)) > maxCustomFieldTextLength {
+			return nil, invalid(fmt.Sprintf("at most %d characters", maxCustomFieldTextLength))
+		}
+		if field.Required && strings.TrimSpace(s) == "" {
+			return nil, fmt.Errorf("%w: custom field %q is required", ErrInvalidInput, field.Key)
+		}
+	case models.CustomFieldDate:
+		if _, err := time.Parse(customFieldDateLayout, s); err != nil {
+			return nil, invalid("a date in YYYY-MM-DD format")
+		}
+	case models.CustomFieldSingleSelect:
+		if !containsOption(field.Options, s) {
+			return nil, invalid("one of: " + strings.Join(field.Options, ", "))
+		}
+	case models.CustomFieldUser:
+		if _, err := userRepo.GetUserByID(s); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: user %s not found for custom field %q", ErrInvalidInput, s, field.Key)
+			}
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+func containsOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCustomFieldFilters turns the cf.<key> filters and a cf.<key> sort of a task listing
+// into the typed match document and sort mode the repository expects
+func resolveCustomFieldFilters(customFieldRepo *repository.CustomFieldRepository, workspaceID string, filters *models.TaskFilters) error {
+	sortKey, sortsByField := strings.CutPrefix(filters.SortBy, models.CustomFieldPrefix)
+	if len(filters.CustomFields) == 0 && !sortsByField {
+		return nil
+	}
+
+	fields, err := customFieldRepo.GetCustomFields(workspaceID)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]models.CustomField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	if sortsByField {
+		field, ok := byKey[sortKey]
+		if !ok {
+			return fmt.Errorf("%w: cannot sort by unknown custom field %q", ErrInvalidInput, sortKey)
+		}
+		filters.SortNumeric = field.Type == models.CustomFieldNumber
+	}
+
+	if len(filters.CustomFields) == 0 {
+		return nil
+	}
+
+	match := make(map[string]any, len(filters.CustomFields))
+	for key, raw := range filters.CustomFields {
+		field, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("%w: cannot filter by unknown custom field %q", ErrInvalidInput, key)
+		}
+
+		switch field.Type {
+		case models.CustomFieldNumber:
+			n, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("%w: filter for custom field %q must be a number", ErrInvalidInput, key)
+			}
+			match[key] = n
+		case models.CustomFieldDate:
+			if _, err := time.Parse(customFieldDateLayout, raw); err != nil {
+				return fmt.Errorf("%w: filter for custom field %q must be a date in YYYY-MM-DD format", ErrInvalidInput, key)
+			}
+			match[key] = raw
+		case models.CustomFieldMultiSelect:
+			// Containment matches tasks whose selection includes the option
+			match[key] = []string{raw}
+		default:
+			match[key] = raw
+		}
+	}
+
+	data, err := json.Marshal(match)
+	if err != nil {
+		return err
+	}
+	filters.CustomFieldMatch = string(data)
+	return nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// DependencyService handles business logic for blocked-by relations between tasks
+type DependencyService struct {
+	dependencyRepo *repository.DependencyRepository
+	taskRepo       *repository.TaskRepository
+}
+
+// NewDependencyService creates a new instance of DependencyService
+func NewDependencyService(dependencyRepo *repository.DependencyRepository, taskRepo *repository.TaskRepository) *DependencyService {
+	return &DependencyService{
+		dependencyRepo: dependencyRepo,
+		taskRepo:       taskRepo,
+	}
+}
+
+// AddDependency marks a task as blocked by another task of the same workspace.
+// Edges that would close a cycle are rejected as a conflict
+func (s *DependencyService) AddDependency(workspaceID string, taskID string, blockerID string) (*models.TaskDependencies, error) {
+	if taskID == blockerID {
+		return nil, fmt.Errorf("%w: a task cannot depend on itself", ErrInvalidInput)
+	}
+
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, blockerID); err != nil {
+		return nil, fmt.Errorf("blocking task not found: %w", err)
+	}
+
+	if err := s.dependencyRepo.AddDependency(workspaceID, taskID, blockerID); err != nil {
+		if errors.Is(err, repository.ErrDependencyCycle) {
+			return nil, fmt.Errorf("%w: task %s already depends on task %s", ErrConflict, blockerID, taskID)
+		}
+		return nil, fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	return s.GetDependencies(workspaceID, taskID)
+}
+
+// RemoveDependency removes a blocked-by edge
+func (s *DependencyService) RemoveDependency(workspaceID string, taskID string, blockerID string) (*models.TaskDependencies, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if err := s.dependencyRepo.RemoveDependency(workspaceID, taskID, blockerID); err != nil {
+		return nil, fmt.Errorf("task is not blocked by %s: %w", blockerID, err)
+	}
+
+	return s.GetDependencies(workspaceID, taskID)
+}
+
+// GetDependencies lists the tasks a task waits on and the tasks waiting on it
+func (s *DependencyService) GetDependencies(workspaceID string, taskID string) (*models.TaskDependencies, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	blockedBy, err := s.dependencyRepo.GetBlockers(workspaceID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks, err := s.dependencyRepo.GetBlockedTasks(workspaceID, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TaskDependencies{BlockedBy: blockedBy, Blocks: blocks}, nil
+}
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/mail"
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const (
+	// emailBatchSize caps how many emails one query queues or claims
+	emailBatchSize = 50
+	// emailLease is how long a claimed email is hidden from other replicas while it is sent
+	emailLease = 5 * time.Minute
+	// notificationEmailWindow is how old a notification can be and still be emailed,
+	// so turning email on does not mail out the whole inbox
+	notificationEmailWindow = time.Hour
+	// digestPeriod is how far back a daily digest looks for unread notifications
+	digestPeriod = 24 * time.Hour
+	// maxDigestItems caps how many notifications one digest lists
+	maxDigestItems = 50
+	// maxEmailLimit caps how many log entries one request returns
+	maxEmailLimit = 200
+	// emailRetryDelay is the wait after the first failed attempt; it doubles after each next one
+	emailRetryDelay = time.Minute
+	// maxEmailRetryDelay caps the wait between attempts
+	maxEmailRetryDelay = time.Hour
+)
+
+// EmailOptions configures how email notifications are sent
+type EmailOptions struct {
+	// AppURL is the address of the web app that links in emails point to
+	AppURL string
+	// DigestHour is the hour of the day daily digests are sent at; negative disables them
+	DigestHour int
+	// MaxAttempts is how many times an email is tried before it is marked as failed
+	MaxAttempts int
+}
+
+// EmailService emails users about their notifications: assignments, mentions and reminders as
+// they happen, and a daily digest of what is still unread. Emails go through a delivery log,
+// so every attempt and its outcome can be inspected and failed emails are retried
+type EmailService struct {
+	emailRepo        *repository.EmailRepository
+	notificationRepo *repository.NotificationRepository
+	userRepo         *repository.UserRepository
+	sender           mail.Sender
+	templates        *mail.Templates
+	options          EmailOptions
+}
+
+// emailData is what the email templates are rendered with
+type emailData struct {
+	Recipient    models.User
+	Notification *models.Notification
+	TaskURL      string
+	// DueDate and Overdue describe the due date of a reminder
+	DueDate string
+	Overdue bool
+	// Items are the notifications a digest lists
+	Items []digestItem
+}
+
+type digestItem struct {
+	TaskTitle string
+	Summary   string
+	TaskURL   string
+}
+
+// errNothingToSend reports that an email has no content left, such as a digest whose notifications were all read
+var errNothingToSend = errors.New("nothing to send")
+
+// NewEmailService creates a new instance of EmailService. A nil sender disables email
+func NewEmailService(emailRepo *repository.EmailRepository, notificationRepo *repository.NotificationRepository, userRepo *repository.UserRepository, sender mail.Sender, templates *mail.Templates, options EmailOptions) *EmailService {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	options.AppURL = strings.TrimRight(options.AppURL, "/")
+
+	return &EmailService{
+		emailRepo:        emailRepo,
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		sender:           sender,
+		templates:        templates,
+		options:          options,
+	}
+}
+
+// RunDelivery queues and sends email notifications every interval until ctx is done
+func (s *EmailService) RunDelivery(ctx context.Context, interval time.Duration) {
+	if s.sender == nil {
+		log.Println("SMTP_HOST is not set, email notifications are disabled")
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.QueueEmails(time.Now()); err != nil {
+			log.Printf("Warning: queueing emails failed: %v", err)
+		}
+		if err := s.SendQueuedEmails(ctx); err != nil {
+			log.Printf("Warning: sending emails failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// QueueEmails adds to the delivery log an email for every recent notification a user should be
+// emailed about, and once the digest hour of the day has passed, each user's daily digest.
+// Safe to run concurrently from several replicas
+func (s *EmailService) QueueEmails(now time.Time) error {
+	for _, kind := range models.EmailKinds {
+		types := kind.NotificationTypes()
+		if len(types) == 0 {
+			continue
+		}
+		for {
+			queued, err := s.emailRepo.QueueNotificationEmails(kind, types, now.Add(-notificationEmailWindow), emailBatchSize)
+			if err != nil {
+				return err
+			}
+			if queued < emailBatchSize {
+				break
+			}
+		}
+	}
+
+	if s.options.DigestHour < 0 {
+		return nil
+	}
+	year, month, day := now.Date()
+	if now.Before(time.Date(year, month, day, s.options.DigestHour, 0, 0, 0, now.Location())) {
+		return nil
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	for {
+		queued, err := s.emailRepo.QueueDigests(date, now.Add(-digestPeriod), now, emailBatchSize)
+		if err != nil {
+			return err
+		}
+		if queued < emailBatchSize {
+			return nil
+		}
+	}
+}
+
+// SendQueuedEmails sends the queued emails that are due, batch by batch. An email that fails is tried
+// again later, waiting longer after each attempt, until it runs out of attempts.
+// Safe to run concurrently from several replicas
+func (s *EmailService) SendQueuedEmails(ctx context.Context) error {
+	for {
+		deliveries, err := s.emailRepo.ClaimDeliveries(emailLease, emailBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to claim emails: %w", err)
+		}
+
+		for i := range deliveries {
+			s.deliver(ctx, &deliveries[i])
+		}
+
+		if len(deliveries) < emailBatchSize || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// deliver renders and sends a claimed email and records the outcome in the delivery log
+func (s *EmailService) deliver(ctx context.Context, delivery *models.EmailDelivery) {
+	err := s.send(ctx, delivery)
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = models.EmailSent
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.SentAt = &now
+	case delivery.Status == models.EmailSkipped:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= s.options.MaxAttempts:
+		delivery.Status = models.EmailFailed
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		retryAt := now.Add(emailRetryBackoff(delivery.Attempts))
+		delivery.Status = models.EmailPending
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &retryAt
+	}
+
+	if err := s.emailRepo.FinishDelivery(delivery); err != nil {
+		log.Printf("Warning: failed to record delivery of email %s: %v", delivery.ID, err)
+	}
+}
+
+// send renders an email for its recipient and hands it to the SMTP server. It marks the email as
+// skipped, and returns why, when the recipient no longer wants it or there is nothing left to send
+func (s *EmailService) send(ctx context.Context, delivery *models.EmailDelivery) error {
+	user, err := s.userRepo.GetUserByID(delivery.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load recipient: %w", err)
+	}
+	if user.Email == "" {
+		delivery.Status = models.EmailSkipped
+		return errors.New("user has no email address")
+	}
+	optedOut, err := s.emailRepo.IsOptedOut(user.ID, delivery.Kind)
+	if err != nil {
+		return err
+	}
+	if optedOut {
+		delivery.Status = models.EmailSkipped
+		return fmt.Errorf("user opted out of %s emails", delivery.Kind)
+	}
+
+	data, err := s.emailData(delivery, user)
+	if err != nil {
+		if errors.Is(err, errNothingToSend) || errors.Is(err, sql.ErrNoRows) {
+			delivery.Status = models.EmailSkipped
+		}
+		return err
+	}
+
+	msg, err := s.templates.Render(string(delivery.Kind), user.Email, data)
+	if err != nil {
+		return err
+	}
+	delivery.To = msg.To
+	delivery.Subject = msg.Subject
+
+	return s.sender.Send(ctx, msg)
+}
+
+// emailData gathers what the template of an email is rendered with
+func (s *EmailService) emailData(delivery *models.EmailDelivery, user *models.User) (*emailData, error) {
+	data := &emailData{Recipient: *user}
+
+	if delivery.Kind == models.EmailDigest {
+		notifications, err := s.notificationRepo.GetUnreadBetween(delivery.WorkspaceID, user.ID,
+			delivery.CreatedAt.Add(-digestPeriod), delivery.CreatedAt, maxDigestItems)
+		if err != nil {
+			return nil, err
+		}
+		if len(notifications) == 0 {
+			return nil, fmt.Errorf("%w: every notification of the digest was read", errNothingToSend)
+		}
+		for _, notification := range notifications {
+			data.Items = append(data.Items, digestItem{
+				TaskTitle: notification.TaskTitle,
+				Summary:   describeNotification(notification),
+				TaskURL:   s.taskURL(notification.TaskID),
+			})
+		}
+		return data, nil
+	}
+
+	if delivery.NotificationID == nil {
+		return nil, fmt.Errorf("%w: the email has no notification", errNothingToSend)
+	}
+	notification, err := s.notificationRepo.GetNotification(delivery.WorkspaceID, user.ID, *delivery.NotificationID)
+	if err != nil {
+		return nil, fmt.Errorf("notification not found: %w", err)
+	}
+	data.Notification = notification
+	data.TaskURL = s.taskURL(notification.TaskID)
+	data.Overdue = notification.Type == models.NotifyTaskOverdue
+	if notification.DueDate != nil {
+		data.DueDate = notification.DueDate.Format("Jan 2, 2006 at 15:04")
+	}
+
+	return data, nil
+}
+
+// taskURL links to a task in the web app
+func (s *EmailService) taskURL(taskID string) string {
+	return s.options.AppURL + "/tasks/" + taskID
+}
+
+// GetSettings retrieves the email kinds a user opted out of
+func (s *EmailService) GetSettings(userID string) (*models.EmailSettings, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	optedOut, err := s.emailRepo.GetOptOuts(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EmailSettings{OptedOut: optedOut}, nil
+}
+
+// UpdateSettings replaces the email kinds a user opted out of. Queued emails of those kinds are skipped
+func (s *EmailService) UpdateSettings(userID string, req models.EmailSettings) (*models.EmailSettings, error) {
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	optedOut := make([]models.EmailKind, 0, len(req.OptedOut))
+	for _, kind := range req.OptedOut {
+		if !kind.IsValid() {
+			return nil, fmt.Errorf("%w: unknown email kind %q", ErrInvalidInput, kind)
+		}
+		if !slices.Contains(optedOut, kind) {
+			optedOut = append(optedOut, kind)
+		}
+	}
+
+	if err := s.emailRepo.SetOptOuts(userID, optedOut); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(userID)
+}
+
+// GetDeliveries retrieves the entries of the delivery log matching filters, newest first
+func (s *EmailService) GetDeliveries(workspaceID string, filters models.EmailDeliveryFilters) ([]models.EmailDelivery, error) {
+	if filters.Kind != "" && !filters.Kind.IsValid() {
+		return nil, fmt.Errorf("%w: unknown email kind %q", ErrInvalidInput, filters.Kind)
+	}
+	if filters.Status != "" && !filters.Status.IsValid() {
+		return nil, fmt.Errorf("%w: unknown email status %q", ErrInvalidInput, filters.Status)
+	}
+	if filters.Limit < 0 || filters.Limit > maxEmailLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxEmailLimit)
+	}
+	if filters.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+
+	return s.emailRepo.GetDeliveries(workspaceID, filters)
+}
+
+// GetDelivery retrieves an entry of the delivery log
+func (s *EmailService) GetDelivery(workspaceID string, id string) (*models.EmailDelivery, error) {
+	return s.emailRepo.GetDeliveryByID(workspaceID, id)
+}
+
+// RetryDelivery queues a failed email again with a fresh set of attempts
+func (s *EmailService) RetryDelivery(workspaceID string, id string) (*models.EmailDelivery, error) {
+	delivery, err := s.emailRepo.GetDeliveryByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("email not found: %w", err)
+	}
+	if delivery.Status != models.EmailFailed {
+		return nil, fmt.Errorf("%w: only failed emails can be retried, this one is %s", ErrConflict, delivery.Status)
+	}
+
+	if err := s.emailRepo.RetryDelivery(workspaceID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: the email is no longer failed", ErrConflict)
+		}
+		return nil, err
+	}
+
+	return s.emailRepo.GetDeliveryByID(workspaceID, id)
+}
+
+// emailRetryBackoff is how long to wait before the next attempt after the given number of attempts
+func emailRetryBackoff(attempts int) time.Duration {
+	delay := emailRetryDelay
+	for i := 1; i < attempts && delay < maxEmailRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxEmailRetryDelay)
+}
+
+// describeNotification says in a few words what a notification is about, for the daily digest
+func describeNotification(notification models.Notification) string {
+	actor := "Someone"
+	if notification.Actor != nil {
+		actor = notification.Actor.Name
+	}
+
+	switch notification.Type {
+	case models.NotifyTaskAssigned:
+		return actor + " assigned you"
+	case models.NotifyTaskUpdated:
+		return actor + " updated the task"
+	case models.NotifyTaskDeleted:
+		return actor + " moved the task to the trash"
+	case models.NotifyTaskRestored:
+		return actor + " restored the task"
+	case models.NotifySubTaskChanged:
+		return actor + " changed a subtask"
+	case models.NotifyCommentAdded:
+		return actor + " commented"
+	case models.NotifyMentioned:
+		return actor + " mentioned you in a comment"
+	case models.NotifyTaskDueSoon:
+		return "the task is due soon"
+	case models.NotifyTaskOverdue:
+		return "the task is overdue"
+	default:
+		return string(notification.Type)
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import "errors"
+
+// ErrInvalidInput is returned when a request fails business validation.
+// Handlers map it to 400 Bad Request.
+var ErrInvalidInput = errors.New("invalid input")
+
+// ErrTooLarge is returned when an upload exceeds a configured size limit.
+// Handlers map it to 413 Request Entity Too Large.
+var ErrTooLarge = errors.New("too large")
+
+// ErrConflict is returned when a request would duplicate an existing resource.
+// Handlers map it to 409 Conflict.
+var ErrConflict = errors.New("conflict")
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const maxLabelNameLength = 50
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// LabelService handles business logic for labels and their assignment to tasks
+type LabelService struct {
+	labelRepo        *repository.LabelRepository
+	taskRepo         *repository.TaskRepository
+	activityRepo     *repository.ActivityRepository
+	revisionRepo     *repository.RevisionRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewLabelService creates a new instance of LabelService
+func NewLabelService(labelRepo *repository.LabelRepository, taskRepo *repository.TaskRepository, activityRepo *repository.ActivityRepository, revisionRepo *repository.RevisionRepository, notificationRepo *repository.NotificationRepository) *LabelService {
+	return &LabelService{
+		labelRepo:        labelRepo,
+		taskRepo:         taskRepo,
+		activityRepo:     activityRepo,
+		revisionRepo:     revisionRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *LabelService) CreateLabel(workspaceID string, req models.CreateLabelRequest) (*models.Label, error) {
+	name, err := normalizeLabelName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	req.Name = name
+
+	if req.Color == "" {
+		req.Color = models.DefaultLabelColor
+	}
+	if !labelColorPattern.MatchString(req.Color) {
+		return nil, fmt.Errorf("%w: color must be a #rrggbb hex value", ErrInvalidInput)
+	}
+	req.Color = strings.ToLower(req.Color)
+
+	label := models.NewLabel(workspaceID, req)
+	if err := s.labelRepo.CreateLabel(label); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: label %q already exists", ErrConflict, req.Name)
+		}
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return s.labelRepo.GetLabelByID(workspaceID, label.ID)
+}
+
+func (s *LabelService) GetLabels(workspaceID string) ([]models.Label, error) {
+	return s.labelRepo.GetLabels(workspaceID)
+}
+
+func (s *LabelService) GetLabel(workspaceID string, id string) (*models.Label, error) {
+	return s.labelRepo.GetLabelByID(workspaceID, id)
+}
+
+func (s *LabelService) UpdateLabel(workspaceID string, id string, req models.UpdateLabelRequest) (*models.Label, error) {
+	_, err := s.labelRepo.GetLabelByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("label not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name, err := normalizeLabelName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		req.Name = &name
+	}
+	if req.Color != nil {
+		if !labelColorPattern.MatchString(*req.Color) {
+			return nil, fmt.Errorf("%w: color must be a #rrggbb hex value", ErrInvalidInput)
+		}
+		color := strings.ToLower(*req.Color)
+		req.Color = &color
+	}
+
+	if err := s.labelRepo.UpdateLabel(workspaceID, id, &req); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: label %q already exists", ErrConflict, *req.Name)
+		}
+		return nil, fmt.Errorf("failed to update label: %w", err)
+	}
+
+	return s.labelRepo.GetLabelByID(workspaceID, id)
+}
+
+func (s *LabelService) DeleteLabel(workspaceID string, id string) error {
+	_, err := s.labelRepo.GetLabelByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("label not found: %w", err)
+	}
+
+	return s.labelRepo.DeleteLabel(workspaceID, id)
+}
+
+// AssignLabel attaches a label to a task
+func (s *LabelService) AssignLabel(workspaceID string, actorID string, taskID string, labelID string) (*models.Task, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	_, err = s.labelRepo.GetLabelByID(workspaceID, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("label not found: %w", err)
+	}
+
+	if err := s.labelRepo.AssignLabel(workspaceID, taskID, labelID); err != nil {
+		return nil, fmt.Errorf("failed to assign label: %w", err)
+	}
+
+	return s.recordLabelChange(workspaceID, actorID, task)
+}
+
+// UnassignLabel detaches a label from a task
+func (s *LabelService) UnassignLabel(workspaceID string, actorID string, taskID string, labelID string) (*models.Task, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if err := s.labelRepo.UnassignLabel(workspaceID, taskID, labelID); err != nil {
+		return nil, fmt.Errorf("label is not assigned to the task: %w", err)
+	}
+
+	return s.recordLabelChange(workspaceID, actorID, task)
+}
+
+// recordLabelChange reloads a task after its labels changed and records the change in the audit log
+// and as a new revision
+func (s *LabelService) recordLabelChange(workspaceID string, actorID string, before *models.Task) (*models.Task, error) {
+	updated, err := s.taskRepo.GetTaskByID(workspaceID, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	if changes := diffAuditFields(taskAuditFields(before), taskAuditFields(updated)); len(changes) > 0 {
+		activity := models.NewActivity(workspaceID, before.ID, nil, actorID, models.ActionUpdated, changes)
+		recordActivity(s.activityRepo, activity)
+		notifyWatchers(s.notificationRepo, activity)
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, before.ID, actorID)
+
+	return updated, nil
+}
+
+func normalizeLabelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: label name must not be empty", ErrInvalidInput)
+	}
+	if len([]rune(name)) > maxLabelNameLength {
+		return "", fmt.Errorf("%w: label name must be at most %d characters", ErrInvalidInput, maxLabelNameLength)
+	}
+	return name, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const maxLinkTitleLength = 255
+
+// LinkService handles business logic for task links
+type LinkService struct {
+	linkRepo *repository.LinkRepository
+	taskRepo *repository.TaskRepository
+}
+
+// NewLinkService creates a new instance of LinkService
+func NewLinkService(linkRepo *repository.LinkRepository, taskRepo *repository.TaskRepository) *LinkService {
+	return &LinkService{
+		linkRepo: linkRepo,
+		taskRepo: taskRepo,
+	}
+}
+
+// CreateLink adds a link to a task. A task can reference each URL only once
+func (s *LinkService) CreateLink(workspaceID string, taskID string, req models.CreateTaskLinkRequest) (*models.TaskLink, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	u, ok := parseHTTPURL(req.URL)
+	if !ok {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidInput)
+	}
+	req.URL = u.String()
+
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		req.Title = u.Host
+	}
+	if len([]rune(req.Title)) > maxLinkTitleLength {
+		return nil, fmt.Errorf("%w: title must be at most %d characters", ErrInvalidInput, maxLinkTitleLength)
+	}
+
+	if req.Kind == "" {
+		req.Kind = models.LinkKindOther
+	}
+	if !req.Kind.IsValid() {
+		return nil, fmt.Errorf("%w: unknown link kind %q", ErrInvalidInput, req.Kind)
+	}
+
+	link := models.NewTaskLink(taskID, req)
+	if err := s.linkRepo.CreateLink(workspaceID, link); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: url is already linked to this task", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to create link: %w", err)
+	}
+
+	return s.linkRepo.GetLinkByID(workspaceID, link.ID)
+}
+
+// GetLinks lists a task's links
+func (s *LinkService) GetLinks(workspaceID string, taskID string) ([]models.TaskLink, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	return s.linkRepo.GetTaskLinks(workspaceID, taskID)
+}
+
+// UpdateLink updates a link that belongs to the given task
+func (s *LinkService) UpdateLink(workspaceID string, taskID string, linkID string, req models.UpdateTaskLinkRequest) (*models.TaskLink, error) {
+	if _, err := s.getTaskLink(workspaceID, taskID, linkID); err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		u, ok := parseHTTPURL(*req.URL)
+		if !ok {
+			return nil, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidInput)
+		}
+		normalized := u.String()
+		req.URL = &normalized
+	}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, fmt.Errorf("%w: title must not be empty", ErrInvalidInput)
+		}
+		if len([]rune(title)) > maxLinkTitleLength {
+			return nil, fmt.Errorf("%w: title must be at most %d characters", ErrInvalidInput, maxLinkTitleLength)
+		}
+		req.Title = &title
+	}
+	if req.Kind != nil && !req.Kind.IsValid() {
+		return nil, fmt.Errorf("%w: unknown link kind %q", ErrInvalidInput, *req.Kind)
+	}
+
+	if err := s.linkRepo.UpdateLink(workspaceID, linkID, &req); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: url is already linked to this task", ErrConflict)
+		}
+		return nil, fmt.Errorf("failed to update link: %w", err)
+	}
+
+	return s.linkRepo.GetLinkByID(workspaceID, linkID)
+}
+
+// DeleteLink removes a link from a task
+func (s *LinkService) DeleteLink(workspaceID string, taskID string, linkID string) error {
+	if _, err := s.getTaskLink(workspaceID, taskID, linkID); err != nil {
+		return err
+	}
+
+	return s.linkRepo.DeleteLink(workspaceID, linkID)
+}
+
+// getTaskLink loads a link and reports sql.ErrNoRows if it is not on the given task
+// or the task is outside the workspace
+func (s *LinkService) getTaskLink(workspaceID string, taskID string, linkID string) (*models.TaskLink, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	link, err := s.linkRepo.GetLinkByID(workspaceID, linkID)
+	if err != nil {
+		return nil, fmt.Errorf("link not found: %w", err)
+	}
+	if link.TaskID != taskID {
+		return nil, fmt.Errorf("link not found: %w", sql.ErrNoRows)
+	}
+
+	return link, nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// MilestoneService handles business logic for milestones
+type MilestoneService struct {
+	milestoneRepo   *repository.MilestoneRepository
+	projectRepo     *repository.ProjectRepository
+	taskRepo        *repository.TaskRepository
+	customFieldRepo *repository.CustomFieldRepository
+}
+
+// NewMilestoneService creates a new instance of MilestoneService
+func NewMilestoneService(milestoneRepo *repository.MilestoneRepository, projectRepo *repository.ProjectRepository, taskRepo *repository.TaskRepository, customFieldRepo *repository.CustomFieldRepository) *MilestoneService {
+	return &MilestoneService{
+		milestoneRepo:   milestoneRepo,
+		projectRepo:     projectRepo,
+		taskRepo:        taskRepo,
+		customFieldRepo: customFieldRepo,
+	}
+}
+
+func (s *MilestoneService) CreateMilestone(workspaceID string, req models.CreateMilestoneRequest) (*models.Milestone, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+	}
+	if req.ProjectID != nil {
+		if err := checkProjectExists(s.projectRepo, workspaceID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	milestone := models.NewMilestone(workspaceID, req)
+	if err := s.milestoneRepo.CreateMilestone(milestone); err != nil {
+		return nil, fmt.Errorf("failed to create milestone: %w", err)
+	}
+
+	return s.milestoneRepo.GetMilestoneByID(workspaceID, milestone.ID)
+}
+
+func (s *MilestoneService) GetMilestone(workspaceID string, id string) (*models.Milestone, error) {
+	return s.milestoneRepo.GetMilestoneByID(workspaceID, id)
+}
+
+func (s *MilestoneService) GetMilestones(workspaceID string, projectID string) ([]models.Milestone, error) {
+	return s.milestoneRepo.GetMilestones(workspaceID, projectID)
+}
+
+func (s *MilestoneService) UpdateMilestone(workspaceID string, id string, req models.UpdateMilestoneRequest) (*models.Milestone, error) {
+	_, err := s.milestoneRepo.GetMilestoneByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("milestone not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+
+	if err := s.milestoneRepo.UpdateMilestone(workspaceID, id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update milestone: %w", err)
+	}
+
+	return s.milestoneRepo.GetMilestoneByID(workspaceID, id)
+}
+
+// DeleteMilestone removes a milestone. Its tasks stay and no longer belong to any milestone
+func (s *MilestoneService) DeleteMilestone(workspaceID string, id string) error {
+	_, err := s.milestoneRepo.GetMilestoneByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("milestone not found: %w", err)
+	}
+
+	return s.milestoneRepo.DeleteMilestone(workspaceID, id)
+}
+
+// GetMilestoneTasks lists the tasks of a milestone using the regular task filters
+func (s *MilestoneService) GetMilestoneTasks(workspaceID string, id string, filters models.TaskFilters) ([]models.Task, error) {
+	_, err := s.milestoneRepo.GetMilestoneByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("milestone not found: %w", err)
+	}
+
+	filters.MilestoneID = id
+	if err := resolveCustomFieldFilters(s.customFieldRepo, workspaceID, &filters); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetTasks(workspaceID, filters)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// maxNotificationLimit caps how many notifications one request returns
+const maxNotificationLimit = 200
+
+// NotificationService handles business logic for task watchers and the notification inbox of each user
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+	taskRepo         *repository.TaskRepository
+	userRepo         *repository.UserRepository
+}
+
+// NewNotificationService creates a new instance of NotificationService
+func NewNotificationService(notificationRepo *repository.NotificationRepository, taskRepo *repository.TaskRepository, userRepo *repository.UserRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		taskRepo:         taskRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// GetWatchers retrieves the users watching a task
+func (s *NotificationService) GetWatchers(workspaceID string, taskID string) ([]models.User, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	return s.notificationRepo.GetWatchers(workspaceID, taskID)
+}
+
+// Watch makes a user watch a task and returns the task's watchers
+func (s *NotificationService) Watch(workspaceID string, taskID string, userID string) ([]models.User, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	if _, err := s.userRepo.GetUserByID(userID); err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := s.notificationRepo.AddWatcher(workspaceID, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.notificationRepo.GetWatchers(workspaceID, taskID)
+}
+
+// Unwatch stops a user watching a task and returns the task's remaining watchers.
+// Assignees can unwatch their tasks too
+func (s *NotificationService) Unwatch(workspaceID string, taskID string, userID string) ([]models.User, error) {
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if err := s.notificationRepo.RemoveWatcher(workspaceID, taskID, userID); err != nil {
+		return nil, fmt.Errorf("user does not watch the task: %w", err)
+	}
+
+	return s.notificationRepo.GetWatchers(workspaceID, taskID)
+}
+
+// GetNotifications retrieves the notifications of a user matching filters, newest first
+func (s *NotificationService) GetNotifications(workspaceID string, userID string, filters models.NotificationFilters) ([]models.Notification, error) {
+	if filters.Type != "" && !filters.Type.IsValid() {
+		return nil, fmt.Errorf("%w: unknown notification type %q", ErrInvalidInput, filters.Type)
+	}
+	if filters.Limit < 0 || filters.Limit > maxNotificationLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidInput, maxNotificationLimit)
+	}
+	if filters.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", ErrInvalidInput)
+	}
+
+	return s.notificationRepo.GetNotifications(workspaceID, userID, filters)
+}
+
+// GetUnreadCount counts the unread notifications of a user, in total and per type
+func (s *NotificationService) GetUnreadCount(workspaceID string, userID string) (*models.UnreadCount, error) {
+	counts, err := s.notificationRepo.GetUnreadCounts(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	unread := &models.UnreadCount{ByType: counts}
+	for _, count := range counts {
+		unread.Total += count
+	}
+	return unread, nil
+}
+
+// MarkRead marks one notification of a user as read
+func (s *NotificationService) MarkRead(workspaceID string, userID string, id string) error {
+	if err := s.notificationRepo.MarkRead(workspaceID, userID, id); err != nil {
+		return fmt.Errorf("notification not found: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every notification of a user as read and returns how many were unread
+func (s *NotificationService) MarkAllRead(workspaceID string, userID string) (int, error) {
+	return s.notificationRepo.MarkAllRead(workspaceID, userID)
+}
+
+// GetSettings retrieves the notification types a user muted
+func (s *NotificationService) GetSettings(workspaceID string, userID string) (*models.NotificationSettings, error) {
+	muted, err := s.notificationRepo.GetMutedTypes(workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationSettings{MutedTypes: muted}, nil
+}
+
+// UpdateSettings replaces the notification types a user muted. Muting a type only stops new
+// notifications; those already in the inbox stay
+func (s *NotificationService) UpdateSettings(workspaceID string, userID string, req models.NotificationSettings) (*models.NotificationSettings, error) {
+	muted := make([]models.NotificationType, 0, len(req.MutedTypes))
+	for _, notificationType := range req.MutedTypes {
+		if !notificationType.IsValid() {
+			return nil, fmt.Errorf("%w: unknown notification type %q", ErrInvalidInput, notificationType)
+		}
+		if !slices.Contains(muted, notificationType) {
+			muted = append(muted, notificationType)
+		}
+	}
+
+	if err := s.notificationRepo.SetMutedTypes(workspaceID, userID, muted); err != nil {
+		return nil, err
+	}
+
+	return s.GetSettings(workspaceID, userID)
+}
+
+// notifyWatchers tells the watchers of a task, other than the actor, about a change in the audit log.
+// Creating a task notifies nobody, since only its assignees watch it and they are told about the assignment.
+// The change is already stored, so a failure is logged rather than returned
+func notifyWatchers(notificationRepo *repository.NotificationRepository, activity *models.Activity) {
+	notificationType := models.NotifySubTaskChanged
+	if activity.Entity == models.EntityTask {
+		switch activity.Action {
+		case models.ActionUpdated:
+			notificationType = models.NotifyTaskUpdated
+		case models.ActionDeleted:
+			notificationType = models.NotifyTaskDeleted
+		case models.ActionRestored:
+			notificationType = models.NotifyTaskRestored
+		default:
+			return
+		}
+	}
+
+	notification := models.NewNotification(activity.WorkspaceID, activity.TaskID, notificationType, "")
+	notification.ActorID = activity.ActorID
+	notification.SubTaskID = activity.SubTaskID
+	notification.Action = activity.Action
+	notification.Changes = activity.Changes
+	if _, err := notificationRepo.NotifyWatchers(notification); err != nil {
+		log.Printf("Warning: failed to notify watchers of task %s: %v", activity.TaskID, err)
+	}
+}
+
+// notifyAssignees tells users assigned to a task, other than the actor, about the assignment.
+// The assignment is already stored, so a failure is logged rather than returned
+func notifyAssignees(notificationRepo *repository.NotificationRepository, workspaceID string, taskID string, actorID string, users []models.User) {
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	notification := models.NewNotification(workspaceID, taskID, models.NotifyTaskAssigned, actorID)
+	if _, err := notificationRepo.NotifyUsers(notification, userIDs); err != nil {
+		log.Printf("Warning: failed to notify assignees of task %s: %v", taskID, err)
+	}
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// ProjectService handles business logic for projects
+type ProjectService struct {
+	projectRepo     *repository.ProjectRepository
+	taskRepo        *repository.TaskRepository
+	customFieldRepo *repository.CustomFieldRepository
+}
+
+// NewProjectService creates a new instance of ProjectService
+func NewProjectService(projectRepo *repository.ProjectRepository, taskRepo *repository.TaskRepository, customFieldRepo *repository.CustomFieldRepository) *ProjectService {
+	return &ProjectService{
+		projectRepo:     projectRepo,
+		taskRepo:        taskRepo,
+		customFieldRepo: customFieldRepo,
+	}
+}
+
+func (s *ProjectService) CreateProject(workspaceID string, req models.CreateProjectRequest) (*models.Project, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+	}
+
+	project := models.NewProject(workspaceID, req)
+	if err := s.projectRepo.CreateProject(project); err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	return s.projectRepo.GetProjectByID(workspaceID, project.ID)
+}
+
+func (s *ProjectService) GetProject(workspaceID string, id string) (*models.Project, error) {
+	return s.projectRepo.GetProjectByID(workspaceID, id)
+}
+
+func (s *ProjectService) GetProjects(workspaceID string) ([]models.Project, error) {
+	return s.projectRepo.GetProjects(workspaceID)
+}
+
+func (s *ProjectService) UpdateProject(workspaceID string, id string, req models.UpdateProjectRequest) (*models.Project, error) {
+	_, err := s.projectRepo.GetProjectByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+
+	if err := s.projectRepo.UpdateProject(workspaceID, id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update project: %w", err)
+	}
+
+	return s.projectRepo.GetProjectByID(workspaceID, id)
+}
+
+// DeleteProject removes a project. Its tasks stay and no longer belong to any project
+func (s *ProjectService) DeleteProject(workspaceID string, id string) error {
+	_, err := s.projectRepo.GetProjectByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("project not found: %w", err)
+	}
+
+	return s.projectRepo.DeleteProject(workspaceID, id)
+}
+
+// GetProjectTasks lists the tasks of a project using the regular task filters
+func (s *ProjectService) GetProjectTasks(workspaceID string, id string, filters models.TaskFilters) ([]models.Task, error) {
+	_, err := s.projectRepo.GetProjectByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+
+	filters.ProjectID = id
+	if err := resolveCustomFieldFilters(s.customFieldRepo, workspaceID, &filters); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetTasks(workspaceID, filters)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// reminderBatchSize caps how many reminders one query claims
+const reminderBatchSize = 100
+
+// overdueReminderWindow is how long after its due date a task still gets the overdue reminder,
+// so turning reminders on does not remind watchers of every task that has long been overdue
+const overdueReminderWindow = 7 * 24 * time.Hour
+
+// ReminderOptions configures which due-date reminders are sent
+type ReminderOptions struct {
+	// Offsets are how long before the due date reminders fire
+	Offsets []time.Duration
+	// Overdue sends a reminder when a task passes its due date
+	Overdue bool
+}
+
+// ReminderService sends due-date reminders of open tasks to their watchers
+type ReminderService struct {
+	reminderRepo     *repository.ReminderRepository
+	notificationRepo *repository.NotificationRepository
+	offsets          []time.Duration
+	overdue          bool
+}
+
+// NewReminderService creates a new instance of ReminderService.
+// Offsets that are not whole positive minutes are ignored
+func NewReminderService(reminderRepo *repository.ReminderRepository, notificationRepo *repository.NotificationRepository, options ReminderOptions) *ReminderService {
+	var offsets []time.Duration
+	for _, offset := range options.Offsets {
+		offset = offset.Truncate(time.Minute)
+		if offset > 0 && !slices.Contains(offsets, offset) {
+			offsets = append(offsets, offset)
+		}
+	}
+	// Longest first, so each offset's window ends where the next one's begins
+	slices.SortFunc(offsets, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+
+	return &ReminderService{
+		reminderRepo:     reminderRepo,
+		notificationRepo: notificationRepo,
+		offsets:          offsets,
+		overdue:          options.Overdue,
+	}
+}
+
+// RunReminders sends due reminders every interval until ctx is done
+func (s *ReminderService) RunReminders(ctx context.Context, interval time.Duration) {
+	if len(s.offsets) == 0 && !s.overdue {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SendDueReminders(time.Now()); err != nil {
+			log.Printf("Warning: reminder sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDueReminders notifies the watchers of every open task that entered a reminder window by now.
+// A task gets the reminder of the shortest offset it is within, so a task created an hour before
+// its due date is not also reminded a day ahead. Reminders are recorded per due date, so moving
+// the due date sends them again. Safe to run concurrently from several replicas
+func (s *ReminderService) SendDueReminders(now time.Time) error {
+	for i, offset := range s.offsets {
+		var next time.Duration
+		if i+1 < len(s.offsets) {
+			next = s.offsets[i+1]
+		}
+		if err := s.sendReminders(offset, now.Add(next), now.Add(offset)); err != nil {
+			return err
+		}
+	}
+
+	if s.overdue {
+		if err := s.sendReminders(0, now.Add(-overdueReminderWindow), now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendReminders claims the reminders at offset of tasks due after from and at or before to,
+// batch by batch, and notifies the watchers of each task
+func (s *ReminderService) sendReminders(offset time.Duration, from time.Time, to time.Time) error {
+	for {
+		reminders, err := s.reminderRepo.ClaimReminders(offset, from, to, reminderBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to claim reminders: %w", err)
+		}
+
+		for _, reminder := range reminders {
+			notificationType := models.NotifyTaskDueSoon
+			if reminder.Offset == 0 {
+				notificationType = models.NotifyTaskOverdue
+			}
+
+			notification := models.NewNotification(reminder.WorkspaceID, reminder.TaskID, notificationType, "")
+			notification.DueDate = &reminder.DueDate
+			// The reminder is already recorded as sent, so a failure is not retried
+			if _, err := s.notificationRepo.NotifyWatchers(notification); err != nil {
+				log.Printf("Warning: failed to send reminder for task %s: %v", reminder.TaskID, err)
+			}
+		}
+
+		if len(reminders) < reminderBatchSize {
+			return nil
+		}
+	}
+}
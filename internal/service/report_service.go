@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const (
+	defaultBurndownDays = 14
+	maxBurndownDays     = 366
+)
+
+// ReportService handles business logic for reports over the tasks of a workspace
+type ReportService struct {
+	reportRepo      *repository.ReportRepository
+	customFieldRepo *repository.CustomFieldRepository
+}
+
+// NewReportService creates a new instance of ReportService
+func NewReportService(reportRepo *repository.ReportRepository, customFieldRepo *repository.CustomFieldRepository) *ReportService {
+	return &ReportService{
+		reportRepo:      reportRepo,
+		customFieldRepo: customFieldRepo,
+	}
+}
+
+// GetBurndown computes the daily remaining effort of the tasks matching filters between two dates,
+// inclusive. Days are UTC days; the ideal line falls evenly from the first day's effort to zero
+func (s *ReportService) GetBurndown(workspaceID string, filters models.TaskFilters, period models.BurndownFilters) (*models.BurndownReport, error) {
+	from, to, err := parseBurndownPeriod(period)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := resolveCustomFieldFilters(s.customFieldRepo, workspaceID, &filters); err != nil {
+		return nil, err
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	tasks, burndown, err := s.reportRepo.GetBurndown(workspaceID, filters, from, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(burndown) > 0 {
+		startHours, startPoints := burndown[0].RemainingHours, float64(burndown[0].RemainingPoints)
+		for i := range burndown {
+			left := 1.0
+			if days > 1 {
+				left = 1 - float64(i)/float64(days-1)
+			}
+			burndown[i].IdealHours = startHours * left
+			burndown[i].IdealPoints = startPoints * left
+		}
+	}
+
+	return &models.BurndownReport{
+		From:  from.Format(time.DateOnly),
+		To:    to.Format(time.DateOnly),
+		Tasks: tasks,
+		Days:  burndown,
+	}, nil
+}
+
+// parseBurndownPeriod parses the report dates, defaulting to the last defaultBurndownDays days
+func parseBurndownPeriod(period models.BurndownFilters) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if period.To != "" {
+		parsed, err := time.Parse(time.DateOnly, period.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: to must be a date in YYYY-MM-DD format", ErrInvalidInput)
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, 1-defaultBurndownDays)
+	if period.From != "" {
+		parsed, err := time.Parse(time.DateOnly, period.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: from must be a date in YYYY-MM-DD format", ErrInvalidInput)
+		}
+		from = parsed
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: to must not be before from", ErrInvalidInput)
+	}
+	if to.Sub(from).Hours()/24 >= maxBurndownDays {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: a burndown can cover at most %d days", ErrInvalidInput, maxBurndownDays)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,612 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// RevisionService handles business logic for task revisions. Restoring a revision
+// writes through TaskService, so it is subject to the same validation as any other edit
+type RevisionService struct {
+	revisionRepo *repository.RevisionRepository
+	taskService  *TaskService
+}
+
+// NewRevisionService creates a new instance of RevisionService
+func NewRevisionService(revisionRepo *repository.RevisionRepository, taskService *TaskService) *RevisionService {
+	return &RevisionService{revisionRepo: revisionRepo, taskService: taskService}
+}
+
+// GetRevisions retrieves the revisions of a task, newest first
+func (s *RevisionService) GetRevisions(workspaceID string, taskID string) ([]models.TaskRevision, error) {
+	return s.revisionRepo.GetRevisions(workspaceID, taskID)
+}
+
+// GetRevision retrieves one revision of a task with its snapshot
+func (s *RevisionService) GetRevision(workspaceID string, taskID string, number int) (*models.TaskRevision, error) {
+	return s.revisionRepo.GetRevision(workspaceID, taskID, number)
+}
+
+// DiffRevisions lists the changes from revision from to revision to of a task.
+// A from of 0 compares against nothing, so every field of revision to shows up as added
+func (s *RevisionService) DiffRevisions(workspaceID string, taskID string, from int, to int) (*models.RevisionDiff, error) {
+	if from < 0 {
+		return nil, fmt.Errorf("%w: from must not be negative", ErrInvalidInput)
+	}
+
+	target, err := s.revisionRepo.GetRevision(workspaceID, taskID, to)
+	if err != nil {
+		return nil, fmt.Errorf("revision %d not found: %w", to, err)
+	}
+
+	var base *models.Task
+	if from > 0 {
+		revision, err := s.revisionRepo.GetRevision(workspaceID, taskID, from)
+		if err != nil {
+			return nil, fmt.Errorf("revision %d not found: %w", from, err)
+		}
+		base = revision.Snapshot
+	}
+
+	diff := diffSnapshots(base, target.Snapshot)
+	diff.TaskID = taskID
+	diff.From = from
+	diff.To = to
+	return diff, nil
+}
+
+// RestoreRevision brings a task and its subtasks back to the state of a revision and stores the
+// result as a new revision. Assignees and labels are set back, the task's fields are set with an
+// ordinary update; subtasks missing from the revision go to the trash, and subtasks missing from
+// the task are restored from the trash or, once purged, created anew. Recurrence is left as it is.
+// Every step is validated before the first write, so a revision that can no longer be applied,
+// for example because its sprint was closed, leaves the task untouched
+func (s *RevisionService) RestoreRevision(workspaceID string, actorID string, taskID string, number int) (*models.Task, error) {
+	revision, err := s.revisionRepo.GetRevision(workspaceID, taskID, number)
+	if err != nil {
+		return nil, fmt.Errorf("revision not found: %w", err)
+	}
+
+	current, err := s.taskService.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: the task is in the trash or does not exist", sql.ErrNoRows)
+		}
+		return nil, err
+	}
+
+	if err := s.checkRestore(workspaceID, current, revision.Snapshot); err != nil {
+		return nil, err
+	}
+
+	restoreErr := s.restoreSnapshot(workspaceID, actorID, current, revision.Snapshot)
+
+	restored, err := newTaskRevision(s.taskService.taskRepo, workspaceID, taskID, actorID)
+	if err != nil {
+		log.Printf("Warning: failed to snapshot task %s: %v", taskID, err)
+	} else {
+		restored.RestoredFrom = &number
+		if err := s.revisionRepo.CreateRevision(restored); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+
+	return s.taskService.taskRepo.GetTaskByID(workspaceID, taskID)
+}
+
+// checkRestore runs the validation of every write restoreSnapshot would make, without writing anything
+func (s *RevisionService) checkRestore(workspaceID string, current *models.Task, snapshot *models.Task) error {
+	assign, _ := diffUsers(current.Users, snapshot.Users)
+	if _, err := resolveUsers(s.taskService.userRepo, userIDs(assign)); err != nil {
+		return err
+	}
+	attach, _ := diffLabels(current.Labels, snapshot.Labels)
+	if _, err := resolveLabels(s.taskService.labelRepo, workspaceID, labelIDs(attach)); err != nil {
+		return err
+	}
+
+	if req, changed := taskRestoreRequest(current, snapshot); changed {
+		if _, _, err := s.taskService.checkTaskUpdate(workspaceID, current, &req); err != nil {
+			return err
+		}
+	}
+
+	workflow, err := s.taskService.workflowRepo.GetWorkflow(workspaceID)
+	if err != nil {
+		return err
+	}
+
+	live := make(map[string]*models.SubTask, len(current.SubTasks))
+	for i := range current.SubTasks {
+		live[current.SubTasks[i].ID] = &current.SubTasks[i]
+	}
+	parents := make(map[string]bool)
+	for _, subTask := range snapshot.SubTasks {
+		if subTask.ParentSubTaskID != nil {
+			parents[*subTask.ParentSubTaskID] = true
+		}
+	}
+
+	// back holds the subtasks that exist once the steps so far are applied
+	back := make(map[string]bool, len(snapshot.SubTasks))
+	for id := range live {
+		back[id] = true
+	}
+	for i := range snapshot.SubTasks {
+		want := &snapshot.SubTasks[i]
+		if want.ParentSubTaskID != nil && !back[*want.ParentSubTaskID] {
+			return fmt.Errorf("%w: parent subtask %s of subtask %s cannot be restored", ErrConflict, *want.ParentSubTaskID, want.ID)
+		}
+
+		have, ok := live[want.ID]
+		if !ok {
+			deleted, err := s.taskService.subTaskRepo.GetDeletedSubTaskByID(workspaceID, want.ID)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if deleted != nil && deleted.TaskID == current.ID {
+				if deleted.ParentSubTaskID != nil && !back[*deleted.ParentSubTaskID] {
+					return fmt.Errorf("%w: parent subtask %s of subtask %s cannot be restored", ErrConflict, *deleted.ParentSubTaskID, want.ID)
+				}
+				have = deleted
+			}
+		}
+		back[want.ID] = true
+
+		if have == nil {
+			if err := checkEffort(want.EstimateHours, want.StoryPoints, want.RemainingHours); err != nil {
+				return err
+			}
+			if _, err := checkStatus(workflow, want.Status); err != nil {
+				return err
+			}
+			continue
+		}
+
+		req, changed := subTaskRestoreRequest(have, want, !parents[want.ID])
+		if !changed {
+			continue
+		}
+		if err := checkEffort(req.EstimateHours, req.StoryPoints, req.RemainingHours); err != nil {
+			return err
+		}
+		if req.Status != nil {
+			if err := checkTransition(workflow, have.Status, *req.Status); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// restoreSnapshot applies snapshot to the task current and its subtasks. It expects checkRestore
+// to have passed, so only a failing write can stop it midway
+func (s *RevisionService) restoreSnapshot(workspaceID string, actorID string, current *models.Task, snapshot *models.Task) error {
+	if err := s.restoreAssignments(workspaceID, actorID, current, snapshot); err != nil {
+		return err
+	}
+
+	if req, changed := taskRestoreRequest(current, snapshot); changed {
+		if _, err := s.taskService.updateTask(workspaceID, actorID, current.ID, req); err != nil {
+			return err
+		}
+	}
+
+	live := make(map[string]*models.SubTask, len(current.SubTasks))
+	for i := range current.SubTasks {
+		live[current.SubTasks[i].ID] = &current.SubTasks[i]
+	}
+
+	// The status of a parent follows its children, so it is left to the roll-up
+	parents := make(map[string]bool)
+	for _, subTask := range snapshot.SubTasks {
+		if subTask.ParentSubTaskID != nil {
+			parents[*subTask.ParentSubTaskID] = true
+		}
+	}
+
+	// Snapshots list parents before their children, so a parent is always back before its subtasks.
+	// ids maps subtask IDs of the snapshot to the IDs of subtasks that had to be created again
+	ids := make(map[string]string, len(snapshot.SubTasks))
+	for i := range snapshot.SubTasks {
+		want := &snapshot.SubTasks[i]
+		ids[want.ID] = want.ID
+
+		have, ok := live[want.ID]
+		if !ok {
+			var err error
+			if have, err = s.recoverSubTask(workspaceID, actorID, current.ID, want, ids); err != nil {
+				return err
+			}
+			ids[want.ID] = have.ID
+		}
+
+		if req, changed := subTaskRestoreRequest(have, want, !parents[want.ID]); changed {
+			if _, err := s.taskService.updateSubTask(workspaceID, actorID, have.ID, req); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Subtasks restored from the trash may bring back descendants the revision did not have
+	subTasks, err := s.taskService.subTaskRepo.GetSubTasksByTaskID(workspaceID, current.ID)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	for _, subTask := range subTasks {
+		if wanted[subTask.ID] {
+			continue
+		}
+		// Deleting a parent already took its descendants to the trash
+		if _, err := s.taskService.deleteSubTask(workspaceID, actorID, subTask.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return s.restoreSubTaskOrders(workspaceID, actorID, current.ID, snapshot.SubTasks, ids)
+}
+
+// restoreAssignments sets the assignees and labels of the task current back to those of snapshot,
+// recorded as one change. Users assigned again are notified like on any assignment
+func (s *RevisionService) restoreAssignments(workspaceID string, actorID string, current *models.Task, snapshot *models.Task) error {
+	assign, unassign := diffUsers(current.Users, snapshot.Users)
+	attach, detach := diffLabels(current.Labels, snapshot.Labels)
+	if len(assign)+len(unassign)+len(attach)+len(detach) == 0 {
+		return nil
+	}
+
+	taskRepo, labelRepo := s.taskService.taskRepo, s.taskService.labelRepo
+	for _, user := range assign {
+		if err := taskRepo.AssignUser(workspaceID, current.ID, user.ID); err != nil {
+			return fmt.Errorf("failed to assign user: %w", err)
+		}
+	}
+	for _, user := range unassign {
+		if err := taskRepo.UnassignUser(workspaceID, current.ID, user.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to unassign user: %w", err)
+		}
+	}
+	for _, label := range attach {
+		if err := labelRepo.AssignLabel(workspaceID, current.ID, label.ID); err != nil {
+			return fmt.Errorf("failed to assign label: %w", err)
+		}
+	}
+	for _, label := range detach {
+		if err := labelRepo.UnassignLabel(workspaceID, current.ID, label.ID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to unassign label: %w", err)
+		}
+	}
+
+	if len(assign) > 0 {
+		notifyAssignees(s.taskService.notificationRepo, workspaceID, current.ID, actorID, assign)
+	}
+	_, err := s.taskService.recordTaskChange(workspaceID, actorID, current)
+	return err
+}
+
+// diffUsers returns the users of want missing from have, and the users of have missing from want
+func diffUsers(have []models.User, want []models.User) (added []models.User, removed []models.User) {
+	hasID := func(users []models.User, id string) bool {
+		return slices.ContainsFunc(users, func(user models.User) bool { return user.ID == id })
+	}
+	for _, user := range want {
+		if !hasID(have, user.ID) {
+			added = append(added, user)
+		}
+	}
+	for _, user := range have {
+		if !hasID(want, user.ID) {
+			removed = append(removed, user)
+		}
+	}
+	return added, removed
+}
+
+// diffLabels returns the labels of want missing from have, and the labels of have missing from want
+func diffLabels(have []models.Label, want []models.Label) (added []models.Label, removed []models.Label) {
+	hasID := func(labels []models.Label, id string) bool {
+		return slices.ContainsFunc(labels, func(label models.Label) bool { return label.ID == id })
+	}
+	for _, label := range want {
+		if !hasID(have, label.ID) {
+			added = append(added, label)
+		}
+	}
+	for _, label := range have {
+		if !hasID(want, label.ID) {
+			removed = append(removed, label)
+		}
+	}
+	return added, removed
+}
+
+func userIDs(users []models.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func labelIDs(labels []models.Label) []string {
+	ids := make([]string, 0, len(labels))
+	for _, label := range labels {
+		ids = append(ids, label.ID)
+	}
+	return ids
+}
+
+// recoverSubTask brings back a subtask of the snapshot that the task did not have: it may already be
+// back with a restored parent, else it comes from the trash or is created anew under the recovered parent
+func (s *RevisionService) recoverSubTask(workspaceID string, actorID string, taskID string, want *models.SubTask, ids map[string]string) (*models.SubTask, error) {
+	subTask, err := s.taskService.subTaskRepo.GetSubTaskByID(workspaceID, want.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if subTask != nil && subTask.TaskID == taskID {
+		return subTask, nil
+	}
+
+	deleted, err := s.taskService.subTaskRepo.GetDeletedSubTaskByID(workspaceID, want.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if deleted != nil && deleted.TaskID == taskID {
+		return s.taskService.restoreSubTask(workspaceID, actorID, taskID, want.ID)
+	}
+
+	req := models.CreateSubTaskRequest{
+		Title:          want.Title,
+		Description:    want.Description,
+		Status:         want.Status,
+		EstimateHours:  want.EstimateHours,
+		StoryPoints:    want.StoryPoints,
+		RemainingHours: want.RemainingHours,
+	}
+	if want.ParentSubTaskID != nil {
+		parentID := ids[*want.ParentSubTaskID]
+		req.ParentSubTaskID = &parentID
+	}
+	return s.taskService.createSubTask(workspaceID, actorID, taskID, req)
+}
+
+// restoreSubTaskOrders puts the subtasks back in the order they had in the snapshot
+func (s *RevisionService) restoreSubTaskOrders(workspaceID string, actorID string, taskID string, wanted []models.SubTask, ids map[string]string) error {
+	subTasks, err := s.taskService.subTaskRepo.GetSubTasksByTaskID(workspaceID, taskID)
+	if err != nil {
+		return err
+	}
+	orders := make(map[string]int, len(subTasks))
+	for _, subTask := range subTasks {
+		orders[subTask.ID] = subTask.Order
+	}
+
+	changed := make(map[string]int)
+	for _, want := range wanted {
+		id := ids[want.ID]
+		if order, ok := orders[id]; ok && order != want.Order {
+			changed[id] = want.Order
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	if err := s.taskService.subTaskRepo.SetSubTaskOrders(workspaceID, taskID, changed); err != nil {
+		return err
+	}
+	for id, order := range changed {
+		subTaskID := id
+		s.taskService.recordChange(models.NewActivity(workspaceID, taskID, &subTaskID, actorID, models.ActionReordered,
+			[]models.FieldChange{{Field: "order", Old: orders[id], New: order}}))
+	}
+
+	return nil
+}
+
+// taskRestoreRequest builds the update that turns current back into snapshot. Only differing fields
+// are set, so validation only applies to what actually changes. Optional fields unset in the
+// snapshot are cleared where the API allows it; estimates and story points cannot be cleared
+func taskRestoreRequest(current *models.Task, snapshot *models.Task) (models.UpdateTaskRequest, bool) {
+	var req models.UpdateTaskRequest
+	changed := false
+
+	clearable := func(have *string, want *string) *string {
+		if sameAuditValue(deref(have), deref(want)) {
+			return nil
+		}
+		changed = true
+		value := ""
+		if want != nil {
+			value = *want
+		}
+		return &value
+	}
+	req.ProjectID = clearable(current.ProjectID, snapshot.ProjectID)
+	req.SprintID = clearable(current.SprintID, snapshot.SprintID)
+	req.MilestoneID = clearable(current.MilestoneID, snapshot.MilestoneID)
+
+	if current.Title != snapshot.Title {
+		req.Title, changed = &snapshot.Title, true
+	}
+	if current.IconName != snapshot.IconName {
+		req.IconName, changed = &snapshot.IconName, true
+	}
+	if !current.DueDate.Equal(snapshot.DueDate) {
+		req.DueDate, changed = &snapshot.DueDate, true
+	}
+	if !sameAuditValue(deref(current.StartTime), deref(snapshot.StartTime)) ||
+		!sameAuditValue(deref(current.EndTime), deref(snapshot.EndTime)) {
+		changed = true
+		if snapshot.StartTime == nil && snapshot.EndTime == nil {
+			req.ClearSchedule = true
+		} else {
+			req.StartTime, req.EndTime = snapshot.StartTime, snapshot.EndTime
+		}
+	}
+	if current.Progress != snapshot.Progress {
+		req.Progress, changed = &snapshot.Progress, true
+	}
+	if current.Status != snapshot.Status {
+		req.Status, changed = &snapshot.Status, true
+	}
+	if current.Priority != snapshot.Priority {
+		req.Priority, changed = &snapshot.Priority, true
+	}
+	if snapshot.EstimateHours != nil && !sameAuditValue(deref(current.EstimateHours), *snapshot.EstimateHours) {
+		req.EstimateHours, changed = snapshot.EstimateHours, true
+	}
+	if snapshot.StoryPoints != nil && !sameAuditValue(deref(current.StoryPoints), *snapshot.StoryPoints) {
+		req.StoryPoints, changed = snapshot.StoryPoints, true
+	}
+	if snapshot.RemainingHours != nil && !sameAuditValue(deref(current.RemainingHours), *snapshot.RemainingHours) {
+		req.RemainingHours, changed = snapshot.RemainingHours, true
+	}
+
+	customFields := models.CustomFieldValues{}
+	for key, value := range snapshot.CustomFields {
+		if !sameAuditValue(current.CustomFields[key], value) {
+			customFields[key] = value
+		}
+	}
+	for key := range current.CustomFields {
+		if _, ok := snapshot.CustomFields[key]; !ok {
+			customFields[key] = nil
+		}
+	}
+	if len(customFields) > 0 {
+		req.CustomFields, changed = &customFields, true
+	}
+
+	return req, changed
+}
+
+// subTaskRestoreRequest builds the update that turns have back into want, setting only differing fields.
+// The status is only included with withStatus
+func subTaskRestoreRequest(have *models.SubTask, want *models.SubTask, withStatus bool) (models.UpdateSubTaskRequest, bool) {
+	var req models.UpdateSubTaskRequest
+	changed := false
+
+	if have.Title != want.Title {
+		req.Title, changed = &want.Title, true
+	}
+	if want.Description != nil && !sameAuditValue(deref(have.Description), *want.Description) {
+		req.Description, changed = want.Description, true
+	}
+	if withStatus && have.Status != want.Status {
+		req.Status, changed = &want.Status, true
+	}
+	if want.EstimateHours != nil && !sameAuditValue(deref(have.EstimateHours), *want.EstimateHours) {
+		req.EstimateHours, changed = want.EstimateHours, true
+	}
+	if want.StoryPoints != nil && !sameAuditValue(deref(have.StoryPoints), *want.StoryPoints) {
+		req.StoryPoints, changed = want.StoryPoints, true
+	}
+	if want.RemainingHours != nil && !sameAuditValue(deref(have.RemainingHours), *want.RemainingHours) {
+		req.RemainingHours, changed = want.RemainingHours, true
+	}
+
+	return req, changed
+}
+
+// diffSnapshots compares two snapshots of a task; a nil from describes a task that did not exist yet
+func diffSnapshots(from *models.Task, to *models.Task) *models.RevisionDiff {
+	var before []auditField
+	var beforeSubTasks []models.SubTask
+	if from != nil {
+		before = taskAuditFields(from)
+		beforeSubTasks = from.SubTasks
+	}
+
+	diff := &models.RevisionDiff{
+		Changes:  diffAuditFields(before, taskAuditFields(to)),
+		SubTasks: []models.SubTaskDiff{},
+	}
+
+	previous := make(map[string]*models.SubTask, len(beforeSubTasks))
+	for i := range beforeSubTasks {
+		previous[beforeSubTasks[i].ID] = &beforeSubTasks[i]
+	}
+	for i := range to.SubTasks {
+		subTask := &to.SubTasks[i]
+		old, ok := previous[subTask.ID]
+		delete(previous, subTask.ID)
+		if !ok {
+			diff.SubTasks = append(diff.SubTasks, models.SubTaskDiff{SubTaskID: subTask.ID, Title: subTask.Title, Change: "added",
+				Changes: diffAuditFields(nil, subTaskAuditFields(subTask))})
+			continue
+		}
+		if changes := diffAuditFields(subTaskAuditFields(old), subTaskAuditFields(subTask)); len(changes) > 0 {
+			diff.SubTasks = append(diff.SubTasks, models.SubTaskDiff{SubTaskID: subTask.ID, Title: subTask.Title, Change: "updated",
+				Changes: changes})
+		}
+	}
+	for _, subTask := range beforeSubTasks {
+		if _, removed := previous[subTask.ID]; removed {
+			diff.SubTasks = append(diff.SubTasks, models.SubTaskDiff{SubTaskID: subTask.ID, Title: subTask.Title, Change: "removed",
+				Changes: diffAuditFields(subTaskAuditFields(&subTask), nil)})
+		}
+	}
+
+	return diff
+}
+
+// recordRevision stores a revision of the task as it is now. The writes it follows are already
+// stored, so a failure is logged rather than returned
+func recordRevision(taskRepo *repository.TaskRepository, revisionRepo *repository.RevisionRepository, workspaceID string, taskID string, actorID string) {
+	revision, err := newTaskRevision(taskRepo, workspaceID, taskID, actorID)
+	if err != nil {
+		log.Printf("Warning: failed to snapshot task %s: %v", taskID, err)
+		return
+	}
+	if err := revisionRepo.CreateRevision(revision); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// newTaskRevision snapshots a task with its subtasks in tree order
+func newTaskRevision(taskRepo *repository.TaskRepository, workspaceID string, taskID string, actorID string) (*models.TaskRevision, error) {
+	task, err := taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, err
+	}
+	task.SubTasks = flattenSubTaskTree(task.SubTasks)
+
+	return models.NewTaskRevision(workspaceID, taskID, actorID, task), nil
+}
+
+// flattenSubTaskTree orders subtasks depth first, each level by its order field,
+// so that every parent comes before its children
+func flattenSubTaskTree(subTasks []models.SubTask) []models.SubTask {
+	sorted := slices.Clone(subTasks)
+	slices.SortStableFunc(sorted, func(a, b models.SubTask) int { return a.Order - b.Order })
+
+	flat := make([]models.SubTask, 0, len(sorted))
+	var walk func(level []models.SubTask)
+	walk = func(level []models.SubTask) {
+		for _, subTask := range level {
+			children := subTask.Children
+			subTask.Children = nil
+			flat = append(flat, subTask)
+			walk(children)
+		}
+	}
+	walk(buildSubTaskTree(sorted))
+
+	return flat
+}
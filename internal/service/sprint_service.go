@@ -0,0 +1,218 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// SprintService handles business logic for sprints
+type SprintService struct {
+	sprintRepo       *repository.SprintRepository
+	projectRepo      *repository.ProjectRepository
+	taskRepo         *repository.TaskRepository
+	customFieldRepo  *repository.CustomFieldRepository
+	activityRepo     *repository.ActivityRepository
+	revisionRepo     *repository.RevisionRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewSprintService creates a new instance of SprintService
+func NewSprintService(sprintRepo *repository.SprintRepository, projectRepo *repository.ProjectRepository, taskRepo *repository.TaskRepository, customFieldRepo *repository.CustomFieldRepository, activityRepo *repository.ActivityRepository, revisionRepo *repository.RevisionRepository, notificationRepo *repository.NotificationRepository) *SprintService {
+	return &SprintService{
+		sprintRepo:       sprintRepo,
+		projectRepo:      projectRepo,
+		taskRepo:         taskRepo,
+		customFieldRepo:  customFieldRepo,
+		activityRepo:     activityRepo,
+		revisionRepo:     revisionRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *SprintService) CreateSprint(workspaceID string, req models.CreateSprintRequest) (*models.Sprint, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+	}
+	if err := checkSprintDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+	if req.ProjectID != nil {
+		if err := checkProjectExists(s.projectRepo, workspaceID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	sprint := models.NewSprint(workspaceID, req)
+	if err := s.sprintRepo.CreateSprint(sprint); err != nil {
+		return nil, fmt.Errorf("failed to create sprint: %w", err)
+	}
+
+	return s.sprintRepo.GetSprintByID(workspaceID, sprint.ID)
+}
+
+func (s *SprintService) GetSprint(workspaceID string, id string) (*models.Sprint, error) {
+	return s.sprintRepo.GetSprintByID(workspaceID, id)
+}
+
+func (s *SprintService) GetSprints(workspaceID string, filters models.SprintFilters) ([]models.Sprint, error) {
+	if filters.State != "" && !filters.State.IsValid() {
+		return nil, fmt.Errorf("%w: unknown sprint state %q", ErrInvalidInput, filters.State)
+	}
+	return s.sprintRepo.GetSprints(workspaceID, filters)
+}
+
+func (s *SprintService) UpdateSprint(workspaceID string, id string, req models.UpdateSprintRequest) (*models.Sprint, error) {
+	sprint, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+
+	if req.StartDate != nil || req.EndDate != nil {
+		if sprint.State == models.SprintClosed {
+			return nil, fmt.Errorf("%w: the dates of a closed sprint cannot be changed", ErrConflict)
+		}
+		start, end := sprint.StartDate, sprint.EndDate
+		if req.StartDate != nil {
+			start = *req.StartDate
+		}
+		if req.EndDate != nil {
+			end = *req.EndDate
+		}
+		if err := checkSprintDates(start, end); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.sprintRepo.UpdateSprint(workspaceID, id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update sprint: %w", err)
+	}
+
+	return s.sprintRepo.GetSprintByID(workspaceID, id)
+}
+
+// DeleteSprint removes a sprint. Its tasks stay and go back to the backlog
+func (s *SprintService) DeleteSprint(workspaceID string, id string) error {
+	_, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("sprint not found: %w", err)
+	}
+
+	return s.sprintRepo.DeleteSprint(workspaceID, id)
+}
+
+// StartSprint makes a planned sprint the active sprint of its project
+func (s *SprintService) StartSprint(workspaceID string, id string) (*models.Sprint, error) {
+	sprint, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found: %w", err)
+	}
+	if sprint.State != models.SprintPlanned {
+		return nil, fmt.Errorf("%w: sprint is %s, only planned sprints can be started", ErrConflict, sprint.State)
+	}
+
+	err = s.sprintRepo.StartSprint(workspaceID, id, time.Now())
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, fmt.Errorf("%w: there is already an active sprint", ErrConflict)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		// The sprint was started or deleted concurrently
+		return nil, fmt.Errorf("%w: sprint is no longer planned", ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start sprint: %w", err)
+	}
+
+	return s.sprintRepo.GetSprintByID(workspaceID, id)
+}
+
+// CloseSprint closes the active sprint and carries its unfinished tasks over.
+// Without an explicit target they move to the next planned sprint of the same project,
+// or to the backlog when there is none. Every task carried over gets a sprint_id change in the audit log
+// and a new revision
+func (s *SprintService) CloseSprint(workspaceID string, actorID string, id string, req models.CloseSprintRequest) (*models.CloseSprintResponse, error) {
+	sprint, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found: %w", err)
+	}
+	if sprint.State != models.SprintActive {
+		return nil, fmt.Errorf("%w: sprint is %s, only the active sprint can be closed", ErrConflict, sprint.State)
+	}
+
+	var carryOverTo *string
+	switch {
+	case req.CarryOverTo == nil:
+		next, err := s.sprintRepo.GetNextPlannedSprint(workspaceID, sprint.ProjectID, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if next != nil {
+			carryOverTo = &next.ID
+		}
+	case *req.CarryOverTo != "":
+		if *req.CarryOverTo == id {
+			return nil, fmt.Errorf("%w: tasks cannot be carried over to the sprint being closed", ErrInvalidInput)
+		}
+		if err := checkSprintOpen(s.sprintRepo, workspaceID, *req.CarryOverTo); err != nil {
+			return nil, err
+		}
+		carryOverTo = req.CarryOverTo
+	}
+
+	carried, err := s.sprintRepo.CloseSprint(workspaceID, id, carryOverTo, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: sprint is no longer active", ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to close sprint: %w", err)
+	}
+	for _, taskID := range carried {
+		activity := models.NewActivity(workspaceID, taskID, nil, actorID, models.ActionUpdated,
+			[]models.FieldChange{{Field: "sprint_id", Old: id, New: deref(carryOverTo)}})
+		recordActivity(s.activityRepo, activity)
+		notifyWatchers(s.notificationRepo, activity)
+		recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+	}
+
+	closed, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CloseSprintResponse{Sprint: closed, CarriedOver: len(carried), CarriedOverTo: carryOverTo}, nil
+}
+
+// GetSprintTasks lists the tasks of a sprint using the regular task filters
+func (s *SprintService) GetSprintTasks(workspaceID string, id string, filters models.TaskFilters) ([]models.Task, error) {
+	_, err := s.sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found: %w", err)
+	}
+
+	filters.SprintID = id
+	if err := resolveCustomFieldFilters(s.customFieldRepo, workspaceID, &filters); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetTasks(workspaceID, filters)
+}
+
+// checkSprintDates requires a sprint to end after it starts
+func checkSprintDates(start time.Time, end time.Time) error {
+	if !end.After(start) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrInvalidInput)
+	}
+	return nil
+}
@@ -1,123 +1,1048 @@
 package service
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"slices"
+	"time"
 
 	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/recurrence"
 	"github.com/Sasha125588/event_app/internal/repository"
+	"github.com/Sasha125588/event_app/internal/storage"
+	"github.com/google/uuid"
 )
 
-// TaskService handles business logic for tasks and subtasks
+// recurrenceBatchSize caps how many series one sweep rolls over
+const recurrenceBatchSize = 100
+
+// purgeBatchSize caps how many tasks or subtasks one statement of the trash purge removes
+const purgeBatchSize = 100
+
+// TaskService handles business logic for tasks and subtasks.
+// Every method operates inside the workspace passed as its first argument. Changes are recorded
+// in the audit log on behalf of actorID, the user making the request; it may be empty.
+// Each write to a task or its subtasks also stores a revision of the task and notifies its watchers
 type TaskService struct {
-	taskRepo    *repository.TaskRepository
-	subTaskRepo *repository.SubTaskRepository
+	taskRepo         *repository.TaskRepository
+	subTaskRepo      *repository.SubTaskRepository
+	userRepo         *repository.UserRepository
+	labelRepo        *repository.LabelRepository
+	projectRepo      *repository.ProjectRepository
+	attachmentRepo   *repository.AttachmentRepository
+	dependencyRepo   *repository.DependencyRepository
+	seriesRepo       *repository.SeriesRepository
+	customFieldRepo  *repository.CustomFieldRepository
+	sprintRepo       *repository.SprintRepository
+	milestoneRepo    *repository.MilestoneRepository
+	workflowRepo     *repository.WorkflowRepository
+	activityRepo     *repository.ActivityRepository
+	revisionRepo     *repository.RevisionRepository
+	notificationRepo *repository.NotificationRepository
+	fileStore        storage.Storage
+	options          TaskOptions
+}
+
+// TaskOptions tunes the rules TaskService enforces
+type TaskOptions struct {
+	// RejectBlockedCompletion makes UpdateTask refuse to complete a task while any of its blockers is open
+	RejectBlockedCompletion bool
 }
 
 // NewTaskService creates a new instance of TaskService
-func NewTaskService(taskRepo *repository.TaskRepository, subTaskRepo *repository.SubTaskRepository) *TaskService {
+func NewTaskService(taskRepo *repository.TaskRepository, subTaskRepo *repository.SubTaskRepository, userRepo *repository.UserRepository, labelRepo *repository.LabelRepository, projectRepo *repository.ProjectRepository, attachmentRepo *repository.AttachmentRepository, dependencyRepo *repository.DependencyRepository, seriesRepo *repository.SeriesRepository, customFieldRepo *repository.CustomFieldRepository, sprintRepo *repository.SprintRepository, milestoneRepo *repository.MilestoneRepository, workflowRepo *repository.WorkflowRepository, activityRepo *repository.ActivityRepository, revisionRepo *repository.RevisionRepository, notificationRepo *repository.NotificationRepository, fileStore storage.Storage, options TaskOptions) *TaskService {
 	return &TaskService{
-		taskRepo:    taskRepo,
-		subTaskRepo: subTaskRepo,
+		taskRepo:         taskRepo,
+		subTaskRepo:      subTaskRepo,
+		userRepo:         userRepo,
+		labelRepo:        labelRepo,
+		projectRepo:      projectRepo,
+		attachmentRepo:   attachmentRepo,
+		dependencyRepo:   dependencyRepo,
+		seriesRepo:       seriesRepo,
+		customFieldRepo:  customFieldRepo,
+		sprintRepo:       sprintRepo,
+		milestoneRepo:    milestoneRepo,
+		workflowRepo:     workflowRepo,
+		activityRepo:     activityRepo,
+		revisionRepo:     revisionRepo,
+		notificationRepo: notificationRepo,
+		fileStore:        fileStore,
+		options:          options,
 	}
 }
 
-func (s *TaskService) CreateTask(req models.CreateTaskRequest) (*models.Task, error) {
+func (s *TaskService) CreateTask(workspaceID string, actorID string, req models.CreateTaskRequest) (*models.Task, error) {
+	if req.Priority == "" {
+		req.Priority = models.PriorityNone
+	}
+	if !req.Priority.IsValid() {
+		return nil, fmt.Errorf("%w: unknown priority %q", ErrInvalidInput, req.Priority)
+	}
+	workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkStatus(workflow, req.Status); err != nil {
+		return nil, err
+	}
+	if err := checkTaskSchedule(req.StartTime, req.EndTime, req.DueDate); err != nil {
+		return nil, err
+	}
+	if err := checkEffort(req.EstimateHours, req.StoryPoints, req.RemainingHours); err != nil {
+		return nil, err
+	}
+
+	if req.ProjectID != nil {
+		if err := checkProjectExists(s.projectRepo, workspaceID, *req.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+	if req.SprintID != nil {
+		if err := checkSprintOpen(s.sprintRepo, workspaceID, *req.SprintID); err != nil {
+			return nil, err
+		}
+	}
+	if req.MilestoneID != nil {
+		if err := checkMilestoneExists(s.milestoneRepo, workspaceID, *req.MilestoneID); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := resolveUsers(s.userRepo, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	labels, err := resolveLabels(s.labelRepo, workspaceID, req.LabelIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	task := models.NewTask(req)
-	err := s.taskRepo.CreateTask(task)
+	req.CustomFields, err = s.normalizeCustomFields(workspaceID, req.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	task := models.NewTask(workspaceID, req)
+	task.Users = users
+	task.Labels = labels
+	if req.Recurrence != nil && *req.Recurrence != "" {
+		rule, err := parseRecurrence(*req.Recurrence)
+		if err != nil {
+			return nil, err
+		}
+		seriesID := uuid.New().String()
+		task.SeriesID = &seriesID
+		task.Recurrence = &rule
+	}
+	err = s.taskRepo.CreateTask(task)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
 	}
 
-	return s.taskRepo.GetTaskByID(task.ID)
+	created, err := s.taskRepo.GetTaskByID(workspaceID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(models.NewActivity(workspaceID, task.ID, nil, actorID, models.ActionCreated,
+		diffAuditFields(nil, taskAuditFields(created))))
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, task.ID, actorID)
+	notifyAssignees(s.notificationRepo, workspaceID, task.ID, actorID, created.Users)
+
+	return created, nil
+}
+
+// recordChange records a change to a task or subtask in the audit log and notifies the task's watchers
+func (s *TaskService) recordChange(activity *models.Activity) {
+	recordActivity(s.activityRepo, activity)
+	notifyWatchers(s.notificationRepo, activity)
 }
 
-func (s *TaskService) GetTask(id string) (*models.Task, error) {
-	return s.taskRepo.GetTaskByID(id)
+func (s *TaskService) GetTask(workspaceID string, id string) (*models.Task, error) {
+	return s.taskRepo.GetTaskByID(workspaceID, id)
 }
 
-func (s *TaskService) UpdateTask(id string, req models.UpdateTaskRequest) (*models.Task, error) {
-	_, err := s.taskRepo.GetTaskByID(id)
+func (s *TaskService) UpdateTask(workspaceID string, actorID string, id string, req models.UpdateTaskRequest) (*models.Task, error) {
+	task, err := s.updateTask(workspaceID, actorID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, id, actorID)
+
+	return task, nil
+}
+
+// updateTask applies req without storing a revision, so that restoring a revision can store one for all its writes
+func (s *TaskService) updateTask(workspaceID string, actorID string, id string, req models.UpdateTaskRequest) (*models.Task, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, id)
 	if err != nil {
 		return nil, fmt.Errorf("task not found: %w", err)
 	}
 
-	err = s.taskRepo.UpdateTask(id, &req)
+	completing, rule, err := s.checkTaskUpdate(workspaceID, task, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recurrence lives on the series, not on the task row
+	recurrenceUpdate := req.Recurrence
+	req.Recurrence = nil
+	if req != (models.UpdateTaskRequest{}) || recurrenceUpdate == nil {
+		err = s.taskRepo.UpdateTask(workspaceID, id, &req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+	}
+
+	if recurrenceUpdate != nil {
+		if err := s.updateRecurrence(workspaceID, id, rule); err != nil {
+			return nil, fmt.Errorf("failed to update recurrence: %w", err)
+		}
+	}
+
+	updated, err := s.taskRepo.GetTaskByID(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes := diffAuditFields(taskAuditFields(task), taskAuditFields(updated)); len(changes) > 0 {
+		s.recordChange(models.NewActivity(workspaceID, id, nil, actorID, models.ActionUpdated, changes))
+	}
+
+	if completing && task.SeriesID != nil {
+		if err := s.spawnNextOccurrence(workspaceID, *task.SeriesID, id); err != nil {
+			log.Printf("Warning: failed to spawn next occurrence of series %s: %v", *task.SeriesID, err)
+		}
+	}
+
+	return updated, nil
+}
+
+// checkTaskUpdate validates req against the task as it is now, without writing anything, and
+// normalizes its custom fields. It reports whether req completes the task and the parsed recurrence rule
+func (s *TaskService) checkTaskUpdate(workspaceID string, task *models.Task, req *models.UpdateTaskRequest) (bool, string, error) {
+	// Moving into a done status is what the blocked check calls completing
+	completing := false
+	if req.Status != nil {
+		workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+		if err != nil {
+			return false, "", err
+		}
+		if err := checkTransition(workflow, task.Status, *req.Status); err != nil {
+			return false, "", err
+		}
+		status, _ := workflow.Status(*req.Status)
+		completing = status.Category == models.CategoryDone && task.StatusCategory != models.CategoryDone
+	}
+	if completing {
+		if err := s.checkNotBlocked(workspaceID, task.ID); err != nil {
+			return false, "", err
+		}
+	}
+
+	var rule string
+	if req.Recurrence != nil && *req.Recurrence != "" {
+		var err error
+		if rule, err = parseRecurrence(*req.Recurrence); err != nil {
+			return false, "", err
+		}
+	}
+
+	if req.Priority != nil && !req.Priority.IsValid() {
+		return false, "", fmt.Errorf("%w: unknown priority %q", ErrInvalidInput, *req.Priority)
+	}
+	if err := checkEffort(req.EstimateHours, req.StoryPoints, req.RemainingHours); err != nil {
+		return false, "", err
+	}
+
+	// The schedule is only checked when it changes, so tasks with a legacy schedule stay editable
+	if req.StartTime != nil || req.EndTime != nil || req.DueDate != nil || req.ClearSchedule {
+		if req.ClearSchedule && (req.StartTime != nil || req.EndTime != nil) {
+			return false, "", fmt.Errorf("%w: clear_schedule cannot be combined with start_time or end_time", ErrInvalidInput)
+		}
+		start, end, due := task.StartTime, task.EndTime, task.DueDate
+		if req.ClearSchedule {
+			start, end = nil, nil
+		}
+		if req.StartTime != nil {
+			start = req.StartTime
+		}
+		if req.EndTime != nil {
+			end = req.EndTime
+		}
+		if req.DueDate != nil {
+			due = *req.DueDate
+		}
+		if err := checkTaskSchedule(start, end, due); err != nil {
+			return false, "", err
+		}
+	}
+
+	if req.ProjectID != nil && *req.ProjectID != "" {
+		if err := checkProjectExists(s.projectRepo, workspaceID, *req.ProjectID); err != nil {
+			return false, "", err
+		}
+	}
+	if req.SprintID != nil && *req.SprintID != "" {
+		if err := checkSprintOpen(s.sprintRepo, workspaceID, *req.SprintID); err != nil {
+			return false, "", err
+		}
+	}
+	if req.MilestoneID != nil && *req.MilestoneID != "" {
+		if err := checkMilestoneExists(s.milestoneRepo, workspaceID, *req.MilestoneID); err != nil {
+			return false, "", err
+		}
+	}
+
+	if req.CustomFields != nil {
+		customFields, err := s.normalizeCustomFields(workspaceID, *req.CustomFields, false)
+		if err != nil {
+			return false, "", err
+		}
+		req.CustomFields = &customFields
+	}
+
+	return completing, rule, nil
+}
+
+// updateRecurrence starts, changes or (for an empty rule) stops the series of a task
+func (s *TaskService) updateRecurrence(workspaceID string, id string, rule string) error {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, id)
+	if err != nil {
+		return err
+	}
+
+	if rule == "" {
+		if task.SeriesID == nil {
+			return nil
+		}
+		return s.seriesRepo.EndSeries(workspaceID, *task.SeriesID)
+	}
+
+	if task.SeriesID != nil {
+		return s.seriesRepo.UpdateRule(workspaceID, *task.SeriesID, rule)
+	}
+
+	now := time.Now()
+	return s.seriesRepo.StartSeries(&models.TaskSeries{
+		ID:            uuid.New().String(),
+		WorkspaceID:   workspaceID,
+		RRule:         rule,
+		StartDate:     task.DueDate,
+		Occurrences:   1,
+		CurrentTaskID: &task.ID,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+}
+
+// RunRecurrence rolls over overdue occurrences of recurring tasks every interval until ctx is done
+func (s *TaskService) RunRecurrence(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SpawnDueOccurrences(time.Now()); err != nil {
+			log.Printf("Warning: recurrence sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SpawnDueOccurrences creates the next occurrence of every series whose current occurrence
+// was due before now. Safe to run concurrently from several replicas
+func (s *TaskService) SpawnDueOccurrences(now time.Time) error {
+	dueSeries, err := s.seriesRepo.GetDueSeries(now, recurrenceBatchSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update task: %w", err)
+		return err
+	}
+
+	for _, series := range dueSeries {
+		if series.CurrentTaskID == nil {
+			continue
+		}
+		if err := s.spawnNextOccurrence(series.WorkspaceID, series.ID, *series.CurrentTaskID); err != nil {
+			log.Printf("Warning: failed to spawn next occurrence of series %s: %v", series.ID, err)
+		}
 	}
 
-	return s.taskRepo.GetTaskByID(id)
+	return nil
 }
 
-func (s *TaskService) DeleteTask(id string) error {
-	_, err := s.taskRepo.GetTaskByID(id)
+// spawnNextOccurrence copies the current occurrence of a series, with its subtasks reset to
+// the workflow's initial status, to the next date of the rule that lies in the future. Occurrences skipped
+// while the series was overdue still count towards COUNT
+func (s *TaskService) spawnNextOccurrence(workspaceID string, seriesID string, currentTaskID string) error {
+	series, err := s.seriesRepo.GetSeriesByID(workspaceID, seriesID)
+	if err != nil {
+		return err
+	}
+	if !series.Active || series.CurrentTaskID == nil || *series.CurrentTaskID != currentTaskID {
+		return nil
+	}
+
+	current, err := s.taskRepo.GetTaskByID(workspaceID, currentTaskID)
+	if err != nil {
+		return err
+	}
+
+	rule, err := recurrence.Parse(series.RRule)
+	if err != nil {
+		return s.seriesRepo.FinishSeries(workspaceID, seriesID, currentTaskID)
+	}
+
+	workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+	if err != nil {
+		return err
+	}
+	initial := workflow.InitialStatus()
+
+	now := time.Now()
+	due := current.DueDate
+	occurrences := series.Occurrences
+	for {
+		next, ok := rule.Next(series.StartDate, due)
+		occurrences++
+		if !ok || (rule.Count > 0 && occurrences > rule.Count) {
+			return s.seriesRepo.FinishSeries(workspaceID, seriesID, currentTaskID)
+		}
+		due = next
+		if due.After(now) {
+			break
+		}
+	}
+
+	// The schedule keeps its position relative to the due date
+	shift := func(t *time.Time) *time.Time {
+		if t == nil {
+			return nil
+		}
+		shifted := t.Add(due.Sub(current.DueDate))
+		return &shifted
+	}
+
+	task := models.NewTask(workspaceID, models.CreateTaskRequest{
+		ProjectID: current.ProjectID,
+		Title:     current.Title,
+		IconName:  current.IconName,
+		StartTime: shift(current.StartTime),
+		EndTime:   shift(current.EndTime),
+		DueDate:   due,
+		Status:    initial,
+		Priority:  current.Priority,
+
+		EstimateHours: current.EstimateHours,
+		StoryPoints:   current.StoryPoints,
+	})
+	task.SeriesID = &seriesID
+	task.Users = current.Users
+	task.Labels = current.Labels
+	task.CustomFields = current.CustomFields
+	// Subtasks are copied depth-first from the tree so that every parent is inserted before its children
+	var copySubTasks func(subTasks []models.SubTask, parentID *string)
+	copySubTasks = func(subTasks []models.SubTask, parentID *string) {
+		for _, subTask := range subTasks {
+			copied := models.NewSubTask(workspaceID, task.ID, models.CreateSubTaskRequest{
+				ParentSubTaskID: parentID,
+				Title:           subTask.Title,
+				Description:     subTask.Description,
+				Status:          initial,
+				EstimateHours:   subTask.EstimateHours,
+				StoryPoints:     subTask.StoryPoints,
+			})
+			copied.Order = subTask.Order
+			task.SubTasks = append(task.SubTasks, *copied)
+			copySubTasks(subTask.Children, &copied.ID)
+		}
+	}
+	copySubTasks(buildSubTaskTree(current.SubTasks), nil)
+
+	created, err := s.seriesRepo.CreateOccurrence(seriesID, currentTaskID, occurrences, task)
+	if err != nil {
+		return err
+	}
+	if created {
+		s.recordChange(models.NewActivity(workspaceID, task.ID, nil, "", models.ActionCreated,
+			diffAuditFields(nil, taskAuditFields(task))))
+		recordRevision(s.taskRepo, s.revisionRepo, workspaceID, task.ID, "")
+		notifyAssignees(s.notificationRepo, workspaceID, task.ID, "", task.Users)
+	}
+	return nil
+}
+
+// checkTaskSchedule enforces start_time < end_time <= due_date for whichever of start and end are set
+func checkTaskSchedule(start *time.Time, end *time.Time, due time.Time) error {
+	if start != nil && end != nil && !start.Before(*end) {
+		return fmt.Errorf("%w: start_time must be before end_time", ErrInvalidInput)
+	}
+	if end != nil && end.After(due) {
+		return fmt.Errorf("%w: end_time must not be after due_date", ErrInvalidInput)
+	}
+	if start != nil && start.After(due) {
+		return fmt.Errorf("%w: start_time must not be after due_date", ErrInvalidInput)
+	}
+	return nil
+}
+
+// checkEffort rejects negative estimates, story points and remaining hours
+func checkEffort(estimateHours *float64, storyPoints *int, remainingHours *float64) error {
+	if estimateHours != nil && *estimateHours < 0 {
+		return fmt.Errorf("%w: estimate_hours must not be negative", ErrInvalidInput)
+	}
+	if storyPoints != nil && *storyPoints < 0 {
+		return fmt.Errorf("%w: story_points must not be negative", ErrInvalidInput)
+	}
+	if remainingHours != nil && *remainingHours < 0 {
+		return fmt.Errorf("%w: remaining_hours must not be negative", ErrInvalidInput)
+	}
+	return nil
+}
+
+// parseRecurrence validates an RRULE and returns it in canonical form
+func parseRecurrence(value string) (string, error) {
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return rule.String(), nil
+}
+
+// DeleteTask moves a task and its subtasks to the trash. Attachments are kept until the task is purged
+func (s *TaskService) DeleteTask(workspaceID string, actorID string, id string) error {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, id)
 	if err != nil {
 		return fmt.Errorf("task not found: %w", err)
 	}
 
-	return s.taskRepo.DeleteTask(id)
+	if err := s.taskRepo.DeleteTask(workspaceID, id); err != nil {
+		return err
+	}
+	s.recordChange(models.NewActivity(workspaceID, id, nil, actorID, models.ActionDeleted, nil))
+
+	return nil
+}
+
+// RestoreTask takes a task out of the trash together with the subtasks deleted with it
+func (s *TaskService) RestoreTask(workspaceID string, actorID string, id string) (*models.Task, error) {
+	if err := s.taskRepo.RestoreTask(workspaceID, id); err != nil {
+		return nil, fmt.Errorf("task not in the trash: %w", err)
+	}
+	s.recordChange(models.NewActivity(workspaceID, id, nil, actorID, models.ActionRestored, nil))
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, id, actorID)
+
+	return s.taskRepo.GetTaskByID(workspaceID, id)
 }
 
-func (s *TaskService) GetTasks(filters models.TaskFilters) ([]models.Task, error) {
-	return s.taskRepo.GetTasks(filters)
+// GetTrash lists the tasks and subtasks of the workspace that were deleted and can still be restored
+func (s *TaskService) GetTrash(workspaceID string) (*models.TrashResponse, error) {
+	tasks, err := s.taskRepo.GetDeletedTasks(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	subTasks, err := s.subTaskRepo.GetDeletedSubTasks(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TrashResponse{Tasks: tasks, SubTasks: subTasks}, nil
+}
+
+// RunPurge permanently removes trashed tasks and subtasks older than retention every interval until ctx is done.
+// A zero retention keeps the trash forever
+func (s *TaskService) RunPurge(ctx context.Context, interval time.Duration, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.PurgeTrash(time.Now().Add(-retention)); err != nil {
+			log.Printf("Warning: trash purge failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeTrash permanently removes the tasks and subtasks that were moved to the trash before the given time,
+// along with the stored files of their attachments. Safe to run concurrently from several replicas
+func (s *TaskService) PurgeTrash(before time.Time) error {
+	for {
+		storageKeys, purged, err := s.taskRepo.PurgeDeletedTasks(before, purgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to purge tasks: %w", err)
+		}
+
+		// Attachment metadata is removed by ON DELETE CASCADE; the stored files are not
+		for _, key := range storageKeys {
+			if err := s.fileStore.Delete(context.Background(), key); err != nil {
+				log.Printf("Warning: failed to delete stored object %s: %v", key, err)
+			}
+		}
+
+		if purged < purgeBatchSize {
+			break
+		}
+	}
+
+	for {
+		purged, err := s.subTaskRepo.PurgeDeletedSubTasks(before, purgeBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to purge subtasks: %w", err)
+		}
+		if purged < purgeBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *TaskService) GetTasks(workspaceID string, filters models.TaskFilters) ([]models.Task, error) {
+	if err := resolveCustomFieldFilters(s.customFieldRepo, workspaceID, &filters); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetTasks(workspaceID, filters)
+}
+
+// normalizeCustomFields validates custom field values of a task against the workspace's field definitions
+func (s *TaskService) normalizeCustomFields(workspaceID string, values models.CustomFieldValues, creating bool) (models.CustomFieldValues, error) {
+	fields, err := s.customFieldRepo.GetCustomFields(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeCustomFieldValues(fields, s.userRepo, values, creating)
+}
+
+// AssignUser assigns an existing user to an existing task
+func (s *TaskService) AssignUser(workspaceID string, actorID string, taskID string, userID string) (*models.Task, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	user, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if err := s.taskRepo.AssignUser(workspaceID, taskID, userID); err != nil {
+		return nil, fmt.Errorf("failed to assign user: %w", err)
+	}
+	if !slices.ContainsFunc(task.Users, func(assignee models.User) bool { return assignee.ID == userID }) {
+		notifyAssignees(s.notificationRepo, workspaceID, taskID, actorID, []models.User{*user})
+	}
+
+	updated, err := s.recordTaskChange(workspaceID, actorID, task)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+
+	return updated, nil
+}
+
+// UnassignUser removes a user from a task
+func (s *TaskService) UnassignUser(workspaceID string, actorID string, taskID string, userID string) (*models.Task, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	if err := s.taskRepo.UnassignUser(workspaceID, taskID, userID); err != nil {
+		return nil, fmt.Errorf("user is not assigned to the task: %w", err)
+	}
+
+	updated, err := s.recordTaskChange(workspaceID, actorID, task)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+
+	return updated, nil
+}
+
+// recordTaskChange reloads a task after a write outside of UpdateTask and records how it differs from before
+func (s *TaskService) recordTaskChange(workspaceID string, actorID string, before *models.Task) (*models.Task, error) {
+	updated, err := s.taskRepo.GetTaskByID(workspaceID, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	if changes := diffAuditFields(taskAuditFields(before), taskAuditFields(updated)); len(changes) > 0 {
+		s.recordChange(models.NewActivity(workspaceID, before.ID, nil, actorID, models.ActionUpdated, changes))
+	}
+
+	return updated, nil
+}
+
+// checkNotBlocked reports a conflict if completion is gated on dependencies and the task has open blockers
+func (s *TaskService) checkNotBlocked(workspaceID string, id string) error {
+	if !s.options.RejectBlockedCompletion {
+		return nil
+	}
+
+	open, err := s.dependencyRepo.CountOpenBlockers(workspaceID, id)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return fmt.Errorf("%w: task is blocked by %d open tasks", ErrConflict, open)
+	}
+
+	return nil
+}
+
+// resolveUsers loads the users referenced by ids, skipping duplicates.
+// Unknown ids are reported as invalid input
+func resolveUsers(userRepo *repository.UserRepository, ids []string) ([]models.User, error) {
+	seen := make(map[string]bool, len(ids))
+	users := make([]models.User, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		user, err := userRepo.GetUserByID(id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: user %s does not exist", ErrInvalidInput, id)
+			}
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, nil
+}
+
+// checkProjectExists reports unknown project ids as invalid input
+func checkProjectExists(projectRepo *repository.ProjectRepository, workspaceID string, id string) error {
+	_, err := projectRepo.GetProjectByID(workspaceID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: project %s does not exist", ErrInvalidInput, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkSprintOpen reports unknown and closed sprints as invalid input
+func checkSprintOpen(sprintRepo *repository.SprintRepository, workspaceID string, id string) error {
+	sprint, err := sprintRepo.GetSprintByID(workspaceID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: sprint %s does not exist", ErrInvalidInput, id)
+		}
+		return err
+	}
+	if sprint.State == models.SprintClosed {
+		return fmt.Errorf("%w: sprint %s is closed", ErrInvalidInput, id)
+	}
+	return nil
+}
+
+// checkMilestoneExists reports unknown milestone ids as invalid input
+func checkMilestoneExists(milestoneRepo *repository.MilestoneRepository, workspaceID string, id string) error {
+	_, err := milestoneRepo.GetMilestoneByID(workspaceID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: milestone %s does not exist", ErrInvalidInput, id)
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveLabels loads the labels referenced by ids, skipping duplicates.
+// Unknown ids are reported as invalid input
+func resolveLabels(labelRepo *repository.LabelRepository, workspaceID string, ids []string) ([]models.Label, error) {
+	seen := make(map[string]bool, len(ids))
+	labels := make([]models.Label, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		label, err := labelRepo.GetLabelByID(workspaceID, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: label %s does not exist", ErrInvalidInput, id)
+			}
+			return nil, err
+		}
+		labels = append(labels, *label)
+	}
+
+	return labels, nil
 }
 
 // CreateSubTask creates a new subtask for a specific task
-// It validates that the parent task exists before creating the subtask
-func (s *TaskService) CreateSubTask(taskID string, req models.CreateSubTaskRequest) (*models.SubTask, error) {
-	_, err := s.taskRepo.GetTaskByID(taskID)
+// It validates that the parent task and, for nested subtasks, the parent subtask exist before creating the subtask
+func (s *TaskService) CreateSubTask(workspaceID string, actorID string, taskID string, req models.CreateSubTaskRequest) (*models.SubTask, error) {
+	subTask, err := s.createSubTask(workspaceID, actorID, taskID, req)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+
+	return subTask, nil
+}
+
+// createSubTask creates a subtask without storing a revision of its task
+func (s *TaskService) createSubTask(workspaceID string, actorID string, taskID string, req models.CreateSubTaskRequest) (*models.SubTask, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
 	if err != nil {
 		return nil, fmt.Errorf("parent task not found: %w", err)
 	}
 
-	subTask := models.NewSubTask(taskID, req)
+	if err := checkEffort(req.EstimateHours, req.StoryPoints, req.RemainingHours); err != nil {
+		return nil, err
+	}
+
+	workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := checkStatus(workflow, req.Status); err != nil {
+		return nil, err
+	}
+
+	if req.ParentSubTaskID != nil {
+		parent, err := s.subTaskRepo.GetSubTaskByID(workspaceID, *req.ParentSubTaskID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		if parent == nil || parent.TaskID != taskID {
+			return nil, fmt.Errorf("%w: parent subtask %s does not belong to the task", ErrInvalidInput, *req.ParentSubTaskID)
+		}
+	}
+
+	subTask := models.NewSubTask(workspaceID, taskID, req)
 	err = s.subTaskRepo.CreateSubTask(subTask)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create subtask: %w", err)
 	}
 
-	return s.subTaskRepo.GetSubTaskByID(subTask.ID)
+	created, err := s.subTaskRepo.GetSubTaskByID(workspaceID, subTask.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.recordChange(models.NewActivity(workspaceID, taskID, &subTask.ID, actorID, models.ActionCreated,
+		diffAuditFields(nil, subTaskAuditFields(created))))
+
+	if err := s.rollupSubTaskStatus(workspaceID, subTask.ParentSubTaskID); err != nil {
+		return nil, err
+	}
+
+	return s.subTaskRepo.GetSubTaskByID(workspaceID, subTask.ID)
 }
 
 // UpdateSubTask updates an existing subtask
 // It validates that the subtask exists before updating it
-func (s *TaskService) UpdateSubTask(id string, req models.UpdateSubTaskRequest) (*models.SubTask, error) {
-	_, err := s.subTaskRepo.GetSubTaskByID(id)
+func (s *TaskService) UpdateSubTask(workspaceID string, actorID string, id string, req models.UpdateSubTaskRequest) (*models.SubTask, error) {
+	subTask, err := s.updateSubTask(workspaceID, actorID, id, req)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, subTask.TaskID, actorID)
+
+	return subTask, nil
+}
+
+// updateSubTask updates a subtask without storing a revision of its task
+func (s *TaskService) updateSubTask(workspaceID string, actorID string, id string, req models.UpdateSubTaskRequest) (*models.SubTask, error) {
+	subTask, err := s.subTaskRepo.GetSubTaskByID(workspaceID, id)
 	if err != nil {
 		return nil, fmt.Errorf("subtask not found: %w", err)
 	}
 
-	err = s.subTaskRepo.UpdateSubTask(id, &req)
+	if err := checkEffort(req.EstimateHours, req.StoryPoints, req.RemainingHours); err != nil {
+		return nil, err
+	}
+
+	if req.Status != nil {
+		workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkTransition(workflow, subTask.Status, *req.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.subTaskRepo.UpdateSubTask(workspaceID, id, &req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update subtask: %w", err)
 	}
 
-	return s.subTaskRepo.GetSubTaskByID(id)
+	updated, err := s.subTaskRepo.GetSubTaskByID(workspaceID, id)
+	if err != nil {
+		return nil, err
+	}
+	if changes := diffAuditFields(subTaskAuditFields(subTask), subTaskAuditFields(updated)); len(changes) > 0 {
+		s.recordChange(models.NewActivity(workspaceID, subTask.TaskID, &subTask.ID, actorID, models.ActionUpdated, changes))
+	}
+
+	if req.Status != nil {
+		if err := s.rollupSubTaskStatus(workspaceID, subTask.ParentSubTaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.subTaskRepo.GetSubTaskByID(workspaceID, id)
 }
 
-// DeleteSubTask removes a subtask from the database
+// DeleteSubTask moves a subtask and all of its descendants to the trash
 // It validates that the subtask exists before deleting it
-func (s *TaskService) DeleteSubTask(id string) error {
-	_, err := s.subTaskRepo.GetSubTaskByID(id)
+func (s *TaskService) DeleteSubTask(workspaceID string, actorID string, id string) error {
+	subTask, err := s.deleteSubTask(workspaceID, actorID, id)
+	if err != nil {
+		return err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, subTask.TaskID, actorID)
+
+	return nil
+}
+
+// deleteSubTask moves a subtask to the trash without storing a revision of its task and returns it
+func (s *TaskService) deleteSubTask(workspaceID string, actorID string, id string) (*models.SubTask, error) {
+	subTask, err := s.subTaskRepo.GetSubTaskByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("subtask not found: %w", err)
+	}
+
+	if err := s.subTaskRepo.DeleteSubTask(workspaceID, id); err != nil {
+		return nil, err
+	}
+	s.recordChange(models.NewActivity(workspaceID, subTask.TaskID, &subTask.ID, actorID, models.ActionDeleted, nil))
+
+	if err := s.rollupSubTaskStatus(workspaceID, subTask.ParentSubTaskID); err != nil {
+		return nil, err
+	}
+
+	return subTask, nil
+}
+
+// RestoreSubTask takes a subtask of taskID out of the trash together with the descendants deleted with it,
+// back at its original order. Its task and parent subtask must not be in the trash
+func (s *TaskService) RestoreSubTask(workspaceID string, actorID string, taskID string, id string) (*models.SubTask, error) {
+	subTask, err := s.restoreSubTask(workspaceID, actorID, taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+
+	return subTask, nil
+}
+
+// restoreSubTask takes a subtask out of the trash without storing a revision of its task
+func (s *TaskService) restoreSubTask(workspaceID string, actorID string, taskID string, id string) (*models.SubTask, error) {
+	subTask, err := s.subTaskRepo.GetDeletedSubTaskByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("subtask not in the trash: %w", err)
+	}
+	if subTask.TaskID != taskID {
+		return nil, fmt.Errorf("subtask does not belong to the task: %w", sql.ErrNoRows)
+	}
+
+	if _, err := s.taskRepo.GetTaskByID(workspaceID, taskID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: the task is in the trash; restore it first", ErrConflict)
+		}
+		return nil, err
+	}
+	if subTask.ParentSubTaskID != nil {
+		if _, err := s.subTaskRepo.GetSubTaskByID(workspaceID, *subTask.ParentSubTaskID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: parent subtask %s is in the trash; restore it first", ErrConflict, *subTask.ParentSubTaskID)
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.subTaskRepo.RestoreSubTask(workspaceID, id); err != nil {
+		return nil, fmt.Errorf("failed to restore subtask: %w", err)
+	}
+	s.recordChange(models.NewActivity(workspaceID, taskID, &subTask.ID, actorID, models.ActionRestored, nil))
+
+	if err := s.rollupSubTaskStatus(workspaceID, subTask.ParentSubTaskID); err != nil {
+		return nil, err
+	}
+
+	return s.subTaskRepo.GetSubTaskByID(workspaceID, id)
+}
+
+// GetSubTasksByTaskID retrieves all subtasks for a specific task as a flat list
+func (s *TaskService) GetSubTasksByTaskID(workspaceID string, taskID string) ([]models.SubTask, error) {
+	return s.subTaskRepo.GetSubTasksByTaskID(workspaceID, taskID)
+}
+
+// GetSubTaskTree retrieves the subtasks of a task nested under their parents,
+// each level ordered by its order field
+func (s *TaskService) GetSubTaskTree(workspaceID string, taskID string) ([]models.SubTask, error) {
+	subTasks, err := s.subTaskRepo.GetSubTasksByTaskID(workspaceID, taskID)
 	if err != nil {
-		return fmt.Errorf("subtask not found: %w", err)
+		return nil, err
 	}
 
-	return s.subTaskRepo.DeleteSubTask(id)
+	return buildSubTaskTree(subTasks), nil
 }
 
-// GetSubTasksByTaskID retrieves all subtasks for a specific task
-func (s *TaskService) GetSubTasksByTaskID(taskID string) ([]models.SubTask, error) {
-	return s.subTaskRepo.GetSubTasksByTaskID(taskID)
+// buildSubTaskTree nests a flat, order-sorted list of subtasks under their parents
+func buildSubTaskTree(subTasks []models.SubTask) []models.SubTask {
+	children := make(map[string][]models.SubTask)
+	for _, subTask := range subTasks {
+		parent := ""
+		if subTask.ParentSubTaskID != nil {
+			parent = *subTask.ParentSubTaskID
+		}
+		children[parent] = append(children[parent], subTask)
+	}
+
+	var attach func(level []models.SubTask) []models.SubTask
+	attach = func(level []models.SubTask) []models.SubTask {
+		for i := range level {
+			level[i].Children = attach(children[level[i].ID])
+		}
+		return level
+	}
+
+	return attach(children[""])
 }
 
-// ReorderSubTask reorders a subtask within its parent task
+// rollupSubTaskStatus recomputes the statuses of parentID and its ancestors after a child changed
+func (s *TaskService) rollupSubTaskStatus(workspaceID string, parentID *string) error {
+	if parentID == nil {
+		return nil
+	}
+	if err := s.subTaskRepo.RollupStatus(workspaceID, *parentID); err != nil {
+		return fmt.Errorf("failed to roll up subtask status: %w", err)
+	}
+	return nil
+}
+
+// ReorderSubTask reorders a subtask among its siblings under the same parent
 // It validates that the subtask belongs to the specified task before reordering
-func (s *TaskService) ReorderSubTask(taskID string, subTaskID string, newOrder int) error {
+func (s *TaskService) ReorderSubTask(workspaceID string, actorID string, taskID string, subTaskID string, newOrder int) error {
 	// Verify that the task exists
-	_, err := s.taskRepo.GetTaskByID(taskID)
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return fmt.Errorf("task not found")
@@ -126,7 +1051,7 @@ func (s *TaskService) ReorderSubTask(taskID string, subTaskID string, newOrder i
 	}
 
 	// Verify that the subtask exists and belongs to the task
-	subTask, err := s.subTaskRepo.GetSubTaskByID(subTaskID)
+	subTask, err := s.subTaskRepo.GetSubTaskByID(workspaceID, subTaskID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return err
@@ -137,16 +1062,39 @@ func (s *TaskService) ReorderSubTask(taskID string, subTaskID string, newOrder i
 		return fmt.Errorf("subtask does not belong to the specified task")
 	}
 
-	// Get all subtasks to validate the new order
-	subtasks, err := s.subTaskRepo.GetSubTasksByTaskID(taskID)
+	// Get all subtasks to validate the new order against the siblings
+	subtasks, err := s.subTaskRepo.GetSubTasksByTaskID(workspaceID, taskID)
 	if err != nil {
 		return err
 	}
 
+	siblings := 0
+	for _, st := range subtasks {
+		if sameParent(st.ParentSubTaskID, subTask.ParentSubTaskID) {
+			siblings++
+		}
+	}
+
 	// Validate that the new order is within bounds
-	if newOrder < 0 || newOrder >= len(subtasks) {
-		return fmt.Errorf("invalid order: must be between 0 and %d", len(subtasks)-1)
+	if newOrder < 0 || newOrder >= siblings {
+		return fmt.Errorf("invalid order: must be between 0 and %d", siblings-1)
 	}
 
-	return s.subTaskRepo.ReorderSubTask(taskID, subTaskID, newOrder)
+	if err := s.subTaskRepo.ReorderSubTask(workspaceID, taskID, subTaskID, newOrder); err != nil {
+		return err
+	}
+	if newOrder != subTask.Order {
+		s.recordChange(models.NewActivity(workspaceID, taskID, &subTask.ID, actorID, models.ActionReordered,
+			[]models.FieldChange{{Field: "order", Old: subTask.Order, New: newOrder}}))
+		recordRevision(s.taskRepo, s.revisionRepo, workspaceID, taskID, actorID)
+	}
+
+	return nil
+}
+
+func sameParent(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
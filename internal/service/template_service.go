@@ -0,0 +1,369 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const (
+	maxTemplateNameLength = 100
+	// maxTemplateSubTasks caps the subtasks of a template at every depth
+	maxTemplateSubTasks = 200
+)
+
+// TemplateService handles business logic for task templates and creating tasks from them
+type TemplateService struct {
+	templateRepo     *repository.TemplateRepository
+	taskRepo         *repository.TaskRepository
+	userRepo         *repository.UserRepository
+	labelRepo        *repository.LabelRepository
+	projectRepo      *repository.ProjectRepository
+	customFieldRepo  *repository.CustomFieldRepository
+	workflowRepo     *repository.WorkflowRepository
+	activityRepo     *repository.ActivityRepository
+	revisionRepo     *repository.RevisionRepository
+	notificationRepo *repository.NotificationRepository
+}
+
+// NewTemplateService creates a new instance of TemplateService
+func NewTemplateService(templateRepo *repository.TemplateRepository, taskRepo *repository.TaskRepository, userRepo *repository.UserRepository, labelRepo *repository.LabelRepository, projectRepo *repository.ProjectRepository, customFieldRepo *repository.CustomFieldRepository, workflowRepo *repository.WorkflowRepository, activityRepo *repository.ActivityRepository, revisionRepo *repository.RevisionRepository, notificationRepo *repository.NotificationRepository) *TemplateService {
+	return &TemplateService{
+		templateRepo:     templateRepo,
+		taskRepo:         taskRepo,
+		userRepo:         userRepo,
+		labelRepo:        labelRepo,
+		projectRepo:      projectRepo,
+		customFieldRepo:  customFieldRepo,
+		workflowRepo:     workflowRepo,
+		activityRepo:     activityRepo,
+		revisionRepo:     revisionRepo,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (s *TemplateService) CreateTemplate(workspaceID string, req models.CreateTaskTemplateRequest) (*models.TaskTemplate, error) {
+	if req.Priority == "" {
+		req.Priority = models.PriorityNone
+	}
+
+	template := models.NewTaskTemplate(workspaceID, req)
+	labels, err := resolveLabels(s.labelRepo, workspaceID, req.LabelIDs)
+	if err != nil {
+		return nil, err
+	}
+	template.Labels = labels
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.CreateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return s.templateRepo.GetTemplateByID(workspaceID, template.ID)
+}
+
+func (s *TemplateService) GetTemplates(workspaceID string) ([]models.TaskTemplate, error) {
+	return s.templateRepo.GetTemplates(workspaceID)
+}
+
+func (s *TemplateService) GetTemplate(workspaceID string, id string) (*models.TaskTemplate, error) {
+	return s.templateRepo.GetTemplateByID(workspaceID, id)
+}
+
+func (s *TemplateService) UpdateTemplate(workspaceID string, id string, req models.UpdateTaskTemplateRequest) (*models.TaskTemplate, error) {
+	template, err := s.templateRepo.GetTemplateByID(workspaceID, id)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+
+	if req.Name != nil {
+		template.Name = *req.Name
+	}
+	if req.Title != nil {
+		template.Title = *req.Title
+	}
+	if req.IconName != nil {
+		template.IconName = *req.IconName
+	}
+	if req.ProjectID != nil {
+		template.ProjectID = req.ProjectID
+		if *req.ProjectID == "" {
+			template.ProjectID = nil
+		}
+	}
+	if req.Priority != nil {
+		template.Priority = *req.Priority
+	}
+	if req.DueInDays != nil {
+		template.DueInDays = req.DueInDays
+	}
+	if req.LabelIDs != nil {
+		if template.Labels, err = resolveLabels(s.labelRepo, workspaceID, *req.LabelIDs); err != nil {
+			return nil, err
+		}
+	}
+	if req.CustomFields != nil {
+		template.CustomFields = *req.CustomFields
+	}
+	if req.SubTasks != nil {
+		template.SubTasks = *req.SubTasks
+	}
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.UpdatedAt = time.Now()
+	if err := s.templateRepo.SaveTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to update template: %w", err)
+	}
+
+	return s.templateRepo.GetTemplateByID(workspaceID, id)
+}
+
+func (s *TemplateService) DeleteTemplate(workspaceID string, id string) error {
+	_, err := s.templateRepo.GetTemplateByID(workspaceID, id)
+	if err != nil {
+		return fmt.Errorf("template not found: %w", err)
+	}
+
+	return s.templateRepo.DeleteTemplate(workspaceID, id)
+}
+
+// CreateTaskFromTemplate creates a task with the template's subtasks, labels and custom fields
+// in a single transaction. Without a due_date override the task is due due_in_days from now
+func (s *TemplateService) CreateTaskFromTemplate(workspaceID string, actorID string, templateID string, req models.CreateTaskFromTemplateRequest) (*models.Task, error) {
+	template, err := s.templateRepo.GetTemplateByID(workspaceID, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("template not found: %w", err)
+	}
+
+	workflow, err := s.workflowRepo.GetWorkflow(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	taskReq := models.CreateTaskRequest{
+		ProjectID: template.ProjectID,
+		Title:     template.Title,
+		IconName:  template.IconName,
+		Status:    workflow.InitialStatus(),
+		Priority:  template.Priority,
+	}
+
+	if req.Title != nil {
+		taskReq.Title = strings.TrimSpace(*req.Title)
+		if taskReq.Title == "" {
+			return nil, fmt.Errorf("%w: title must not be empty", ErrInvalidInput)
+		}
+	}
+
+	switch {
+	case req.DueDate != nil:
+		taskReq.DueDate = *req.DueDate
+	case template.DueInDays != nil:
+		taskReq.DueDate = time.Now().AddDate(0, 0, *template.DueInDays)
+	default:
+		return nil, fmt.Errorf("%w: due_date is required because the template has no due_in_days", ErrInvalidInput)
+	}
+
+	if req.ProjectID != nil {
+		taskReq.ProjectID = req.ProjectID
+		if *req.ProjectID == "" {
+			taskReq.ProjectID = nil
+		}
+	}
+	if taskReq.ProjectID != nil {
+		if err := checkProjectExists(s.projectRepo, workspaceID, *taskReq.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := resolveUsers(s.userRepo, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Field definitions may have changed since the template was saved
+	fields, err := s.customFieldRepo.GetCustomFields(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	taskReq.CustomFields, err = normalizeCustomFieldValues(fields, s.userRepo, template.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
+	task := models.NewTask(workspaceID, taskReq)
+	task.Users = users
+	task.Labels = template.Labels
+	task.SubTasks = instantiateTemplateSubTasks(workspaceID, task.ID, taskReq.Status, template.SubTasks, nil)
+
+	if err := s.taskRepo.CreateTask(task); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	created, err := s.taskRepo.GetTaskByID(workspaceID, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	recordActivity(s.activityRepo, models.NewActivity(workspaceID, task.ID, nil, actorID, models.ActionCreated,
+		diffAuditFields(nil, taskAuditFields(created))))
+	recordRevision(s.taskRepo, s.revisionRepo, workspaceID, task.ID, actorID)
+	notifyAssignees(s.notificationRepo, workspaceID, task.ID, actorID, created.Users)
+
+	return created, nil
+}
+
+// SaveTaskAsTemplate creates a template from an existing task, its subtask tree, labels and custom fields
+func (s *TemplateService) SaveTaskAsTemplate(workspaceID string, taskID string, req models.SaveTaskAsTemplateRequest) (*models.TaskTemplate, error) {
+	task, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	dueInDays := req.DueInDays
+	if dueInDays == nil {
+		days := int(math.Ceil(task.DueDate.Sub(task.CreatedAt).Hours() / 24))
+		days = max(days, 0)
+		dueInDays = &days
+	}
+
+	subTasks := append([]models.SubTask(nil), task.SubTasks...)
+	sort.SliceStable(subTasks, func(i, j int) bool { return subTasks[i].Order < subTasks[j].Order })
+
+	template := models.NewTaskTemplate(workspaceID, models.CreateTaskTemplateRequest{
+		Name:         req.Name,
+		Title:        task.Title,
+		IconName:     task.IconName,
+		ProjectID:    task.ProjectID,
+		Priority:     task.Priority,
+		DueInDays:    dueInDays,
+		CustomFields: task.CustomFields,
+		SubTasks:     templateSubTasksOf(buildSubTaskTree(subTasks)),
+	})
+	template.Labels = task.Labels
+
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	if err := s.templateRepo.CreateTemplate(template); err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
+	return s.templateRepo.GetTemplateByID(workspaceID, template.ID)
+}
+
+// validateTemplate checks and normalizes a template before it is stored
+func (s *TemplateService) validateTemplate(template *models.TaskTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if template.Name == "" {
+		return fmt.Errorf("%w: template name must not be empty", ErrInvalidInput)
+	}
+	if len([]rune(template.Name)) > maxTemplateNameLength {
+		return fmt.Errorf("%w: template name must be at most %d characters", ErrInvalidInput, maxTemplateNameLength)
+	}
+
+	template.Title = strings.TrimSpace(template.Title)
+	if template.Title == "" {
+		return fmt.Errorf("%w: title must not be empty", ErrInvalidInput)
+	}
+	if !template.Priority.IsValid() {
+		return fmt.Errorf("%w: unknown priority %q", ErrInvalidInput, template.Priority)
+	}
+	if template.DueInDays != nil && *template.DueInDays < 0 {
+		return fmt.Errorf("%w: due_in_days must not be negative", ErrInvalidInput)
+	}
+
+	if template.ProjectID != nil {
+		if err := checkProjectExists(s.projectRepo, template.WorkspaceID, *template.ProjectID); err != nil {
+			return err
+		}
+	}
+
+	fields, err := s.customFieldRepo.GetCustomFields(template.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	customFields, err := normalizeCustomFieldValues(fields, s.userRepo, template.CustomFields, false)
+	if err != nil {
+		return err
+	}
+	for key, value := range customFields {
+		if value == nil {
+			delete(customFields, key)
+		}
+	}
+	template.CustomFields = customFields
+
+	count := 0
+	subTasks, err := normalizeTemplateSubTasks(template.SubTasks, &count)
+	if err != nil {
+		return err
+	}
+	template.SubTasks = subTasks
+
+	return nil
+}
+
+// normalizeTemplateSubTasks trims subtask titles and rejects empty ones, counting subtasks at every depth
+func normalizeTemplateSubTasks(subTasks []models.TemplateSubTask, count *int) ([]models.TemplateSubTask, error) {
+	for i := range subTasks {
+		*count++
+		if *count > maxTemplateSubTasks {
+			return nil, fmt.Errorf("%w: a template can have at most %d subtasks", ErrInvalidInput, maxTemplateSubTasks)
+		}
+
+		subTasks[i].Title = strings.TrimSpace(subTasks[i].Title)
+		if subTasks[i].Title == "" {
+			return nil, fmt.Errorf("%w: subtask title must not be empty", ErrInvalidInput)
+		}
+
+		children, err := normalizeTemplateSubTasks(subTasks[i].Children, count)
+		if err != nil {
+			return nil, err
+		}
+		subTasks[i].Children = children
+	}
+	return subTasks, nil
+}
+
+// instantiateTemplateSubTasks turns a subtask blueprint tree into subtasks of taskID in the given status,
+// listed depth-first so that every parent precedes its children
+func instantiateTemplateSubTasks(workspaceID string, taskID string, status models.TaskStatus, blueprints []models.TemplateSubTask, parentID *string) []models.SubTask {
+	var subTasks []models.SubTask
+	for i, blueprint := range blueprints {
+		subTask := models.NewSubTask(workspaceID, taskID, models.CreateSubTaskRequest{
+			ParentSubTaskID: parentID,
+			Title:           blueprint.Title,
+			Description:     blueprint.Description,
+			Status:          status,
+		})
+		subTask.Order = i
+		subTasks = append(subTasks, *subTask)
+		subTasks = append(subTasks, instantiateTemplateSubTasks(workspaceID, taskID, status, blueprint.Children, &subTask.ID)...)
+	}
+	return subTasks
+}
+
+// templateSubTasksOf converts a subtask tree into template blueprints
+func templateSubTasksOf(subTasks []models.SubTask) []models.TemplateSubTask {
+	blueprints := make([]models.TemplateSubTask, 0, len(subTasks))
+	for _, subTask := range subTasks {
+		blueprints = append(blueprints, models.TemplateSubTask{
+			Title:       subTask.Title,
+			Description: subTask.Description,
+			Children:    templateSubTasksOf(subTask.Children),
+		})
+	}
+	return blueprints
+}
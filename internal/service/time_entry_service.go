@@ -0,0 +1,300 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const maxTimeEntryNoteLength = 1000
+
+// TimeEntryService handles business logic for timers and time entries
+type TimeEntryService struct {
+	timeEntryRepo *repository.TimeEntryRepository
+	taskRepo      *repository.TaskRepository
+	subTaskRepo   *repository.SubTaskRepository
+	userRepo      *repository.UserRepository
+}
+
+// NewTimeEntryService creates a new instance of TimeEntryService
+func NewTimeEntryService(timeEntryRepo *repository.TimeEntryRepository, taskRepo *repository.TaskRepository, subTaskRepo *repository.SubTaskRepository, userRepo *repository.UserRepository) *TimeEntryService {
+	return &TimeEntryService{
+		timeEntryRepo: timeEntryRepo,
+		taskRepo:      taskRepo,
+		subTaskRepo:   subTaskRepo,
+		userRepo:      userRepo,
+	}
+}
+
+// StartTimer starts a running time entry for a user on a task.
+// A user can only have one running timer, so starting a second one is a conflict
+func (s *TimeEntryService) StartTimer(workspaceID string, taskID string, req models.StartTimerRequest) (*models.TimeEntry, error) {
+	if err := s.checkEntryRefs(workspaceID, taskID, req.UserID, req.SubTaskID); err != nil {
+		return nil, err
+	}
+	note, err := normalizeTimeEntryNote(req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.NewTimeEntry(workspaceID, taskID, req.UserID, req.SubTaskID, note, time.Now(), nil)
+	if err := s.timeEntryRepo.CreateTimeEntry(entry); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, fmt.Errorf("%w: user %s already has a running timer", ErrConflict, req.UserID)
+		}
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	return s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, entry.ID)
+}
+
+// StopTimer stops the user's running timer on a task
+func (s *TimeEntryService) StopTimer(workspaceID string, taskID string, req models.StopTimerRequest) (*models.TimeEntry, error) {
+	id, err := s.timeEntryRepo.StopTimer(workspaceID, taskID, req.UserID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("no running timer: %w", err)
+	}
+
+	return s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, id)
+}
+
+// GetRunningTimer returns the running timer of a user in the workspace
+func (s *TimeEntryService) GetRunningTimer(workspaceID string, userID string) (*models.TimeEntry, error) {
+	return s.timeEntryRepo.GetRunningTimeEntry(workspaceID, userID)
+}
+
+// CreateTimeEntry logs a finished span of work manually
+func (s *TimeEntryService) CreateTimeEntry(workspaceID string, taskID string, req models.CreateTimeEntryRequest) (*models.TimeEntry, error) {
+	if err := s.checkEntryRefs(workspaceID, taskID, req.UserID, req.SubTaskID); err != nil {
+		return nil, err
+	}
+	if err := checkTimeEntrySpan(req.StartedAt, &req.EndedAt); err != nil {
+		return nil, err
+	}
+	note, err := normalizeTimeEntryNote(req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := models.NewTimeEntry(workspaceID, taskID, req.UserID, req.SubTaskID, note, req.StartedAt, &req.EndedAt)
+	if err := s.timeEntryRepo.CreateTimeEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	return s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, entry.ID)
+}
+
+// GetTimeEntries lists the time entries of a task overlapping a range
+func (s *TimeEntryService) GetTimeEntries(workspaceID string, taskID string, filters models.TimeRangeFilters) ([]models.TimeEntry, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	from, to, err := parseTimeRange(filters.From, filters.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.timeEntryRepo.GetTimeEntries(workspaceID, taskID, filters.UserID, from, to)
+}
+
+// UpdateTimeEntry edits a time entry. The resulting span must still be valid
+func (s *TimeEntryService) UpdateTimeEntry(workspaceID string, taskID string, id string, req models.UpdateTimeEntryRequest) (*models.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, id)
+	if err != nil {
+		return nil, fmt.Errorf("time entry not found: %w", err)
+	}
+
+	if req.SubTaskID != nil && *req.SubTaskID != "" {
+		if err := s.checkSubTask(workspaceID, taskID, *req.SubTaskID); err != nil {
+			return nil, err
+		}
+	}
+
+	startedAt, endedAt := entry.StartedAt, entry.EndedAt
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	if req.EndedAt != nil {
+		endedAt = req.EndedAt
+	}
+	if err := checkTimeEntrySpan(startedAt, endedAt); err != nil {
+		return nil, err
+	}
+
+	if req.Note != nil {
+		if req.Note, err = normalizeTimeEntryNote(req.Note); err != nil {
+			return nil, err
+		}
+		if req.Note == nil {
+			// An empty note clears the stored one
+			empty := ""
+			req.Note = &empty
+		}
+	}
+
+	if err := s.timeEntryRepo.UpdateTimeEntry(workspaceID, taskID, id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	return s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, id)
+}
+
+// DeleteTimeEntry removes a time entry, running or not
+func (s *TimeEntryService) DeleteTimeEntry(workspaceID string, taskID string, id string) error {
+	_, err := s.timeEntryRepo.GetTimeEntryByID(workspaceID, taskID, id)
+	if err != nil {
+		return fmt.Errorf("time entry not found: %w", err)
+	}
+
+	return s.timeEntryRepo.DeleteTimeEntry(workspaceID, taskID, id)
+}
+
+// GetTaskTotals sums the time spent on a task within a range, in total and per user
+func (s *TimeEntryService) GetTaskTotals(workspaceID string, taskID string, filters models.TimeRangeFilters) (*models.TaskTimeTotals, error) {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	from, to, err := parseTimeRange(filters.From, filters.To)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.timeEntryRepo.GetTaskTotals(workspaceID, taskID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &models.TaskTimeTotals{TaskID: taskID, From: from, To: to, Users: users}
+	for _, user := range users {
+		totals.TotalSeconds += user.Seconds
+	}
+	return totals, nil
+}
+
+// GetUserTotals sums the time a user spent within a range, in total and per task
+func (s *TimeEntryService) GetUserTotals(workspaceID string, userID string, filters models.TimeRangeFilters) (*models.UserTimeTotals, error) {
+	_, err := s.userRepo.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	from, to, err := parseTimeRange(filters.From, filters.To)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.timeEntryRepo.GetUserTotals(workspaceID, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := &models.UserTimeTotals{UserID: userID, From: from, To: to, Tasks: tasks}
+	for _, task := range tasks {
+		totals.TotalSeconds += task.Seconds
+	}
+	return totals, nil
+}
+
+// checkEntryRefs validates the task, user and optional subtask a new entry points at
+func (s *TimeEntryService) checkEntryRefs(workspaceID string, taskID string, userID string, subTaskID *string) error {
+	_, err := s.taskRepo.GetTaskByID(workspaceID, taskID)
+	if err != nil {
+		return fmt.Errorf("task not found: %w", err)
+	}
+
+	if _, err := resolveUsers(s.userRepo, []string{userID}); err != nil {
+		return err
+	}
+
+	if subTaskID != nil {
+		return s.checkSubTask(workspaceID, taskID, *subTaskID)
+	}
+	return nil
+}
+
+// checkSubTask reports a subtask that does not belong to the task as invalid input
+func (s *TimeEntryService) checkSubTask(workspaceID string, taskID string, subTaskID string) error {
+	subTask, err := s.subTaskRepo.GetSubTaskByID(workspaceID, subTaskID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if subTask == nil || subTask.TaskID != taskID {
+		return fmt.Errorf("%w: subtask %s does not belong to the task", ErrInvalidInput, subTaskID)
+	}
+	return nil
+}
+
+// checkTimeEntrySpan rejects entries that end before they start or lie in the future
+func checkTimeEntrySpan(startedAt time.Time, endedAt *time.Time) error {
+	now := time.Now()
+	if startedAt.After(now) {
+		return fmt.Errorf("%w: started_at must not be in the future", ErrInvalidInput)
+	}
+	if endedAt != nil {
+		if !endedAt.After(startedAt) {
+			return fmt.Errorf("%w: ended_at must be after started_at", ErrInvalidInput)
+		}
+		if endedAt.After(now) {
+			return fmt.Errorf("%w: ended_at must not be in the future", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// normalizeTimeEntryNote trims a note, turning a blank one into no note
+func normalizeTimeEntryNote(note *string) (*string, error) {
+	if note == nil {
+		return nil, nil
+	}
+	trimmed := strings.TrimSpace(*note)
+	if trimmed == "" {
+		return nil, nil
+	}
+	if len([]rune(trimmed)) > maxTimeEntryNoteLength {
+		return nil, fmt.Errorf("%w: note must be at most %d characters", ErrInvalidInput, maxTimeEntryNoteLength)
+	}
+	return &trimmed, nil
+}
+
+// parseTimeRange parses optional from and to bounds given as YYYY-MM-DD or RFC 3339.
+// A date-only to includes the whole day
+func parseTimeRange(fromValue string, toValue string) (*time.Time, *time.Time, error) {
+	parse := func(name string, value string, endOfDay bool) (*time.Time, error) {
+		if value == "" {
+			return nil, nil
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t, nil
+		}
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be YYYY-MM-DD or an RFC 3339 timestamp", ErrInvalidInput, name)
+		}
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+
+	from, err := parse("from", fromValue, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parse("to", toValue, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, nil, fmt.Errorf("%w: to must be after from", ErrInvalidInput)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// UserService handles business logic for users
+type UserService struct {
+	userRepo *repository.UserRepository
+}
+
+// NewUserService creates a new instance of UserService
+func NewUserService(userRepo *repository.UserRepository) *UserService {
+	return &UserService{userRepo: userRepo}
+}
+
+func (s *UserService) CreateUser(req models.CreateUserRequest) (*models.User, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+	}
+	if err := validateAvatarSrc(req.Src); err != nil {
+		return nil, err
+	}
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+	req.Email = email
+
+	user := models.NewUser(req)
+	if err := s.userRepo.CreateUser(user); err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return s.userRepo.GetUserByID(user.ID)
+}
+
+func (s *UserService) GetUser(id string) (*models.User, error) {
+	return s.userRepo.GetUserByID(id)
+}
+
+func (s *UserService) GetUsers() ([]models.User, error) {
+	return s.userRepo.GetUsers()
+}
+
+func (s *UserService) UpdateUser(id string, req models.UpdateUserRequest) (*models.User, error) {
+	_, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	if req.Name == nil && req.Src == nil && req.Email == nil {
+		return nil, fmt.Errorf("%w: no fields to update", ErrInvalidInput)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+	if req.Src != nil {
+		if err := validateAvatarSrc(*req.Src); err != nil {
+			return nil, err
+		}
+	}
+	if req.Email != nil {
+		email, err := normalizeEmail(*req.Email)
+		if err != nil {
+			return nil, err
+		}
+		req.Email = &email
+	}
+
+	if err := s.userRepo.UpdateUser(id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	return s.userRepo.GetUserByID(id)
+}
+
+func (s *UserService) DeleteUser(id string) error {
+	_, err := s.userRepo.GetUserByID(id)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	return s.userRepo.DeleteUser(id)
+}
+
+// validateAvatarSrc accepts an empty value or an absolute http(s) URL
+func validateAvatarSrc(src string) error {
+	if src == "" {
+		return nil
+	}
+
+	if _, ok := parseHTTPURL(src); !ok {
+		return fmt.Errorf("%w: src must be an absolute http or https URL", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// normalizeEmail trims an email address and checks it is a bare address such as john@example.com.
+// An empty address is allowed and means the user gets no email
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", nil
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", fmt.Errorf("%w: email must be an address such as john@example.com", ErrInvalidInput)
+	}
+
+	return email, nil
+}
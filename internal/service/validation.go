@@ -0,0 +1,23 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+)
+
+// parseHTTPURL parses raw as an absolute http or https URL.
+// The scheme and host of the returned URL are lower-cased
+func parseHTTPURL(raw string) (*url.URL, bool) {
+	u, err := url.ParseRequestURI(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return nil, false
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, false
+	}
+	u.Host = strings.ToLower(u.Host)
+
+	return u, true
+}
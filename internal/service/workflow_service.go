@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+const (
+	maxStatusKeyLength  = 50
+	maxStatusNameLength = 100
+)
+
+// statusKeyPattern keeps status keys usable in query strings; the built-in keys use dashes
+var statusKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// WorkflowService handles business logic for the workflow of a workspace
+type WorkflowService struct {
+	workflowRepo *repository.WorkflowRepository
+}
+
+// NewWorkflowService creates a new instance of WorkflowService
+func NewWorkflowService(workflowRepo *repository.WorkflowRepository) *WorkflowService {
+	return &WorkflowService{workflowRepo: workflowRepo}
+}
+
+func (s *WorkflowService) GetWorkflow(workspaceID string) (*models.Workflow, error) {
+	return s.workflowRepo.GetWorkflow(workspaceID)
+}
+
+// UpdateWorkflow replaces the statuses and transitions of the workspace's workflow.
+// Every category needs a status, and statuses still used by tasks or subtasks cannot be removed
+func (s *WorkflowService) UpdateWorkflow(workspaceID string, req models.UpdateWorkflowRequest) (*models.Workflow, error) {
+	keys := make(map[models.TaskStatus]bool, len(req.Statuses))
+	categories := make(map[models.StatusCategory]bool)
+	for i, status := range req.Statuses {
+		if len(status.Key) > maxStatusKeyLength || !statusKeyPattern.MatchString(string(status.Key)) {
+			return nil, fmt.Errorf("%w: status key %q must start with a letter and contain only lowercase letters, digits, '-' and '_' (at most %d characters)",
+				ErrInvalidInput, status.Key, maxStatusKeyLength)
+		}
+		if keys[status.Key] {
+			return nil, fmt.Errorf("%w: status %q is listed twice", ErrInvalidInput, status.Key)
+		}
+		keys[status.Key] = true
+
+		name := strings.TrimSpace(status.Name)
+		if name == "" || len(name) > maxStatusNameLength {
+			return nil, fmt.Errorf("%w: name of status %q must be 1-%d characters", ErrInvalidInput, status.Key, maxStatusNameLength)
+		}
+		req.Statuses[i].Name = name
+
+		if !status.Category.IsValid() {
+			return nil, fmt.Errorf("%w: unknown category %q of status %q", ErrInvalidInput, status.Category, status.Key)
+		}
+		categories[status.Category] = true
+	}
+	for _, category := range []models.StatusCategory{models.CategoryTodo, models.CategoryDoing, models.CategoryDone} {
+		if !categories[category] {
+			return nil, fmt.Errorf("%w: the workflow needs at least one %s status", ErrInvalidInput, category)
+		}
+	}
+
+	for _, transition := range req.Transitions {
+		if !keys[transition.From] || !keys[transition.To] {
+			return nil, fmt.Errorf("%w: transition %s -> %s refers to an unknown status", ErrInvalidInput, transition.From, transition.To)
+		}
+		if transition.From == transition.To {
+			return nil, fmt.Errorf("%w: transition from %s to itself", ErrInvalidInput, transition.From)
+		}
+	}
+
+	usage, err := s.workflowRepo.GetStatusUsage(workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for status, count := range usage {
+		if !keys[status] {
+			return nil, fmt.Errorf("%w: status %s is still used by %d tasks or subtasks", ErrConflict, status, count)
+		}
+	}
+
+	err = s.workflowRepo.ReplaceWorkflow(workspaceID, req.Statuses, req.Transitions)
+	if errors.Is(err, repository.ErrInUse) {
+		return nil, fmt.Errorf("%w: a removed status is still in use", ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update workflow: %w", err)
+	}
+
+	return s.workflowRepo.GetWorkflow(workspaceID)
+}
+
+// checkStatus looks up status in the workflow, reporting unknown statuses as invalid input
+func checkStatus(workflow *models.Workflow, status models.TaskStatus) (models.WorkflowStatus, error) {
+	definition, ok := workflow.Status(status)
+	if !ok {
+		return models.WorkflowStatus{}, fmt.Errorf("%w: unknown status %q", ErrInvalidInput, status)
+	}
+	return definition, nil
+}
+
+// checkTransition validates a status change against the workflow's transition rules
+func checkTransition(workflow *models.Workflow, from models.TaskStatus, to models.TaskStatus) error {
+	if _, err := checkStatus(workflow, to); err != nil {
+		return err
+	}
+	if !workflow.CanTransition(from, to) {
+		return fmt.Errorf("%w: status cannot change from %s to %s", ErrConflict, from, to)
+	}
+	return nil
+}
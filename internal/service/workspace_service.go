@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Sasha125588/event_app/internal/models"
+	"github.com/Sasha125588/event_app/internal/repository"
+)
+
+// WorkspaceService handles business logic for workspaces
+type WorkspaceService struct {
+	workspaceRepo *repository.WorkspaceRepository
+}
+
+// NewWorkspaceService creates a new instance of WorkspaceService
+func NewWorkspaceService(workspaceRepo *repository.WorkspaceRepository) *WorkspaceService {
+	return &WorkspaceService{workspaceRepo: workspaceRepo}
+}
+
+func (s *WorkspaceService) CreateWorkspace(req models.CreateWorkspaceRequest) (*models.Workspace, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+	}
+
+	workspace := models.NewWorkspace(req)
+	if err := s.workspaceRepo.CreateWorkspace(workspace); err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	return s.workspaceRepo.GetWorkspaceByID(workspace.ID)
+}
+
+func (s *WorkspaceService) GetWorkspace(id string) (*models.Workspace, error) {
+	return s.workspaceRepo.GetWorkspaceByID(id)
+}
+
+func (s *WorkspaceService) GetWorkspaces() ([]models.Workspace, error) {
+	return s.workspaceRepo.GetWorkspaces()
+}
+
+func (s *WorkspaceService) UpdateWorkspace(id string, req models.UpdateWorkspaceRequest) (*models.Workspace, error) {
+	_, err := s.workspaceRepo.GetWorkspaceByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("workspace not found: %w", err)
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: name must not be empty", ErrInvalidInput)
+		}
+		req.Name = &name
+	}
+
+	if err := s.workspaceRepo.UpdateWorkspace(id, &req); err != nil {
+		return nil, fmt.Errorf("failed to update workspace: %w", err)
+	}
+
+	return s.workspaceRepo.GetWorkspaceByID(id)
+}
+
+// DeleteWorkspace removes an empty workspace. Workspaces that still hold tasks are
+// rejected so that stored attachment files are never orphaned by a cascade
+func (s *WorkspaceService) DeleteWorkspace(id string) error {
+	_, err := s.workspaceRepo.GetWorkspaceByID(id)
+	if err != nil {
+		return fmt.Errorf("workspace not found: %w", err)
+	}
+
+	if id == models.DefaultWorkspaceID {
+		return fmt.Errorf("%w: the default workspace cannot be deleted", ErrConflict)
+	}
+
+	count, err := s.workspaceRepo.CountWorkspaceTasks(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: workspace still has %d tasks", ErrConflict, count)
+	}
+
+	return s.workspaceRepo.DeleteWorkspace(id)
+}